@@ -0,0 +1,138 @@
+package curling
+
+import (
+	"regexp"
+	"strings"
+)
+
+// authPasswordVariable is the shell variable replacing the password sent with -u, --user.
+const authPasswordVariable = "AUTH_PASSWORD"
+
+// An AuthScheme is an HTTP authentication scheme negotiated by cURL.
+type AuthScheme string
+
+const (
+	// AuthDigest is the Digest scheme, set with the flag --digest.
+	AuthDigest AuthScheme = "digest"
+
+	// AuthNTLM is the NTLM scheme, set with the flag --ntlm.
+	AuthNTLM AuthScheme = "ntlm"
+
+	// AuthNegotiate is the Negotiate (SPNEGO) scheme, set with the flag --negotiate.
+	AuthNegotiate AuthScheme = "negotiate"
+
+	// AuthAny lets cURL pick the most secure scheme offered by the server, set with the flag --anyauth.
+	AuthAny AuthScheme = "anyauth"
+)
+
+// authSchemeFlags holds the flag of each [AuthScheme].
+var authSchemeFlags = map[AuthScheme]flag{
+	AuthDigest:    flagDigest,
+	AuthNTLM:      flagNTLM,
+	AuthNegotiate: flagNegotiate,
+	AuthAny:       flagAnyAuth,
+}
+
+// authHeaderSchemes holds the scheme suggested by each Authorization header scheme
+// that is computed from a server challenge, and so can't be replayed as a header.
+var authHeaderSchemes = map[string]AuthScheme{
+	"digest":    AuthDigest,
+	"ntlm":      AuthNTLM,
+	"negotiate": AuthNegotiate,
+}
+
+// digestUsername matches the username parameter of a Digest Authorization header.
+var digestUsername = regexp.MustCompile(`(?i)\busername\s*=\s*(?:"((?:[^"\\]|\\.)*)"|([^,\s]+))`)
+
+// WithAuthScheme sets the flag --digest, --ntlm, --negotiate or --anyauth along
+// with -u, --user, so that cURL authenticates with scheme instead of sending the
+// Authorization header of the request, which answers a challenge that can't be replayed.
+// The credentials are the ones of [WithAuthUser], otherwise the ones of a basic
+// Authorization header or the username of a Digest one; Negotiate uses none.
+// The password is rendered as the AUTH_PASSWORD placeholder (see [WithPlaceholders]);
+// without one, cURL prompts for it.
+// An unknown scheme will be silently ignored.
+func WithAuthScheme(scheme AuthScheme) Option {
+	return func(curling *Command) {
+		if _, ok := authSchemeFlags[scheme]; !ok {
+			return
+		}
+
+		curling.authScheme = scheme
+	}
+}
+
+// WithAuthUser sets the credentials sent with -u, --user by [WithAuthScheme].
+// An empty user will be silently ignored.
+func WithAuthUser(user, password string) Option {
+	return func(curling *Command) {
+		if user == "" {
+			return
+		}
+
+		curling.authUser = user
+		curling.authPassword = password
+	}
+}
+
+// applyAuthScheme moves the credentials of the request to the -u, --user option
+// when an auth scheme is set, dropping the Authorization header. Otherwise,
+// an Authorization header that can't be replayed is reported with a warning
+// suggesting the auth scheme to use.
+func (c *Command) applyAuthScheme() {
+	authorization := c.request.header.Get("Authorization")
+	headerScheme, credentials, _ := strings.Cut(strings.TrimSpace(authorization), " ")
+	headerScheme = strings.ToLower(headerScheme)
+
+	if c.authScheme == "" {
+		if scheme, ok := authHeaderSchemes[headerScheme]; ok {
+			c.warn(WarningAuthScheme, "Authorization header of the %s scheme answers a server challenge and can't be replayed, use the %s auth scheme", headerScheme, scheme)
+		}
+		return
+	}
+
+	c.request.header.Del("Authorization")
+
+	switch {
+	case c.authUser != "":
+		c.credentialUser, c.credentialPassword = c.authUser, c.authPassword
+	case c.authScheme == AuthNegotiate:
+		return
+	case headerScheme == "basic":
+		c.credentialUser, c.credentialPassword, _ = basicAuth(authorization)
+	case headerScheme == "digest":
+		if m := digestUsername.FindStringSubmatch(credentials); m != nil {
+			c.credentialUser = strings.ReplaceAll(m[1], `\"`, `"`) + m[2]
+		}
+	}
+}
+
+// redactAuthPassword replaces the password sent with -u, --user with the AUTH_PASSWORD placeholder.
+func (c *Command) redactAuthPassword() {
+	if c.credentialUser == "" || c.credentialPassword == "" {
+		return
+	}
+
+	c.redactSecret(authPasswordVariable, &c.credentialPassword)
+}
+
+// authOptions returns the options selecting the auth scheme and its credentials.
+// Negotiate without credentials sends the empty user, so that cURL enables it.
+func (c *Command) authOptions() []word {
+	if c.authScheme == "" {
+		return nil
+	}
+
+	s := []word{literal(c.option(authSchemeFlags[c.authScheme]))}
+
+	switch {
+	case c.credentialUser != "" && c.credentialPassword != "":
+		s = append(s, literal(c.option(flagUser)), value(c.credentialUser+":"+c.credentialPassword))
+	case c.credentialUser != "":
+		s = append(s, literal(c.option(flagUser)), value(c.credentialUser))
+	case c.authScheme == AuthNegotiate:
+		s = append(s, literal(c.option(flagUser)), value(":"))
+	}
+
+	return s
+}
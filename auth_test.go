@@ -0,0 +1,120 @@
+package curling
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithAuthScheme(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		opts          []Option
+		want          string
+		wantWarning   bool
+	}{
+		{
+			name:          "digest with basic credentials",
+			authorization: basicAuthHeader("alice", "s3cr3t"),
+			opts:          []Option{WithAuthScheme(AuthDigest)},
+			want: "export AUTH_PASSWORD='********'\n" +
+				"curl --digest -u 'alice:'\"${AUTH_PASSWORD}\" -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:          "digest with digest header",
+			authorization: `Digest username="alice", realm="api", nonce="abc", uri="/test", response="0123"`,
+			opts:          []Option{WithAuthScheme(AuthDigest), WithLongForm()},
+			want:          "curl --digest --user 'alice' --request 'GET' 'https://localhost/test'",
+		},
+		{
+			name:          "ntlm with auth user",
+			authorization: "NTLM TlRMTVNTUAADAAAA",
+			opts:          []Option{WithAuthScheme(AuthNTLM), WithAuthUser(`CORP\alice`, "")},
+			want:          "curl --ntlm -u 'CORP\\alice' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:          "negotiate",
+			authorization: "Negotiate YIIGhgYGKwYBBQUC",
+			opts:          []Option{WithAuthScheme(AuthNegotiate)},
+			want:          "curl --negotiate -u ':' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "anyauth without credentials",
+			opts: []Option{WithAuthScheme(AuthAny)},
+			want: "curl --anyauth -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:          "digest header without auth scheme",
+			authorization: `Digest username="alice", response="0123"`,
+			want:          "curl -X 'GET' 'https://localhost/test' -H 'Authorization: Digest username=\"alice\", response=\"0123\"'",
+			wantWarning:   true,
+		},
+		{
+			name:          "unknown scheme",
+			authorization: "Bearer token",
+			opts:          []Option{WithAuthScheme("kerberos"), WithAuthUser("", "s3cr3t")},
+			want:          "curl -X 'GET' 'https://localhost/test' -H 'Authorization: Bearer token'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			var got bool
+			for _, w := range c.Validate() {
+				got = got || w.Code == WarningAuthScheme
+			}
+			if got != tt.wantWarning {
+				t.Errorf("Validate() has %s = %v, want %v", WarningAuthScheme, got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestWithAuthScheme_quoter(t *testing.T) {
+	tests := []struct {
+		name   string
+		quoter Quoter
+		want   string
+	}{
+		{
+			name:   "powershell",
+			quoter: PowerShellQuoter,
+			want:   "$env:AUTH_PASSWORD = '********'\ncurl --digest -u \"alice:${env:AUTH_PASSWORD}\" -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:   "custom",
+			quoter: QuoterFunc(func(s string) string { return "<" + s + ">" }),
+			want:   "export AUTH_PASSWORD=<********>\ncurl --digest -u <alice:${AUTH_PASSWORD}> -X <GET> <https://localhost/test>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test",
+				WithAuthScheme(AuthDigest), WithAuthUser("alice", "hunter2"), WithQuoter(tt.quoter))
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			if got := c.Args(); got[3] != "alice:hunter2" {
+				t.Errorf("Args()[3] = %q, want %q", got[3], "alice:hunter2")
+			}
+		})
+	}
+}
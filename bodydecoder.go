@@ -0,0 +1,98 @@
+package curling
+
+import (
+	"mime"
+	"strings"
+	"sync"
+)
+
+// A BodyDecoder turns a body of a vendor format into a readable representation.
+type BodyDecoder func(body []byte) (string, error)
+
+var (
+	bodyDecodersMu sync.RWMutex
+	bodyDecoders   = make(map[string]BodyDecoder)
+)
+
+// RegisterBodyDecoder registers fn as the decoder of the binary bodies declared
+// with contentType, such as msgpack or CBOR. The readable representation it returns
+// is rendered as comments above the command, which still sends the body as it is.
+// A later registration for the same content type replaces
+// the earlier one. RegisterBodyDecoder panics if contentType is empty or fn is nil.
+func RegisterBodyDecoder(contentType string, fn func([]byte) (string, error)) {
+	mediaType := normalizeMediaType(contentType)
+	if mediaType == "" {
+		panic("curling: RegisterBodyDecoder with an empty content type")
+	}
+	if fn == nil {
+		panic("curling: RegisterBodyDecoder with a nil decoder for " + mediaType)
+	}
+
+	bodyDecodersMu.Lock()
+	defer bodyDecodersMu.Unlock()
+
+	bodyDecoders[mediaType] = fn
+}
+
+// lookupBodyDecoder returns the decoder registered for contentType, if any.
+func lookupBodyDecoder(contentType string) (BodyDecoder, bool) {
+	bodyDecodersMu.RLock()
+	defer bodyDecodersMu.RUnlock()
+
+	fn, ok := bodyDecoders[normalizeMediaType(contentType)]
+
+	return fn, ok
+}
+
+// normalizeMediaType returns the lowercase media type of contentType, without parameters.
+func normalizeMediaType(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+
+	mediaType, _, _ := strings.Cut(contentType, ";")
+
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// decodeVendorBody stores the readable representation of a binary body
+// produced by the decoder registered for its content type.
+// A failing decoder is reported as a warning and leaves the body as it is.
+func (c *Command) decodeVendorBody() {
+	if !c.request.hasBody || isText(c.request.body) {
+		return
+	}
+
+	contentType := c.request.header.Get("Content-Type")
+
+	fn, ok := lookupBodyDecoder(contentType)
+	if !ok {
+		return
+	}
+
+	s, err := fn(c.request.body)
+	if err != nil {
+		c.warn(WarningBodyDecoder, "%s body can't be decoded: %v", normalizeMediaType(contentType), err)
+		return
+	}
+
+	c.decodedBody = s
+	c.decodedBodyType = normalizeMediaType(contentType)
+}
+
+// decodedBodyComment returns the readable representation of the body as comment lines,
+// empty when the body wasn't decoded.
+func (c *Command) decodedBodyComment() string {
+	if c.decodedBodyType == "" {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString(c.commentPrefix() + " Body (" + c.decodedBodyType + "):\n")
+	for _, line := range strings.Split(strings.TrimRight(c.decodedBody, "\n"), "\n") {
+		b.WriteString(strings.TrimRight(c.commentPrefix()+" "+line, " ") + "\n")
+	}
+
+	return b.String()
+}
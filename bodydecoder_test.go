@@ -0,0 +1,106 @@
+package curling
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestRegisterBodyDecoder(t *testing.T) {
+	RegisterBodyDecoder("Application/X-Curling-Test; version=1", func(b []byte) (string, error) {
+		if len(b) == 0 || b[0] != 0x01 {
+			return "", errors.New("unknown version")
+		}
+		return fmt.Sprintf("{\n  \"size\": %d\n}", len(b)), nil
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		want        string
+		wantWarning bool
+	}{
+		{
+			name:        "decoded",
+			contentType: "application/x-curling-test",
+			body:        []byte{0x01, 0x00, 0xff},
+			want:        "# Body (application/x-curling-test):\n# {\n#   \"size\": 3\n# }\ncurl -X 'POST' 'https://localhost/rpc' -H 'Content-Type: application/x-curling-test' --data-binary '@body.bin'",
+		},
+		{
+			name:        "decoder error",
+			contentType: "application/x-curling-test",
+			body:        []byte{0x02, 0x00, 0xff},
+			want:        "curl -X 'POST' 'https://localhost/rpc' -H 'Content-Type: application/x-curling-test' --data-binary '@body.bin'",
+			wantWarning: true,
+		},
+		{
+			name:        "text body",
+			contentType: "application/x-curling-test",
+			body:        []byte("plain"),
+			want:        "curl -X 'POST' 'https://localhost/rpc' -H 'Content-Type: application/x-curling-test' -d 'plain'",
+		},
+		{
+			name:        "without decoder",
+			contentType: "application/cbor",
+			body:        []byte{0x01, 0x00, 0xff},
+			want:        "curl -X 'POST' 'https://localhost/rpc' -H 'Content-Type: application/cbor' --data-binary '@body.bin'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "https://localhost/rpc", bytes.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header.Set("Content-Type", tt.contentType)
+
+			c, err := NewFromRequest(r, WithOutputVersion(OutputVersion2))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			var got bool
+			for _, w := range c.Validate() {
+				got = got || w.Code == WarningBodyDecoder
+			}
+			if got != tt.wantWarning {
+				t.Errorf("Validate() has %s = %v, want %v", WarningBodyDecoder, got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestRegisterBodyDecoder_panics(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		fn          func([]byte) (string, error)
+	}{
+		{
+			name: "empty content type",
+			fn:   func([]byte) (string, error) { return "", nil },
+		},
+		{
+			name:        "nil decoder",
+			contentType: "application/msgpack",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("RegisterBodyDecoder() didn't panic")
+				}
+			}()
+
+			RegisterBodyDecoder(tt.contentType, tt.fn)
+		})
+	}
+}
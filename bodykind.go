@@ -0,0 +1,127 @@
+package curling
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// A BodyKind is the format of a request body.
+type BodyKind string
+
+const (
+	// BodyKindNone is the kind of requests without a body.
+	BodyKindNone BodyKind = ""
+
+	// BodyKindJSON is the kind of JSON bodies.
+	BodyKindJSON BodyKind = "json"
+
+	// BodyKindGraphQL is the kind of GraphQL queries, either raw or wrapped in JSON.
+	BodyKindGraphQL BodyKind = "graphql"
+
+	// BodyKindXML is the kind of XML bodies.
+	BodyKindXML BodyKind = "xml"
+
+	// BodyKindForm is the kind of url-encoded forms.
+	BodyKindForm BodyKind = "form"
+
+	// BodyKindMultipart is the kind of multipart bodies.
+	BodyKindMultipart BodyKind = "multipart"
+
+	// BodyKindProtobuf is the kind of protocol buffers messages.
+	BodyKindProtobuf BodyKind = "protobuf"
+
+	// BodyKindBinary is the kind of any other non-text body.
+	BodyKindBinary BodyKind = "binary"
+
+	// BodyKindText is the kind of any other text body.
+	BodyKindText BodyKind = "text"
+)
+
+// BodyKind returns the format of the request body, detected from the declared
+// Content-Type and from the content itself.
+func (c *Command) BodyKind() BodyKind {
+	return c.bodyKind
+}
+
+// detectBodyKind returns the format of the body of p.
+// The declared Content-Type takes precedence, content sniffing is used
+// when it is missing or generic.
+func detectBodyKind(p *parsedRequest) BodyKind {
+	if !p.hasBody {
+		return BodyKindNone
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(p.header.Get("Content-Type"))
+
+	switch {
+	case mediaType == "application/graphql":
+		return BodyKindGraphQL
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if isGraphQLRequest(p.body) {
+			return BodyKindGraphQL
+		}
+		return BodyKindJSON
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return BodyKindXML
+	case isFormContentType(mediaType):
+		return BodyKindForm
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return BodyKindMultipart
+	case strings.Contains(mediaType, "protobuf") || strings.HasPrefix(mediaType, "application/grpc"):
+		return BodyKindProtobuf
+	}
+
+	return sniffBodyKind(p.body)
+}
+
+// sniffBodyKind returns the format of body detected from its content.
+func sniffBodyKind(body []byte) BodyKind {
+	if !isText(body) {
+		return BodyKindBinary
+	}
+
+	trimmed := bytes.TrimSpace(body)
+
+	switch {
+	case len(trimmed) == 0:
+		return BodyKindText
+	case json.Valid(trimmed):
+		if isGraphQLRequest(trimmed) {
+			return BodyKindGraphQL
+		}
+		return BodyKindJSON
+	case trimmed[0] == '<' && trimmed[len(trimmed)-1] == '>':
+		return BodyKindXML
+	case isFormBody(trimmed):
+		return BodyKindForm
+	}
+
+	return BodyKindText
+}
+
+// isGraphQLRequest reports whether body is a JSON object carrying a GraphQL query.
+func isGraphQLRequest(body []byte) bool {
+	var v struct {
+		Query *string `json:"query"`
+	}
+
+	if err := json.Unmarshal(body, &v); err != nil {
+		return false
+	}
+
+	return v.Query != nil
+}
+
+// isFormBody reports whether body looks like url-encoded key-value pairs.
+func isFormBody(body []byte) bool {
+	s := string(body)
+	if !strings.Contains(s, "=") || strings.ContainsAny(s, " \t\r\n") {
+		return false
+	}
+
+	_, err := url.ParseQuery(s)
+	return err == nil
+}
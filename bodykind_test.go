@@ -0,0 +1,160 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_BodyKind(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        *string
+		want        BodyKind
+	}{
+		{
+			name: "no body",
+			want: BodyKindNone,
+		},
+		{
+			name:        "declared json",
+			contentType: "application/json; charset=utf-8",
+			body:        ptr(`{"key":"value"}`),
+			want:        BodyKindJSON,
+		},
+		{
+			name:        "declared vendor json",
+			contentType: "application/vnd.api+json",
+			body:        ptr(`[]`),
+			want:        BodyKindJSON,
+		},
+		{
+			name:        "declared json with graphql query",
+			contentType: "application/json",
+			body:        ptr(`{"query":"{ me { id } }","variables":{}}`),
+			want:        BodyKindGraphQL,
+		},
+		{
+			name:        "declared graphql",
+			contentType: "application/graphql",
+			body:        ptr(`{ me { id } }`),
+			want:        BodyKindGraphQL,
+		},
+		{
+			name:        "declared xml",
+			contentType: "application/soap+xml",
+			body:        ptr(`<a/>`),
+			want:        BodyKindXML,
+		},
+		{
+			name:        "declared form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        ptr(`a=1`),
+			want:        BodyKindForm,
+		},
+		{
+			name:        "declared multipart",
+			contentType: "multipart/form-data; boundary=x",
+			body:        ptr(`--x--`),
+			want:        BodyKindMultipart,
+		},
+		{
+			name:        "declared protobuf",
+			contentType: "application/x-protobuf",
+			body:        ptr("\x08\x96\x01"),
+			want:        BodyKindProtobuf,
+		},
+		{
+			name:        "declared grpc",
+			contentType: "application/grpc+proto",
+			body:        ptr("\x00"),
+			want:        BodyKindProtobuf,
+		},
+		{
+			name: "sniffed json",
+			body: ptr(` {"key":"value"} `),
+			want: BodyKindJSON,
+		},
+		{
+			name: "sniffed graphql",
+			body: ptr(`{"query":"{ me { id } }"}`),
+			want: BodyKindGraphQL,
+		},
+		{
+			name:        "sniffed xml",
+			contentType: "text/plain",
+			body:        ptr(`<?xml version="1.0"?><a/>`),
+			want:        BodyKindXML,
+		},
+		{
+			name: "sniffed form",
+			body: ptr(`a=1&b=2`),
+			want: BodyKindForm,
+		},
+		{
+			name: "sniffed binary",
+			body: ptr("\x89PNG\r\n\x1a\n"),
+			want: BodyKindBinary,
+		},
+		{
+			name: "sniffed text",
+			body: ptr(`hello world`),
+			want: BodyKindText,
+		},
+		{
+			name: "empty body",
+			body: ptr(``),
+			want: BodyKindText,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{
+				Method: http.MethodPost,
+				URL:    &url.URL{Scheme: "https", Host: "localhost"},
+				Header: http.Header{},
+			}
+
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			if tt.body != nil {
+				r.Body = readCloser(*tt.body)
+			}
+
+			c, err := NewFromRequest(r)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.BodyKind(); got != tt.want {
+				t.Errorf("BodyKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_String_bodyKindComment(t *testing.T) {
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Scheme: "https", Host: "localhost"},
+		Body:   readCloser(`{"key":"value"}`),
+	}
+
+	c, err := NewFromRequest(r, WithBodyKindComment())
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	want := "# Body: json\ncurl -X 'POST' 'https://localhost' -d '{\"key\":\"value\"}'"
+	if got := c.String(); got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
+
+// ptr returns a pointer to v.
+func ptr[T any](v T) *T {
+	return &v
+}
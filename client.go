@@ -0,0 +1,147 @@
+package curling
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// defaultMaxRedirects is the number of redirects followed by clients without CheckRedirect.
+const defaultMaxRedirects = 10
+
+// The names of the files referenced in place of the TLS material held in memory.
+const (
+	clientCertFile = "client.crt"
+	clientKeyFile  = "client.key"
+	caCertFile     = "ca.crt"
+)
+
+// NewFromRequestWithClient returns a new [Command] that reads from r, with the
+// options derived from the configuration of the client that sends it, so that
+// the command behaves as the client does:
+//   - the Timeout sets -m, --max-time, rounded up to the second with
+//     OutputVersion1 and with sub-second precision with OutputVersion2;
+//   - without CheckRedirect, redirects are followed with -L, --location and
+//     --max-redirs 10, as the default policy does; a custom CheckRedirect is
+//     never called, so its limit must be set with [WithMaxRedirects];
+//   - the proxy chosen by the Transport for r sets -x, --proxy;
+//   - InsecureSkipVerify sets -k, --insecure;
+//   - client certificates and root CAs set --cert, --key and --cacert.
+//
+// Certificates are held in memory, so they are referenced as client.crt,
+// client.key and ca.crt, files that must be provided, as reported by [Command.Validate].
+// Only [http.Transport], possibly wrapped by [Transport], is inspected.
+// The opts are applied after the derived options, so they take precedence.
+// A nil client means [http.DefaultClient].
+// If the command can't be built, NewFromRequestWithClient returns an error.
+func NewFromRequestWithClient(r *http.Request, client *http.Client, opts ...Option) (*Command, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return NewFromRequest(r, append(clientOptions(r, client), opts...)...)
+}
+
+// clientOptions returns the options reproducing the behavior of client for r.
+func clientOptions(r *http.Request, client *http.Client) []Option {
+	var opts []Option
+
+	if client.Timeout > 0 {
+		opts = append(opts, withClientTimeout(client.Timeout))
+	}
+
+	if client.CheckRedirect == nil {
+		opts = append(opts, WithMaxRedirects(defaultMaxRedirects))
+	}
+
+	t := clientTransport(client)
+	if t == nil {
+		return opts
+	}
+
+	if t.Proxy != nil && r.URL != nil {
+		proxy, err := t.Proxy(r)
+		switch {
+		case err != nil:
+			opts = append(opts, withIssue(fmt.Errorf("resolving proxy: %w", err)))
+		case proxy != nil:
+			opts = append(opts, WithProxy(proxy.String()))
+		}
+	}
+
+	if tlsConfig := t.TLSClientConfig; tlsConfig != nil {
+		if tlsConfig.InsecureSkipVerify {
+			opts = append(opts, WithInsecure())
+		}
+
+		if len(tlsConfig.Certificates) > 0 || tlsConfig.GetClientCertificate != nil {
+			opts = append(opts, withClientCertInMemory())
+		}
+
+		if tlsConfig.RootCAs != nil {
+			opts = append(opts, withCACertInMemory())
+		}
+	}
+
+	return opts
+}
+
+// withClientTimeout sets the timeout of the client the command is derived from.
+func withClientTimeout(d time.Duration) Option {
+	return func(curling *Command) {
+		curling.clientTimeout = d
+	}
+}
+
+// applyClientTimeout sets the request timeout to the client timeout, unless the
+// options set one. OutputVersion1 rounds it up to the second.
+func (c *Command) applyClientTimeout() {
+	if c.clientTimeout <= 0 || c.requestTimeout > 0 {
+		return
+	}
+
+	c.requestTimeout = c.clientTimeout
+	if c.version() < OutputVersion2 {
+		c.requestTimeout = time.Duration(math.Ceil(c.clientTimeout.Seconds())) * time.Second
+	}
+}
+
+// clientTransport returns the [http.Transport] of client, unwrapping [Transport],
+// or nil when the client sends requests with another RoundTripper.
+func clientTransport(client *http.Client) *http.Transport {
+	rt := client.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	for {
+		wrapper, ok := rt.(*Transport)
+		if !ok {
+			break
+		}
+		rt = wrapper.base()
+	}
+
+	t, _ := rt.(*http.Transport)
+	return t
+}
+
+// withClientCertInMemory sets the flags --cert and --key, referencing the client
+// certificate held in memory as files that must be provided.
+func withClientCertInMemory() Option {
+	return func(curling *Command) {
+		curling.certFile = clientCertFile
+		curling.keyFile = clientKeyFile
+		curling.certInMemory = true
+	}
+}
+
+// withCACertInMemory sets the flag --cacert, referencing the root CAs held
+// in memory as a file that must be provided.
+func withCACertInMemory() Option {
+	return func(curling *Command) {
+		curling.caCertFile = caCertFile
+		curling.caCertInMemory = true
+	}
+}
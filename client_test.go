@@ -0,0 +1,142 @@
+package curling
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewFromRequestWithClient(t *testing.T) {
+	proxy, _ := url.Parse("http://proxy.internal:3128")
+
+	tests := []struct {
+		name         string
+		client       *http.Client
+		opts         []Option
+		want         string
+		wantWarnings []Warning
+	}{
+		{
+			name:   "default client",
+			client: &http.Client{Transport: &http.Transport{}},
+			want:   "curl -L --max-redirs 10 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "timeout rounded up",
+			client: &http.Client{
+				Transport: &http.Transport{},
+				Timeout:   2500 * time.Millisecond,
+			},
+			want: "curl -m 3 -L --max-redirs 10 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "fractional timeout",
+			client: &http.Client{
+				Transport: &http.Transport{},
+				Timeout:   2500 * time.Millisecond,
+			},
+			opts: []Option{WithOutputVersion(OutputVersion2)},
+			want: "curl -m 2.5 -L --max-redirs 10 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "redirects disabled",
+			client: &http.Client{
+				Transport: &http.Transport{},
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "custom redirect policy",
+			client: &http.Client{
+				Transport: &http.Transport{},
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					panic("CheckRedirect called")
+				},
+			},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "explicit redirect limit",
+			client: &http.Client{
+				Transport: &http.Transport{},
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					panic("CheckRedirect called")
+				},
+			},
+			opts: []Option{WithMaxRedirects(3)},
+			want: "curl -L --max-redirs 3 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "proxy",
+			client: &http.Client{
+				Transport: &http.Transport{Proxy: http.ProxyURL(proxy)},
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			},
+			want: "curl -x 'http://proxy.internal:3128' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "tls",
+			client: &http.Client{
+				Transport: &http.Transport{TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+					Certificates:       []tls.Certificate{{}},
+					RootCAs:            x509.NewCertPool(),
+				}},
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			},
+			want: "curl -k -E 'client.crt' --key 'client.key' --cacert 'ca.crt' -X 'GET' 'https://localhost/test'",
+			wantWarnings: []Warning{
+				{Code: WarningTLSInMemory, Message: "client certificate is held in memory, save it as client.crt and client.key to replay the command"},
+				{Code: WarningTLSInMemory, Message: "root CAs are held in memory, save them as ca.crt to replay the command"},
+			},
+		},
+		{
+			name: "wrapped transport",
+			client: &http.Client{
+				Transport: NewTransport(&http.Transport{Proxy: http.ProxyURL(proxy)}, nil),
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			},
+			want: "curl -x 'http://proxy.internal:3128' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "options take precedence",
+			client: &http.Client{
+				Transport: &http.Transport{},
+				Timeout:   5 * time.Second,
+			},
+			opts: []Option{WithRequestTimeout(30), WithLongForm()},
+			want: "curl --max-time 30 --location --max-redirs 10 --request 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+
+			c, err := NewFromRequestWithClient(r, tt.client, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequestWithClient() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			if diff := cmp.Diff(tt.wantWarnings, c.Validate()); diff != "" {
+				t.Errorf("Validate() diff = %s", diff)
+			}
+		})
+	}
+}
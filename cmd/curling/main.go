@@ -0,0 +1,132 @@
+// Command curling prints the cURL command equivalent to a raw HTTP/1.x request
+// or to every request of a HAR file.
+//
+// Usage:
+//
+//	curling [flags] [file]
+//
+// The input is read from file, or from the standard input when file is
+// omitted or is "-". Run curling -h for the list of flags.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/aoliveti/curling"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with args and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("curling", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: curling [flags] [file]")
+		fs.PrintDefaults()
+	}
+
+	har := fs.Bool("har", false, "read a HAR file instead of a raw HTTP request")
+	longForm := fs.Bool("long", false, "use the long form of cURL options")
+	multiLine := fs.String("multiline", "", "split the command across lines for `shell`: posix, cmd or powershell")
+	doubleQuotes := fs.Bool("double-quotes", false, "escape values using double quotes")
+	maxBodySize := fs.Int("max-body-size", 0, "truncate the body to `bytes`, no limit when zero")
+	location := fs.Bool("L", false, "set the flag -L, --location")
+	insecure := fs.Bool("k", false, "set the flag -k, --insecure")
+	silent := fs.Bool("s", false, "set the flag -s, --silent")
+	compressed := fs.Bool("compressed", false, "set the flag --compressed")
+	timeout := fs.Int("m", 0, "set the flag -m, --max-time to `seconds`")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+
+	var opts []curling.Option
+
+	switch *multiLine {
+	case "":
+	case curling.ShellPOSIX:
+		opts = append(opts, curling.WithMultiLine())
+	case curling.ShellWindows:
+		opts = append(opts, curling.WithWindowsMultiLine())
+	case curling.ShellPowerShell:
+		opts = append(opts, curling.WithPowerShellMultiLine())
+	default:
+		fmt.Fprintf(stderr, "curling: invalid shell %q\n", *multiLine)
+		return 2
+	}
+
+	if *longForm {
+		opts = append(opts, curling.WithLongForm())
+	}
+	if *doubleQuotes {
+		opts = append(opts, curling.WithDoubleQuotes())
+	}
+	if *maxBodySize > 0 {
+		opts = append(opts, curling.WithMaxBodySize(*maxBodySize))
+	}
+	if *location {
+		opts = append(opts, curling.WithFollowRedirects())
+	}
+	if *insecure {
+		opts = append(opts, curling.WithInsecure())
+	}
+	if *silent {
+		opts = append(opts, curling.WithSilent())
+	}
+	if *compressed {
+		opts = append(opts, curling.WithCompression())
+	}
+	if *timeout > 0 {
+		opts = append(opts, curling.WithRequestTimeout(*timeout))
+	}
+
+	in := stdin
+	if name := fs.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintf(stderr, "curling: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	commands, err := readCommands(in, *har, opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "curling: %v\n", err)
+		return 1
+	}
+
+	for _, c := range commands {
+		for _, w := range c.Validate() {
+			fmt.Fprintf(stderr, "curling: warning: %s\n", w)
+		}
+		fmt.Fprintln(stdout, c)
+	}
+
+	return 0
+}
+
+// readCommands returns the commands of the requests read from r.
+func readCommands(r io.Reader, har bool, opts ...curling.Option) ([]*curling.Command, error) {
+	if har {
+		return curling.NewFromHAR(r, opts...)
+	}
+
+	c, err := curling.NewFromRawHTTP(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*curling.Command{c}, nil
+}
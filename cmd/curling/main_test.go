@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_run(t *testing.T) {
+	raw := "POST /api HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"Content-Length: 15\r\n" +
+		"Content-Type: application/json\r\n" +
+		"\r\n" +
+		"{\"key\":\"value\"}"
+
+	har := `{"log":{"entries":[
+		{"request":{"method":"GET","url":"https://example.com/a","headers":[]}},
+		{"request":{"method":"DELETE","url":"https://example.com/b","headers":[]}}
+	]}}`
+
+	dir := t.TempDir()
+	rawFile := filepath.Join(dir, "request.txt")
+	if err := os.WriteFile(rawFile, []byte(raw), 0o600); err != nil {
+		t.Fatalf("writing request file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantStdout string
+		wantStderr string
+		wantCode   int
+	}{
+		{
+			name:       "raw request from stdin",
+			stdin:      raw,
+			wantStdout: "curl -X 'POST' 'http://example.com/api' -H 'Content-Length: 15' -H 'Content-Type: application/json' -d '{\"key\":\"value\"}'\n",
+		},
+		{
+			name:       "raw request from file",
+			args:       []string{"-long", "-double-quotes", rawFile},
+			wantStdout: "curl --request \"POST\" \"http://example.com/api\" --header \"Content-Length: 15\" --header \"Content-Type: application/json\" --data \"{\\\"key\\\":\\\"value\\\"}\"\n",
+		},
+		{
+			name:       "multiline",
+			args:       []string{"-multiline", "powershell", "-s", "-"},
+			stdin:      raw,
+			wantStdout: "curl -s -X 'POST' 'http://example.com/api' `\n-H 'Content-Length: 15' `\n-H 'Content-Type: application/json' `\n-d '{\"key\":\"value\"}'\n",
+		},
+		{
+			name:       "max body size",
+			args:       []string{"-max-body-size", "6"},
+			stdin:      raw,
+			wantStdout: "curl -X 'POST' 'http://example.com/api' -H 'Content-Length: 15' -H 'Content-Type: application/json' -d '{\"key\"'\n",
+			wantStderr: "curling: warning: body truncated to 6 of 15 bytes\n",
+		},
+		{
+			name:       "har",
+			args:       []string{"-har", "-L"},
+			stdin:      har,
+			wantStdout: "curl -L -X 'GET' 'https://example.com/a'\ncurl -L -X 'DELETE' 'https://example.com/b'\n",
+		},
+		{
+			name:       "invalid shell",
+			args:       []string{"-multiline", "zsh"},
+			wantStderr: "curling: invalid shell \"zsh\"\n",
+			wantCode:   2,
+		},
+		{
+			name:       "invalid request",
+			stdin:      "not a request",
+			wantStderr: "curling: reading raw request: malformed HTTP version \"request\"\n",
+			wantCode:   1,
+		},
+		{
+			name:       "missing file",
+			args:       []string{filepath.Join(dir, "missing.txt")},
+			wantStderr: "curling: open " + filepath.Join(dir, "missing.txt") + ": no such file or directory\n",
+			wantCode:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() code = %v, want %v", code, tt.wantCode)
+			}
+
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("run() stdout = %q, want %q", got, tt.wantStdout)
+			}
+
+			if got := stderr.String(); got != tt.wantStderr {
+				t.Errorf("run() stderr = %q, want %q", got, tt.wantStderr)
+			}
+		})
+	}
+}
@@ -1,33 +1,53 @@
 package curling
 
 import (
-	"bytes"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+// binaryBodyFileName is the file referenced by commands whose binary body is not written to a file.
+const binaryBodyFileName = "body.bin"
+
 // A Command represents a cURL command based on an HTTP request.
 //
 // Returned by [NewFromRequest], a Command expects a pointer to [http.Request] as input.
 // It generates a cURL command string, which by default uses a single line command,
 // short form options, and single quote escaping.
 type Command struct {
+	// request is the parsed model of the HTTP request the command is built from.
+	request parsedRequest
+
 	// tokens is a set of lines that form the command.
 	tokens []string
 
 	// lineContinuation is the character used to break a single statement into multiple lines.
 	lineContinuation string
 
+	// separator replaces the line continuation between tokens when not empty.
+	separator string
+
 	// location enables the option -L, --location.
 	location bool
 
 	// compressed enables the option --compressed.
 	compressed bool
 
+	// stripManagedHeaders leaves out the headers managed by cURL.
+	stripManagedHeaders bool
+
+	// strippedHeaders holds the sorted keys of the headers left out by stripManagedHeaders.
+	strippedHeaders []string
+
+	// compressedSuppressed leaves --compressed out when it conflicts with the request.
+	compressedSuppressed bool
+
 	// insecure enables the option -k, --insecure.
 	insecure bool
 
@@ -43,13 +63,320 @@ type Command struct {
 	// useDoubleQuotes enables escaping using double quotes.
 	useDoubleQuotes bool
 
+	// quoter quotes the values, the single or double quote escaping when nil.
+	quoter Quoter
+
+	// verbose enables the option -v, --verbose.
+	verbose bool
+
+	// include enables the option -i, --include.
+	include bool
+
+	// fail sets how cURL fails on HTTP errors.
+	fail failMode
+
 	// requestTimeout enables the option -m, --max-time.
-	requestTimeout int
+	requestTimeout time.Duration
+
+	// clientTimeout is the timeout of the client the command is derived from,
+	// setting -m, --max-time unless requestTimeout is set.
+	clientTimeout time.Duration
+
+	// outputFile enables the option -o, --output.
+	outputFile string
+
+	// remoteName enables the option -O, --remote-name.
+	remoteName bool
+
+	// outputDir enables the option --output-dir.
+	outputDir string
+
+	// createDirs enables the option --create-dirs.
+	createDirs bool
+
+	// contextTimeout derives the request timeout from the deadline of the request context.
+	contextTimeout bool
+
+	// timeoutFromContext reports whether the request timeout was derived from the request context.
+	timeoutFromContext bool
+
+	// connectTimeout enables the option --connect-timeout.
+	connectTimeout time.Duration
+
+	// warnings holds the findings collected while building the command.
+	warnings []Warning
+
+	// issues holds the non-fatal errors met while building the command.
+	issues []error
+
+	// inlineWarnings renders the warnings as comment lines above the command.
+	inlineWarnings bool
+
+	// placeholders holds the literal values replaced by shell variables.
+	placeholders []placeholder
+
+	// groupFlags groups related tokens on shared lines, as browsers do with "Copy as cURL".
+	groupFlags bool
+
+	// renderer renders the command in String, the cURL command when nil.
+	renderer Renderer
+
+	// outputVersion freezes the rendering rules of a release, the default one when zero.
+	outputVersion int
+
+	// bodyKind is the detected format of the request body.
+	bodyKind BodyKind
+
+	// decodedBody is the readable representation of the body produced by a registered [BodyDecoder].
+	decodedBody string
+
+	// decodedBodyType is the media type of the decoded body, empty when it wasn't decoded.
+	decodedBodyType string
+
+	// bodyKindComment renders the body kind as a comment line above the command.
+	bodyKindComment bool
+
+	// autoConfigFile writes the options of commands too long for a command line to a config file.
+	autoConfigFile bool
+
+	// autoConfigDir is the directory of the config files, the default temporary one when empty.
+	autoConfigDir string
+
+	// headerSpillSize is the header value size above which headers are written to a file.
+	headerSpillSize int
+
+	// headerSpillDir is the directory of the header files, the default temporary one when empty.
+	headerSpillDir string
+
+	// maxBodySize is the size above which the body is truncated, no limit when zero.
+	maxBodySize int
+
+	// maxCaptureSize is the number of body bytes read by Middleware, defaultMaxCaptureSize when zero.
+	maxCaptureSize int
+
+	// maxOutputSize is the size the rendered command is shrunk to, no limit when zero.
+	maxOutputSize int
+
+	// outputTruncated reports whether the request was shrunk to fit maxOutputSize.
+	outputTruncated bool
+
+	// droppedHeaders is the number of headers left out to fit maxOutputSize.
+	droppedHeaders int
+
+	// truncatedHeaders holds the header values cut to fit maxOutputSize.
+	truncatedHeaders []TruncatedHeader
+
+	// headerFile is the path of the file holding every header, headers are inlined when empty.
+	headerFile string
+
+	// trace holds the connection details rendered as comments above the command.
+	trace *Trace
+
+	// predicate decides whether Transport and Middleware convert a request, all of them when nil.
+	predicate func(r *http.Request) bool
+
+	// maxRedirects is the number of redirects followed with --max-redirs, cURL's default when zero.
+	maxRedirects int
+
+	// retry is the number of retries set with --retry, none when zero.
+	retry int
+
+	// retryDelay and retryMaxTime are the values of --retry-delay and --retry-max-time.
+	retryDelay   time.Duration
+	retryMaxTime time.Duration
+
+	// retryAllErrors reports whether cURL retries on every error.
+	retryAllErrors bool
+
+	// cookieOrder is the order of the cookies in the Cookie header.
+	cookieOrder CookieOrder
+
+	// httpVersion is the HTTP version cURL uses, negotiated when HTTPVersionDefault.
+	httpVersion HTTPVersion
+
+	// inferHTTPVersion reports whether the HTTP version is taken from the request protocol.
+	inferHTTPVersion bool
+
+	// trEncoding reports whether transfer codings are requested with --tr-encoding.
+	trEncoding bool
+
+	// proxy is the proxy URL set with --proxy, none when empty.
+	proxy string
+
+	// authScheme enables the option --digest, --ntlm, --negotiate or --anyauth.
+	authScheme AuthScheme
+
+	// authUser and authPassword are the credentials set with WithAuthUser.
+	authUser     string
+	authPassword string
+
+	// credentialUser and credentialPassword are the credentials sent with -u, --user,
+	// either authUser and authPassword or the ones found in the Authorization header.
+	credentialUser     string
+	credentialPassword string
+
+	// proxyUser and proxyPassword are the proxy credentials set with --proxy-user.
+	proxyUser     string
+	proxyPassword string
+
+	// noProxy holds the hosts reached without proxy, set with --noproxy.
+	noProxy []string
+
+	// socksProxy is the SOCKS5 proxy address, set with --socks5-hostname when
+	// socksRemoteDNS is true, --socks5 otherwise.
+	socksProxy     string
+	socksRemoteDNS bool
+
+	// proxyFromEnv reports whether the proxy is taken from the environment.
+	proxyFromEnv bool
+
+	// certFile and keyFile are the client certificate and key files set with --cert and --key.
+	certFile string
+	keyFile  string
+
+	// certPassword is the password of the client certificate key.
+	certPassword string
+
+	// caCertFile is the CA certificate file set with --cacert.
+	caCertFile string
+
+	// certInMemory and caCertInMemory report TLS material held in memory by the client,
+	// referenced as files that must be provided.
+	certInMemory   bool
+	caCertInMemory bool
+
+	// routes holds the options applied by Transport and Middleware to the requests they match.
+	routes []route
+
+	// formEncoding renders url-encoded bodies as one --data-urlencode option per field.
+	formEncoding bool
+
+	// binaryBodyFile writes binary bodies to a file in binaryBodyDir instead of referencing body.bin.
+	binaryBodyFile bool
+
+	// binaryBodyDir is the directory of the body files, the default temporary one when empty.
+	binaryBodyDir string
+
+	// minimal keeps only what is needed to reproduce the request.
+	minimal bool
+
+	// bodyToFile writes every body to bodyFile, or to a temporary file when bodyFile is empty.
+	bodyToFile bool
+
+	// bodyFile is the path of the body file.
+	bodyFile string
+
+	// faithful maximizes the wire fidelity of the command.
+	faithful bool
+
+	// jsonFlag renders JSON bodies with --json.
+	jsonFlag bool
+
+	// decodeBody decompresses gzip, deflate and br encoded bodies.
+	decodeBody bool
+
+	// now returns the current time, time.Now when nil.
+	now func() time.Time
+
+	// dryRun constructs the command without writing the side files, to measure it.
+	dryRun bool
+
+	// newID returns the IDs naming the side files, random names when nil.
+	newID func() string
+
+	// tempFS creates the side files, the os package when nil.
+	tempFS TempFS
+
+	// redactedHeaders lists the canonical keys of the headers whose values are replaced by variables.
+	redactedHeaders []string
+
+	// headerRedactor replaces the values of the other sensitive headers.
+	headerRedactor func(key, value string) (string, bool)
+
+	// headerEncoding sets how non-ASCII header values are rendered.
+	headerEncoding HeaderEncoding
+
+	// idiomaticFlags adapts the flags to the request semantics.
+	idiomaticFlags bool
+
+	// forceScheme is the scheme the request URL is rewritten to, none when empty.
+	forceScheme string
+
+	// insecureOnRewrite enables the option -k, --insecure when the URL is rewritten to https.
+	insecureOnRewrite bool
+
+	// insecureFromRewrite reports whether -k, --insecure was enabled by the rewrite only.
+	insecureFromRewrite bool
+
+	// cookieJar enables the option -c, --cookie-jar.
+	cookieJar string
+
+	// cookieJarLoad loads the cookie jar with the option -b, --cookie.
+	cookieJarLoad bool
+
+	// junkSessionCookies enables the option -j, --junk-session-cookies.
+	junkSessionCookies bool
+
+	// userAgentFlag renders the User-Agent header with the option -A, --user-agent.
+	userAgentFlag bool
+
+	// refererFlag renders the Referer header with the option -e, --referer.
+	refererFlag bool
+
+	// envFile is the dotenv file receiving the placeholder values.
+	envFile string
+
+	// secretValues holds the redacted values, keyed by the variable replacing them.
+	secretValues map[string]string
+
+	// optionSecrets holds the variables replacing the passwords of the options,
+	// which Args restores.
+	optionSecrets []string
+
+	// oauth2BearerFlag renders a bearer Authorization header with the option --oauth2-bearer.
+	oauth2BearerFlag bool
+
+	// scrubbers mask the secrets found in the request.
+	scrubbers []Scrubber
+
+	// redirects lists the hops of the redirect chain replayed by the command.
+	redirects []string
+
+	// extraArgs holds the cURL arguments the library doesn't model.
+	extraArgs []string
+
+	// args holds the unquoted arguments of the tokens.
+	args []string
+
+	// placeholderVars holds the variables set with WithPlaceholders.
+	placeholderVars map[string]string
+
+	// once guards the construction of the tokens, deferred until they are needed.
+	once sync.Once
+
+	// opts holds the options the command is built with.
+	opts []Option
+
+	// source is the parsed request before any option changes it.
+	source parsedRequest
+
+	// files holds the paths of the side files written while building the command.
+	files []string
+
+	// capturedAt is the time the command was built.
+	capturedAt time.Time
+
+	// meta holds the values attached with SetMeta.
+	meta map[string]any
+
+	// metaMu guards meta.
+	metaMu sync.RWMutex
 }
 
 // NewFromRequest returns a new [Command] that reads from r.
 // If the request has an invalid URL, NewFromRequest returns an error.
 // If NewFromRequest can't read the request body, it returns an error.
+// If NewFromRequest can't write a side file, it returns an error.
 func NewFromRequest(r *http.Request, opts ...Option) (*Command, error) {
 	var c Command
 
@@ -60,21 +387,223 @@ func NewFromRequest(r *http.Request, opts ...Option) (*Command, error) {
 	return &c, nil
 }
 
-// String returns the cURL command.
+// NewFromParts returns a new [Command] built from the components of a request,
+// for callers that don't have an [http.Request] at hand.
+// The body is read entirely and may be nil when the request has none.
+// If rawurl can't be parsed, NewFromParts returns an error.
+// If NewFromParts can't read the body, it returns an error.
+func NewFromParts(method, rawurl string, header http.Header, body io.Reader, opts ...Option) (*Command, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, fmt.Errorf("parsing request url: %w", err)
+	}
+
+	r := &http.Request{
+		Method: method,
+		URL:    u,
+		Header: header,
+	}
+
+	if body != nil {
+		r.Body = io.NopCloser(body)
+	}
+
+	return NewFromRequest(r, opts...)
+}
+
+// NewFromRequestLenient returns a new [Command] that reads from r, like [NewFromRequest],
+// degrading the problems it can recover from into warnings, so that logging paths
+// get a partial command instead of no output.
+// If NewFromRequestLenient can't read the request body, the command carries the bytes
+// read before the failure and a [WarningUnreadableBody] warning.
+// Invalid cookies are reported with [WarningInvalidCookie] warnings.
+// The warnings are the ones returned by [Command.Validate].
+// If the request has an invalid URL or a side file can't be written,
+// NewFromRequestLenient returns an error.
+func NewFromRequestLenient(r *http.Request, opts ...Option) (*Command, []Warning, error) {
+	request, err := parseRequest(r)
+	if err != nil && request.readErr == nil {
+		return nil, nil, err
+	}
+
+	var c Command
+	if err := c.buildParsed(request, opts...); err != nil {
+		return nil, c.Validate(), err
+	}
+
+	return &c, c.Validate(), nil
+}
+
+// String returns the command rendered by the configured [Renderer],
+// the cURL command by default.
 func (c *Command) String() string {
-	separator := " "
-	if c.useMultiLine {
-		separator = fmt.Sprintf(" %s\n", c.lineContinuation)
+	if c.renderer != nil {
+		return c.renderer.Render(c)
+	}
+
+	return c.curl()
+}
+
+// JSONString returns the command returned by String as a JSON string literal,
+// quotes included, ready to be embedded as a value in structured logs.
+// Control characters are escaped and invalid UTF-8 is replaced with U+FFFD.
+func (c *Command) JSONString() string {
+	return jsonQuote(c.String())
+}
+
+// With returns a variant of the command built with its options followed by opts,
+// such as a long form or a PowerShell variant, reusing the parsed request
+// instead of reading the request body again. The command is left unchanged.
+// If With can't write a side file, it returns an error.
+func (c *Command) With(opts ...Option) (*Command, error) {
+	variant := Command{redirects: c.redirects, meta: c.cloneMeta()}
+
+	if err := variant.buildParsed(c.source.clone(), append(slices.Clip(c.opts), opts...)...); err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+// Args returns the arguments of the cURL command, starting with curl, unquoted
+// and ready to be passed to [os/exec.Command] without a shell.
+// Placeholders are not replaced, since no shell expands them, except for the
+// passwords of the options, such as [WithCertPassword], which the arguments carry
+// as set. The lines rendered above the command, such as comments, are left out.
+func (c *Command) Args() []string {
+	c.ensureConstructed()
+
+	args := make([]string, len(c.args))
+	copy(args, c.args)
+
+	if len(c.optionSecrets) > 0 {
+		oldnew := make([]string, 0, 2*len(c.optionSecrets))
+		for _, name := range c.optionSecrets {
+			oldnew = append(oldnew, "${"+name+"}", c.secretValues[name])
+		}
+
+		r := strings.NewReplacer(oldnew...)
+		for i, arg := range args {
+			args[i] = r.Replace(arg)
+		}
 	}
 
-	s := strings.Join(c.tokens, separator)
+	return args
+}
+
+// decorate prepends to the rendered command s the lines that must precede it:
+// the inline warnings, the connection trace, the body kind, the decoded body
+// and the left out headers as comments, and the placeholder export block
+// or the line sourcing the env file.
+func (c *Command) decorate(s string) string {
+	var b strings.Builder
+
+	if c.inlineWarnings {
+		for _, w := range c.warnings {
+			fmt.Fprintf(&b, "%s WARNING: %s\n", c.commentPrefix(), w.Message)
+		}
+	}
+
+	b.WriteString(c.traceBlock())
+	b.WriteString(c.redirectBlock())
+
+	if c.bodyKindComment && c.bodyKind != BodyKindNone {
+		fmt.Fprintf(&b, "%s Body: %s\n", c.commentPrefix(), c.bodyKind)
+	}
+
+	b.WriteString(c.decodedBodyComment())
+	b.WriteString(c.droppedHeadersComment())
+	b.WriteString(c.strippedHeadersComment())
+
+	switch {
+	case len(c.placeholders) > 0 && c.envFile != "":
+		b.WriteString(c.sourceBlock())
+	case len(c.placeholders) > 0:
+		b.WriteString(c.exportBlock())
+	}
+
+	b.WriteString(s)
+	return b.String()
+}
+
+// join joins tokens with a space, or with the line continuation when multiline is enabled.
+// With flag grouping, continuation lines are indented. A custom separator is used as is.
+func (c *Command) join(tokens []string) string {
+	s := strings.Join(tokens, c.tokenSeparator())
 	return strings.TrimSpace(s)
 }
 
-// appendToken appends a new token into tokens.
-func (c *Command) appendToken(s ...string) {
-	token := strings.Join(s, " ")
-	c.tokens = append(c.tokens, token)
+// tokenSeparator returns the string joining the tokens: a space, the line
+// continuation when multiline is enabled or the custom separator.
+func (c *Command) tokenSeparator() string {
+	if c.separator != "" {
+		return c.separator
+	}
+
+	if !c.useMultiLine {
+		return " "
+	}
+
+	separator := fmt.Sprintf(" %s\n", c.lineContinuation)
+	if c.groupFlags {
+		separator += "  "
+	}
+
+	return separator
+}
+
+// commentPrefix returns the marker that starts a comment line in the target shell.
+func (c *Command) commentPrefix() string {
+	if c.shell() == ShellWindows {
+		return "REM"
+	}
+
+	return "#"
+}
+
+// A word is an argument of the command: an option, rendered as is,
+// or a value, quoted when rendered.
+type word struct {
+	s     string
+	value bool
+}
+
+// literal returns a word rendered as is, such as an option or a number.
+func literal(s string) word {
+	return word{s: s}
+}
+
+// value returns a word quoted when rendered.
+func value(s string) word {
+	return word{s: s, value: true}
+}
+
+// appendToken appends a new token made of words into tokens,
+// and the unquoted words into args.
+// The token is written in a single pass, sized for the common case where
+// no quote needs escaping.
+func (c *Command) appendToken(words ...word) {
+	size := len(words)
+	for _, w := range words {
+		size += len(w.s) + 2
+	}
+
+	var b strings.Builder
+	b.Grow(size)
+
+	for i, w := range words {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if w.value {
+			c.writeEscaped(&b, w.s)
+		} else {
+			b.WriteString(w.s)
+		}
+		c.args = append(c.args, w.s)
+	}
+
+	c.tokens = append(c.tokens, b.String())
 }
 
 // optionForm returns either the short or long form based on the useLongForm flag.
@@ -86,122 +615,481 @@ func (c *Command) optionForm(short, long string) string {
 	return short
 }
 
-// escape takes a string as input and escapes it with single or double quotes based
-// on the useDoubleQuotes option.
+// escape takes a string as input and quotes it with the configured [Quoter], or
+// escapes it with single or double quotes based on the useDoubleQuotes option.
+// Placeholder values are replaced with their variables.
 func (c *Command) escape(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	c.writeEscaped(&b, s)
+
+	return b.String()
+}
+
+// writeEscaped writes s to b as escape returns it. Without placeholders,
+// the quoted string is written directly, with no intermediate strings.
+func (c *Command) writeEscaped(b *strings.Builder, s string) {
+	if c.quoter != nil {
+		b.WriteString(c.quotePlaceholders(s))
+		return
+	}
+
+	quote := byte('\'')
 	if c.useDoubleQuotes {
-		v := strings.ReplaceAll(s, "\"", "\\\"")
-		return fmt.Sprintf("\"%s\"", v)
+		quote = '"'
+	}
+
+	if len(c.placeholders) > 0 {
+		b.WriteString(c.replacePlaceholders(string(quote) + c.escapeQuotes(s) + string(quote)))
+		return
 	}
 
-	v := strings.ReplaceAll(s, "'", "'\\''")
-	return fmt.Sprintf("'%s'", v)
+	b.WriteByte(quote)
+	c.writeEscapedQuotes(b, s)
+	b.WriteByte(quote)
+}
+
+// escapeQuotes escapes the quote characters of s based on the useDoubleQuotes option.
+func (c *Command) escapeQuotes(s string) string {
+	if c.useDoubleQuotes {
+		return strings.ReplaceAll(s, "\"", "\\\"")
+	}
+
+	return strings.ReplaceAll(s, "'", "'\\''")
+}
+
+// writeEscapedQuotes writes s to b as escapeQuotes returns it.
+func (c *Command) writeEscapedQuotes(b *strings.Builder, s string) {
+	quote, escaped := "'", "'\\''"
+	if c.useDoubleQuotes {
+		quote, escaped = "\"", "\\\""
+	}
+
+	for {
+		i := strings.Index(s, quote)
+		if i < 0 {
+			b.WriteString(s)
+			return
+		}
+
+		b.WriteString(s[:i])
+		b.WriteString(escaped)
+		s = s[i+1:]
+	}
 }
 
 // build produces tokens based on the supplied options and http request.
 // If the request URL is nil, build returns an error.
 // If build can't read the request body, it returns an error.
 func (c *Command) build(r *http.Request, opts ...Option) error {
+	request, err := parseRequest(r)
+	if err != nil {
+		return err
+	}
+
+	return c.buildParsed(request, opts...)
+}
+
+// buildParsed produces tokens based on the supplied options and parsed request,
+// which is kept untouched so that variants can be built from it.
+// If buildParsed can't write a side file, it returns an error.
+func (c *Command) buildParsed(request parsedRequest, opts ...Option) error {
 	for _, opt := range opts {
 		opt(c)
 	}
 
-	if r.URL == nil {
-		return fmt.Errorf("request url is nil")
+	c.opts = opts
+	c.source = request
+	c.request = request.clone()
+
+	c.checkHeaders()
+	c.checkCookies()
+	c.stripTrailers()
+	c.orderCookies()
+	c.applyProxyEnvironment()
+	c.rewriteScheme()
+	c.applyAuthScheme()
+
+	if c.decodeBody {
+		if err := c.request.decodeBody(); err != nil {
+			c.warn(WarningUndecodableBody, "body was left encoded: %v", err)
+			c.issue(err)
+		}
+	}
+
+	c.scrub()
+	c.bodyKind = detectBodyKind(&c.request)
+	c.decodeVendorBody()
+	c.request.truncateBody(c.maxBodySize)
+	c.capturedAt = time.Now()
+	if c.now != nil {
+		c.capturedAt = c.now()
+	}
+	c.applyClientTimeout()
+	c.applyContextTimeout()
+
+	c.redactHeaders()
+	c.redactCertPassword()
+	c.redactProxyPassword()
+	c.redactAuthPassword()
+	c.encodeHeaders()
+
+	if c.minimal {
+		c.minimize()
+	}
+
+	c.applyIdiomaticFlags()
+	c.stripManaged()
+	c.validate()
+
+	if err := c.writeEnvFile(); err != nil {
+		return err
+	}
+
+	if c.maxOutputSize > 0 {
+		if err := c.fitOutput(); err != nil {
+			return err
+		}
+
+		return c.splitConfigFile()
+	}
+
+	// Side files are written right away, so that failures are reported.
+	if !c.writesFiles() {
+		return nil
+	}
+
+	var err error
+	c.once.Do(func() {
+		err = c.construct()
+	})
+	if err != nil {
+		return err
 	}
 
-	c.buildCommand(r)
-	c.buildHeaders(r)
+	return c.splitConfigFile()
+}
+
+// construct produces the tokens of the command.
+// If construct can't write a side file, it returns an error.
+func (c *Command) construct() error {
+	// The command, the headers, the body and the transport options,
+	// with an option and a value for each argument.
+	c.tokens = make([]string, 0, len(c.request.header)+3)
+	c.args = make([]string, 0, 2*len(c.request.header)+16)
 
-	if err := c.buildData(r); err != nil {
+	c.buildCommand()
+
+	if err := c.buildHeaders(); err != nil {
 		return err
 	}
 
+	if err := c.buildData(); err != nil {
+		return err
+	}
+
+	c.buildTransport()
+
 	return nil
 }
 
+// ensureConstructed produces the tokens of the command the first time they are needed,
+// so that building a command whose output is discarded is cheap.
+// Commands are immutable once built, so the tokens never need to be produced again.
+func (c *Command) ensureConstructed() {
+	c.once.Do(func() {
+		// Commands that weren't built have no request to construct the tokens from.
+		if c.request.url == nil {
+			return
+		}
+
+		// Commands that write side files are constructed by build, so this can't fail.
+		_ = c.construct()
+	})
+}
+
+// writesFiles reports whether building the command may write side files.
+func (c *Command) writesFiles() bool {
+	return c.headerFile != "" || c.headerSpillSize > 0 || c.bodyToFile || c.binaryBodyFile || c.autoConfigFile
+}
+
 // buildCommand produces the token representing the curl command and its related options.
-func (c *Command) buildCommand(r *http.Request) {
-	s := []string{"curl"}
+// With flag grouping, the transport options are left to buildTransport and the
+// method gets its own token. In minimal mode, the method is left out when cURL implies it.
+func (c *Command) buildCommand() {
+	command := []word{literal("curl")}
+
+	if !c.groupFlags {
+		command = append(command, c.transportOptions()...)
+	}
+
+	if c.minimal && c.impliedMethod() {
+		c.appendToken(append(command, value(c.request.url.String()))...)
+		return
+	}
+
+	if c.groupFlags {
+		c.appendToken(append(command, value(c.request.url.String()))...)
+		c.appendToken(c.methodOptions()...)
+		return
+	}
+
+	command = append(command, c.methodOptions()...)
+	c.appendToken(append(command, value(c.request.url.String()))...)
+}
+
+// methodOptions returns the options setting the request method: with OutputVersion2,
+// -I, --head for HEAD requests, so that cURL doesn't wait for a response body,
+// -X, --request otherwise. HEAD requests with a body keep -X, --request, since
+// cURL refuses -I with data.
+func (c *Command) methodOptions() []word {
+	if c.version() >= OutputVersion2 && c.request.method == http.MethodHead && !c.request.hasBody {
+		return []word{literal(c.option(flagHead))}
+	}
+
+	return []word{literal(c.option(flagRequest)), value(c.request.method)}
+}
+
+// buildTransport produces the token grouping the transport options, last in the command.
+// Without flag grouping, the options belong to the command token and no token is produced.
+func (c *Command) buildTransport() {
+	if !c.groupFlags {
+		return
+	}
+
+	if s := c.transportOptions(); len(s) > 0 {
+		c.appendToken(s...)
+	}
+}
+
+// transportOptions returns the options that drive how curl performs the transfer,
+// followed by the extra flags. In minimal mode, only the extra flags are returned.
+func (c *Command) transportOptions() []word {
+	if c.minimal {
+		return c.extraFlags()
+	}
+
+	var s []word
 
 	if c.silent {
-		s = append(s, c.optionForm("-s", "--silent"))
+		s = append(s, literal(c.option(flagSilent)))
+	}
+
+	if c.verbose {
+		s = append(s, literal(c.option(flagVerbose)))
+	}
+
+	if c.include {
+		s = append(s, literal(c.option(flagInclude)))
+	}
+
+	switch c.fail {
+	case failFast:
+		s = append(s, literal(c.option(flagFail)))
+	case failWithBody:
+		s = append(s, literal(c.option(flagFailWithBody)))
 	}
 
 	if c.requestTimeout > 0 {
-		s = append(s, c.optionForm("-m", "--max-time"), strconv.Itoa(c.requestTimeout))
+		s = append(s, literal(c.option(flagMaxTime)), literal(formatSeconds(c.requestTimeout)))
 	}
 
+	if c.connectTimeout > 0 {
+		s = append(s, literal(c.option(flagConnectTimeout)), literal(formatSeconds(c.connectTimeout)))
+	}
+
+	s = append(s, c.retryOptions()...)
+
 	if c.insecure {
-		s = append(s, c.optionForm("-k", "--insecure"))
+		s = append(s, literal(c.option(flagInsecure)))
 	}
 
-	if c.compressed {
-		s = append(s, "--compressed")
+	if c.compressed && !c.compressedSuppressed {
+		s = append(s, literal(c.option(flagCompressed)))
 	}
 
+	if c.trEncoding {
+		s = append(s, literal(c.option(flagTrEncoding)))
+	}
+
+	s = append(s, c.httpVersionOptions()...)
+
 	if c.location {
-		s = append(s, c.optionForm("-L", "--location"))
+		s = append(s, literal(c.option(flagLocation)))
 	}
 
-	var command string
-	if len(s) > 0 {
-		command = strings.Join(s, " ")
+	if c.location && c.maxRedirects > 0 {
+		s = append(s, literal(c.option(flagMaxRedirs)), literal(strconv.Itoa(c.maxRedirects)))
 	}
 
-	method := r.Method
-	if method == "" {
-		method = http.MethodGet
+	s = append(s, c.proxyOptions()...)
+	s = append(s, c.authOptions()...)
+
+	if c.certFile != "" {
+		s = append(s, literal(c.option(flagCert)), value(c.certArgument()))
 	}
 
-	c.appendToken(
-		command,
-		c.optionForm("-X", "--request"),
-		c.escape(method),
-		c.escape(r.URL.String()),
-	)
-}
+	if c.keyFile != "" {
+		s = append(s, literal(c.option(flagKey)), value(c.keyFile))
+	}
 
-// buildHeaders produces one token for each request header.
-func (c *Command) buildHeaders(r *http.Request) {
-	if len(r.Header) == 0 {
-		return
+	if c.caCertFile != "" {
+		s = append(s, literal(c.option(flagCACert)), value(c.caCertFile))
 	}
 
-	var headers []string
-	for key, values := range r.Header {
-		canonicalKey := http.CanonicalHeaderKey(key)
-		headers = append(headers, fmt.Sprintf("%s: %s", canonicalKey, strings.Join(values, ", ")))
+	s = append(s, c.outputOptions()...)
+	s = append(s, c.cookieJarOptions()...)
+
+	if c.faithful {
+		s = append(s, literal(c.option(flagPathAsIs)), literal(c.option(flagGlobOff)))
 	}
 
-	slices.Sort(headers)
+	return append(s, c.extraFlags()...)
+}
+
+// buildHeaders produces one token for each request header, with the option
+// dedicated to the header when there is one, such as -A for User-Agent.
+// With a header file, every header is written to the file referenced by a single
+// -H @file token; with header spill, only the headers whose value exceeds the
+// threshold are written to a temporary file.
+// If buildHeaders can't write the header file, it returns an error.
+func (c *Command) buildHeaders() error {
+	var spilled []byte
+
+	for _, header := range c.headers() {
+		if f, arg, ok := c.headerFlag(header); ok {
+			c.appendToken(literal(c.option(f)), value(arg))
+			continue
+		}
+
+		if c.headerFile != "" || c.headerSpillSize > 0 && len(header.value) > c.headerSpillSize {
+			spilled = append(spilled, header.String()+"\n"...)
+			continue
+		}
 
-	for _, header := range headers {
 		c.appendToken(
-			c.optionForm("-H", "--header"),
-			c.escape(header),
+			literal(c.option(flagHeader)),
+			value(header.String()),
 		)
 	}
+
+	if spilled == nil {
+		return nil
+	}
+
+	var path string
+	var err error
+	if c.headerFile != "" {
+		path, err = c.writeFileAt(c.headerFile, spilled)
+	} else {
+		path, err = c.writeFile(c.headerSpillDir, "curling-headers-*.txt", spilled)
+	}
+	if err != nil {
+		return fmt.Errorf("writing header file: %w", err)
+	}
+
+	c.appendToken(literal(c.option(flagHeader)), value("@"+path))
+
+	return nil
 }
 
 // buildData produces the token representing the request body and its related option (-d or --data).
-// If the request body is nil or [http.NoBody], no token is produced.
-// If buildData can't read the request body, it returns an error.
-func (c *Command) buildData(r *http.Request) error {
-	if r.Body == nil || r.Body == http.NoBody {
+// With form encoding, url-encoded bodies produce a --data-urlencode token for each field
+// and with the JSON flag, JSON bodies produce a --json token.
+// Every body with body to file is referenced with --data-binary @file, and so are
+// binary bodies written with WithBinaryBodyDir or rendered with OutputVersion2.
+// With OutputVersion2, GET and HEAD bodies are sent with --data-raw, which never reads a file.
+// If the request has no body, no token is produced.
+// If buildData can't write the body file, it returns an error.
+func (c *Command) buildData() error {
+	if !c.request.hasBody {
 		return nil
 	}
 
-	var b bytes.Buffer
-	if _, err := b.ReadFrom(r.Body); err != nil {
-		return fmt.Errorf("reading bytes from request body: %w", err)
+	if c.bodyToFile || c.referencesBinaryBody() {
+		path, err := c.writeBody()
+		if err != nil {
+			return err
+		}
+
+		c.appendToken(literal(c.option(flagDataBinary)), value("@"+path))
+		return nil
+	}
+
+	if c.usesJSONFlag() {
+		c.appendToken(literal(c.option(flagJSON)), value(string(c.request.body)))
+		return nil
 	}
 
-	// Reset request body for potential re-reads
-	r.Body = io.NopCloser(bytes.NewBuffer(b.Bytes()))
+	if c.formEncoding && !c.faithful {
+		if fields, ok := c.request.formFields(); ok {
+			for _, field := range fields {
+				c.appendToken(literal(c.option(flagURLEncode)), value(field))
+			}
+			return nil
+		}
+	}
 
-	option := c.optionForm("-d", "--data")
-	c.appendToken(option, c.escape(b.String()))
+	option := c.option(flagData)
+	switch {
+	case c.faithful:
+		option = c.option(flagDataBinary)
+	case c.version() >= OutputVersion2 && c.bodyWithoutPayloadSemantics():
+		option = c.option(flagDataRaw)
+	}
+	c.appendToken(literal(option), value(string(c.request.body)))
 
 	return nil
 }
+
+// headers returns the header fields rendered by the command. In faithful mode,
+// the keys keep their casing, each value has its own field and the body size is
+// declared with Content-Length when the request doesn't set it.
+// With the --json option, the headers it sets are left out.
+func (c *Command) headers() []headerField {
+	if c.usesJSONFlag() {
+		return jsonFlagHeaderFields(c.request.headerFields())
+	}
+
+	if !c.faithful {
+		return c.request.headerFields()
+	}
+
+	fields := c.request.rawHeaderFields()
+	if c.request.hasBody && c.request.header.Get("Content-Length") == "" {
+		fields = append(fields, headerField{key: "Content-Length", value: strconv.Itoa(c.request.bodySize)})
+	}
+
+	return fields
+}
+
+// referencesBinaryBody reports whether a binary body is referenced as a file.
+// OutputVersion1 renders binary bodies inline unless they are written with WithBinaryBodyDir.
+func (c *Command) referencesBinaryBody() bool {
+	return !isText(c.request.body) && (c.binaryBodyFile || c.version() >= OutputVersion2)
+}
+
+// writeBody writes the body to the configured file and returns its path.
+// Binary bodies are referenced as body.bin when no file is configured.
+// If writeBody can't write the file, it returns an error.
+func (c *Command) writeBody() (string, error) {
+	var path string
+	var err error
+
+	switch {
+	case c.bodyToFile && c.bodyFile != "":
+		path, err = c.writeFileAt(c.bodyFile, c.request.body)
+	case c.bodyToFile:
+		path, err = c.writeFile("", "curling-body-*.bin", c.request.body)
+	case c.binaryBodyFile:
+		path, err = c.writeFile(c.binaryBodyDir, "curling-body-*.bin", c.request.body)
+	default:
+		return binaryBodyFileName, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("writing body file: %w", err)
+	}
+
+	return path, nil
+}
@@ -73,6 +73,7 @@ func Test_NewFromRequest_body(t *testing.T) {
 					"curl -X 'POST' 'https://localhost/test'",
 					"-d 'key=value'",
 				},
+				bodyKind: BodyKindForm,
 			},
 			wantErr: false,
 		},
@@ -123,6 +124,45 @@ func Test_NewFromRequest_body(t *testing.T) {
 					"--data 'key=value'",
 				},
 				useLongForm: true,
+				bodyKind:    BodyKindForm,
+			},
+			wantErr: false,
+		},
+		{
+			name: "max body size",
+			args: args{
+				r:    r,
+				opts: []Option{WithMaxBodySize(3)},
+			},
+			want: &Command{
+				tokens: []string{
+					"curl -X 'POST' 'https://localhost/test'",
+					"-d 'key'",
+				},
+				warnings: []Warning{
+					{
+						Code:    WarningBodyTruncated,
+						Message: "body truncated to 3 of 9 bytes",
+					},
+				},
+				bodyKind:    BodyKindForm,
+				maxBodySize: 3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "max body size larger than body",
+			args: args{
+				r:    r,
+				opts: []Option{WithMaxBodySize(16)},
+			},
+			want: &Command{
+				tokens: []string{
+					"curl -X 'POST' 'https://localhost/test'",
+					"-d 'key=value'",
+				},
+				bodyKind:    BodyKindForm,
+				maxBodySize: 16,
 			},
 			wantErr: false,
 		},
@@ -135,10 +175,62 @@ func Test_NewFromRequest_body(t *testing.T) {
 				return
 			}
 
-			optUnexported := cmp.AllowUnexported(Command{})
+			if got != nil {
+				got.ensureConstructed()
+			}
+
+			optUnexported := cmpCommand
 			if !cmp.Equal(got, tt.want, optUnexported) {
 				t.Errorf("NewFromRequest() got = %v, want = %v, diff = %v", got, tt.want, cmp.Diff(got, tt.want, optUnexported))
 			}
 		})
 	}
 }
+
+func Test_NewFromRequest_formEncoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{
+			name:        "form fields",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "q=a+b%26c&name=O%27Brien&flag",
+			want:        "curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/x-www-form-urlencoded' --data-urlencode 'q=a b&c' --data-urlencode 'name=O'\\''Brien' --data-urlencode '=flag'",
+		},
+		{
+			name:        "not a form",
+			contentType: "application/json",
+			body:        `{"a":"b"}`,
+			want:        "curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/json' -d '{\"a\":\"b\"}'",
+		},
+		{
+			name:        "empty field",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "a=1&&b=2",
+			want:        "curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/x-www-form-urlencoded' -d 'a=1&&b=2'",
+		},
+		{
+			name:        "invalid escape",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "a=%zz",
+			want:        "curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/x-www-form-urlencoded' -d 'a=%zz'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{"Content-Type": {tt.contentType}}
+
+			c, err := NewFromParts(http.MethodPost, "https://localhost/test", header, strings.NewReader(tt.body), WithFormEncoding())
+			if err != nil {
+				t.Fatalf("NewFromParts() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
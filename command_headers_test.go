@@ -186,7 +186,11 @@ func Test_NewFromRequest_headers(t *testing.T) {
 				return
 			}
 
-			optUnexported := cmp.AllowUnexported(Command{})
+			if got != nil {
+				got.ensureConstructed()
+			}
+
+			optUnexported := cmpCommand
 			if !cmp.Equal(got, tt.want, optUnexported) {
 				t.Errorf("NewFromRequest() got = %v, want = %v, diff = %v", got, tt.want, cmp.Diff(got, tt.want, optUnexported))
 			}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func Test_NewFromRequest_options(t *testing.T) {
@@ -84,7 +85,7 @@ func Test_NewFromRequest_options(t *testing.T) {
 				tokens: []string{
 					"curl -m 5 -X 'GET' 'https://localhost/test'",
 				},
-				requestTimeout: 5,
+				requestTimeout: 5 * time.Second,
 			},
 			wantErr: false,
 		},
@@ -168,7 +169,7 @@ func Test_NewFromRequest_options(t *testing.T) {
 					"curl --max-time 5 --request 'GET' 'https://localhost/test'",
 				},
 				useLongForm:    true,
-				requestTimeout: 5,
+				requestTimeout: 5 * time.Second,
 			},
 			wantErr: false,
 		},
@@ -236,6 +237,12 @@ func Test_NewFromRequest_options(t *testing.T) {
 				},
 				useMultiLine:     true,
 				lineContinuation: lineContinuationWindows,
+				warnings: []Warning{
+					{
+						Code:    WarningSingleQuotesOnWindows,
+						Message: "the Windows shell does not support single quotes, use double quotes",
+					},
+				},
 			},
 			wantErr: false,
 		},
@@ -272,6 +279,45 @@ func Test_NewFromRequest_options(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "flag grouping option",
+			args: args{
+				r: &http.Request{
+					URL:    testUrl,
+					Header: http.Header{"X-Key": {"1"}},
+				},
+				opts: []Option{WithFlagGrouping(), WithSilent(), WithFollowRedirects()},
+			},
+			want: &Command{
+				tokens: []string{
+					"curl 'https://localhost/test'",
+					"-X 'GET'",
+					"-H 'X-Key: 1'",
+					"-s -L",
+				},
+				groupFlags: true,
+				silent:     true,
+				location:   true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "flag grouping option without transport options",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+				opts: []Option{WithFlagGrouping()},
+			},
+			want: &Command{
+				tokens: []string{
+					"curl 'https://localhost/test'",
+					"-X 'GET'",
+				},
+				groupFlags: true,
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -281,10 +327,184 @@ func Test_NewFromRequest_options(t *testing.T) {
 				return
 			}
 
-			optUnexported := cmp.AllowUnexported(Command{})
+			if got != nil {
+				got.ensureConstructed()
+			}
+
+			optUnexported := cmpCommand
 			if !cmp.Equal(got, tt.want, optUnexported) {
 				t.Errorf("NewFromRequest() got = %v, want = %v, diff = %v", got, tt.want, cmp.Diff(got, tt.want, optUnexported))
 			}
 		})
 	}
 }
+
+func TestWithLineContinuation(t *testing.T) {
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL: &url.URL{
+			Scheme: "https",
+			Host:   "localhost",
+			Path:   "test",
+		},
+		Header: http.Header{"X-Key": {"1"}},
+		Body:   readCloser("key=value"),
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "indented continuation",
+			opts: []Option{WithLineContinuation(" \\\n    ")},
+			want: "curl -X 'POST' 'https://localhost/test' \\\n    -H 'X-Key: 1' \\\n    -d 'key=value'",
+		},
+		{
+			name: "bare newline",
+			opts: []Option{WithLineContinuation("")},
+			want: "curl -X 'POST' 'https://localhost/test'\n-H 'X-Key: 1'\n-d 'key=value'",
+		},
+		{
+			name: "overridden by multiline",
+			opts: []Option{WithLineContinuation(""), WithPowerShellMultiLine()},
+			want: "curl -X 'POST' 'https://localhost/test' `\n-H 'X-Key: 1' `\n-d 'key=value'",
+		},
+		{
+			name: "overriding multiline",
+			opts: []Option{WithWindowsMultiLine(), WithLineContinuation(" ^\n  ")},
+			want: "curl -X 'POST' 'https://localhost/test' ^\n  -H 'X-Key: 1' ^\n  -d 'key=value'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFaithful(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "/a/../b",
+	}
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		opts []Option
+		want string
+	}{
+		{
+			name: "raw headers and body",
+			r: &http.Request{
+				Method: http.MethodPost,
+				URL:    testUrl,
+				Header: http.Header{
+					"x-lower":      {"1"},
+					"X-Repeated":   {"b", "a"},
+					"Content-Type": {formContentType},
+				},
+				Body: readCloser("a=1&b=2"),
+			},
+			opts: []Option{WithFormEncoding(), WithMinimal(), WithFaithful()},
+			want: "curl --path-as-is -g -X 'POST' 'https://localhost/a/../b' " +
+				"-H 'Content-Type: application/x-www-form-urlencoded' -H 'X-Repeated: b' -H 'X-Repeated: a' -H 'x-lower: 1' " +
+				"-H 'Content-Length: 7' --data-binary 'a=1&b=2'",
+		},
+		{
+			name: "declared content length",
+			r: &http.Request{
+				Method: http.MethodPut,
+				URL:    testUrl,
+				Header: http.Header{"Content-Length": {"2"}},
+				Body:   readCloser("{}"),
+			},
+			opts: []Option{WithFaithful(), WithLongForm()},
+			want: "curl --path-as-is --globoff --request 'PUT' 'https://localhost/a/../b' " +
+				"--header 'Content-Length: 2' --data-binary '{}'",
+		},
+		{
+			name: "disabled by minimal",
+			r: &http.Request{
+				URL:    testUrl,
+				Header: http.Header{"X-Key": {"1"}},
+			},
+			opts: []Option{WithFaithful(), WithMinimal()},
+			want: "curl 'https://localhost/a/../b'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithExtraFlags(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "bare arguments",
+			opts: []Option{WithSilent(), WithExtraFlags("--http2", "--retry", "3")},
+			want: "curl -s --http2 --retry 3 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "quoted arguments",
+			opts: []Option{WithExtraFlags("--user-agent", "my agent; v1"), WithExtraFlags("-w", "%{http_code}")},
+			want: "curl --user-agent 'my agent; v1' -w '%{http_code}' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "double quotes",
+			opts: []Option{WithDoubleQuotes(), WithExtraFlags("--proxy-header", "X-Name: \"a\"")},
+			want: "curl --proxy-header \"X-Name: \\\"a\\\"\" -X \"GET\" \"https://localhost/test\"",
+		},
+		{
+			name: "minimal",
+			opts: []Option{WithMinimal(), WithSilent(), WithExtraFlags("--http2")},
+			want: "curl --http2 'https://localhost/test'",
+		},
+		{
+			name: "flag grouping",
+			opts: []Option{WithFlagGrouping(), WithExtraFlags("--http2")},
+			want: "curl 'https://localhost/test' -X 'GET' --http2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(&http.Request{URL: testUrl}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
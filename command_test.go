@@ -3,11 +3,25 @@ package curling
 import (
 	"fmt"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
+// cmpCommand compares the rendered tokens and the applied options of two commands.
+// The parsed request model is covered by the parseRequest tests,
+// the capture time depends on the clock, the args mirror the tokens
+// and the options are compared through their effects.
+var cmpCommand = cmp.Options{
+	cmp.AllowUnexported(Command{}),
+	cmpopts.IgnoreFields(Command{}, "request", "source", "opts", "once", "capturedAt", "args", "metaMu"),
+}
+
 // A readerWithError is a fake reader, so the [Read] method return always an error
 type readerWithError struct{}
 
@@ -22,11 +36,17 @@ func (r readerWithError) Read(p []byte) (n int, err error) {
 	return 0, fmt.Errorf("error reading data")
 }
 
+// readCloser returns a request body that reads s.
+func readCloser(s string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(s))
+}
+
 func TestCommand_String(t *testing.T) {
 	type fields struct {
 		tokens           []string
 		useMultiLine     bool
 		lineContinuation string
+		groupFlags       bool
 	}
 	tests := []struct {
 		name   string
@@ -81,6 +101,21 @@ func TestCommand_String(t *testing.T) {
 			},
 			want: "curl -X 'POST' 'https://localhost/test' \\\n-H 'X-Key-1: 1' \\\n-d 'key=value'",
 		},
+		{
+			name: "multiline with flag grouping",
+			fields: fields{
+				tokens: []string{
+					"curl 'https://localhost/test'",
+					"-X 'POST'",
+					"-d 'key=value'",
+					"-s -L",
+				},
+				useMultiLine:     true,
+				lineContinuation: lineContinuationDefault,
+				groupFlags:       true,
+			},
+			want: "curl 'https://localhost/test' \\\n  -X 'POST' \\\n  -d 'key=value' \\\n  -s -L",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -88,6 +123,7 @@ func TestCommand_String(t *testing.T) {
 				tokens:           tt.fields.tokens,
 				useMultiLine:     tt.fields.useMultiLine,
 				lineContinuation: tt.fields.lineContinuation,
+				groupFlags:       tt.fields.groupFlags,
 			}
 			if got := c.String(); got != tt.want {
 				t.Errorf("String() = %v, want %v", got, tt.want)
@@ -96,6 +132,168 @@ func TestCommand_String(t *testing.T) {
 	}
 }
 
+func TestCommand_JSONString(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []string
+		want   string
+	}{
+		{
+			name:   "plain command",
+			tokens: []string{"curl -X 'GET' 'https://localhost/test'"},
+			want:   `"curl -X 'GET' 'https://localhost/test'"`,
+		},
+		{
+			name:   "double quotes and backslashes",
+			tokens: []string{`curl -X "POST" "https://localhost/test"`, `-d "{\"key\": \"<value>\"}"`},
+			want:   `"curl -X \"POST\" \"https://localhost/test\" -d \"{\\\"key\\\": \\\"<value>\\\"}\""`,
+		},
+		{
+			name:   "control characters",
+			tokens: []string{"curl", "-d 'a\tb\nc\x00\u2028'"},
+			want:   `"curl -d 'a\tb\nc\u0000\u2028'"`,
+		},
+		{
+			name:   "invalid utf-8",
+			tokens: []string{"curl", "-d '\xff'"},
+			want:   "\"curl -d '\ufffd'\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Command{tokens: tt.tokens}
+			if got := c.JSONString(); got != tt.want {
+				t.Errorf("JSONString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_Args(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   string
+		opts   []Option
+		want   []string
+	}{
+		{
+			name: "without body",
+			want: []string{"curl", "-X", "GET", "https://localhost/test"},
+		},
+		{
+			name:   "headers, body and options",
+			header: http.Header{"X-Quote": {"it's"}},
+			body:   `{"key": "value"}`,
+			opts:   []Option{WithSilent(), WithRequestTimeout(5), WithExtraFlags("--retry", "3"), WithLongForm()},
+			want: []string{
+				"curl", "--silent", "--max-time", "5", "--retry", "3", "--request", "POST", "https://localhost/test",
+				"--header", "X-Quote: it's", "--data", `{"key": "value"}`,
+			},
+		},
+		{
+			name:   "quoting and placeholders",
+			header: http.Header{"Authorization": {"Bearer s3cr3t"}},
+			opts:   []Option{WithDoubleQuotes(), WithPlaceholders(map[string]string{"TOKEN": "s3cr3t"})},
+			want:   []string{"curl", "-X", "GET", "https://localhost/test", "-H", "Authorization: Bearer s3cr3t"},
+		},
+		{
+			name: "flag grouping",
+			opts: []Option{WithFlagGrouping(), WithInsecure()},
+			want: []string{"curl", "https://localhost/test", "-X", "GET", "-k"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{URL: testUrl, Header: tt.header}
+			if tt.body != "" {
+				r.Method = http.MethodPost
+				r.Body = readCloser(tt.body)
+			}
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.Args(); !cmp.Equal(got, tt.want) {
+				t.Errorf("Args() diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCommand_With(t *testing.T) {
+	newRequest := func() *http.Request {
+		return &http.Request{
+			Method: http.MethodPost,
+			URL:    &url.URL{Scheme: "https", Host: "localhost", Path: "test"},
+			Header: http.Header{"Authorization": {"Bearer s3cr3t"}},
+			Body:   readCloser("key=value"),
+		}
+	}
+
+	c, err := NewFromRequest(newRequest(), WithSilent())
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+	original := c.String()
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "no options",
+		},
+		{
+			name: "long form and powershell quoting",
+			opts: []Option{WithLongForm(), WithQuoter(PowerShellQuoter)},
+		},
+		{
+			name: "options changing the request",
+			opts: []Option{WithRedactedHeaders("Authorization"), WithMaxBodySize(3), WithScrubber(JWTScrubber)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.With(tt.opts...)
+			if err != nil {
+				t.Fatalf("With() error = %v", err)
+			}
+
+			want, err := NewFromRequest(newRequest(), append([]Option{WithSilent()}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got.String() != want.String() {
+				t.Errorf("With() = %v, want %v", got, want)
+			}
+			if !cmp.Equal(got.Validate(), want.Validate()) {
+				t.Errorf("With() warnings diff = %v", cmp.Diff(got.Validate(), want.Validate()))
+			}
+			if c.String() != original {
+				t.Errorf("With() changed the command to %v, want %v", c, original)
+			}
+		})
+	}
+
+	again, err := c.With()
+	if err != nil {
+		t.Fatalf("With() error = %v", err)
+	}
+	if again.String() != original {
+		t.Errorf("With() = %v after the variants, want %v", again, original)
+	}
+}
+
 func TestCommand_optionForm(t *testing.T) {
 	type fields struct {
 		useLongForm bool
@@ -224,6 +422,39 @@ func TestCommand_escape(t *testing.T) {
 	}
 }
 
+func TestCommand_lazyConstruction(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", WithSilent())
+	if c.tokens != nil {
+		t.Fatalf("tokens = %v, want them produced when first needed", c.tokens)
+	}
+
+	want := "curl -s -X 'GET' 'https://localhost/test'"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := c.String(); got != want {
+				t.Errorf("String() = %v, want %v", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(c.tokens); got != 1 {
+		t.Errorf("len(tokens) = %d after concurrent renderings, want 1", got)
+	}
+
+	spilled, err := NewFromRequest(&http.Request{
+		URL:    &url.URL{Scheme: "https", Host: "localhost"},
+		Header: http.Header{"X-Key": {"1"}},
+	}, WithHeaderFile(filepath.Join(t.TempDir(), "missing", "headers.txt")))
+	if err == nil {
+		t.Errorf("NewFromRequest() = %v, want the side file error", spilled)
+	}
+}
+
 func Test_NewFromRequest(t *testing.T) {
 	type args struct {
 		r    *http.Request
@@ -266,10 +497,165 @@ func Test_NewFromRequest(t *testing.T) {
 				return
 			}
 
-			optUnexported := cmp.AllowUnexported(Command{})
+			if got != nil {
+				got.ensureConstructed()
+			}
+
+			optUnexported := cmpCommand
 			if !cmp.Equal(got, tt.want, optUnexported) {
 				t.Errorf("NewFromRequest() got = %v, want = %v, diff = %v", got, tt.want, cmp.Diff(got, tt.want, optUnexported))
 			}
 		})
 	}
 }
+
+func Test_NewFromParts(t *testing.T) {
+	type args struct {
+		method string
+		rawurl string
+		header http.Header
+		body   io.Reader
+		opts   []Option
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "invalid url",
+			args: args{
+				rawurl: "://localhost",
+			},
+			wantErr: true,
+		},
+		{
+			name: "error reading body",
+			args: args{
+				rawurl: "https://localhost/test",
+				body:   readerWithError{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "nil body",
+			args: args{
+				rawurl: "https://localhost/test",
+			},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "all parts",
+			args: args{
+				method: http.MethodPost,
+				rawurl: "https://localhost/test",
+				header: http.Header{"X-Key": {"1"}},
+				body:   strings.NewReader("key=value"),
+				opts:   []Option{WithLongForm()},
+			},
+			want: "curl --request 'POST' 'https://localhost/test' --header 'X-Key: 1' --data 'key=value'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFromParts(tt.args.method, tt.args.rawurl, tt.args.header, tt.args.body, tt.args.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromParts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("NewFromParts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NewFromRequestLenient(t *testing.T) {
+	tests := []struct {
+		name      string
+		r         *http.Request
+		want      string
+		wantCodes []WarningCode
+		wantErr   bool
+	}{
+		{
+			name:    "invalid url",
+			r:       &http.Request{},
+			wantErr: true,
+		},
+		{
+			name: "partial body",
+			r: &http.Request{
+				Method: http.MethodPost,
+				URL:    &url.URL{Scheme: "https", Host: "localhost", Path: "/test"},
+				Body:   io.NopCloser(io.MultiReader(strings.NewReader("key=va"), readerWithError{})),
+			},
+			want:      "curl -X 'POST' 'https://localhost/test' -d 'key=va'",
+			wantCodes: []WarningCode{WarningUnreadableBody},
+		},
+		{
+			name: "invalid cookie",
+			r: &http.Request{
+				URL:    &url.URL{Scheme: "https", Host: "localhost", Path: "/test"},
+				Header: http.Header{"Cookie": {"a=1; b c=2"}},
+			},
+			want:      "curl -X 'GET' 'https://localhost/test' -H 'Cookie: a=1; b c=2'",
+			wantCodes: []WarningCode{WarningInvalidCookie},
+		},
+		{
+			name: "no warnings",
+			r: &http.Request{
+				URL: &url.URL{Scheme: "https", Host: "localhost", Path: "/test"},
+			},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := NewFromRequestLenient(tt.r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromRequestLenient() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("NewFromRequestLenient() = %v, want %v", got, tt.want)
+			}
+
+			var codes []WarningCode
+			for _, w := range warnings {
+				codes = append(codes, w.Code)
+			}
+			if !cmp.Equal(codes, tt.wantCodes) {
+				t.Errorf("NewFromRequestLenient() warnings diff = %v", cmp.Diff(codes, tt.wantCodes))
+			}
+		})
+	}
+}
+
+func BenchmarkNewFromRequest(b *testing.B) {
+	opts := []Option{WithSilent(), WithCompression(), WithFollowRedirects()}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r, _ := http.NewRequest(http.MethodPost, "https://localhost/api/v1/items?page=2&sort=desc", strings.NewReader(`{"name":"it's a test","tags":["a","b"]}`))
+		r.Header.Set("Accept", "application/json")
+		r.Header.Set("Authorization", "Bearer s3cr3t")
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("User-Agent", "curling-bench/1.0")
+
+		c, err := NewFromRequest(r, opts...)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = c.String()
+	}
+}
@@ -0,0 +1,138 @@
+package curling
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// A CommandSet is an ordered sequence of commands, such as a captured session.
+type CommandSet []*Command
+
+// A ScriptOption configures the script produced by [CommandSet.Script].
+type ScriptOption func(config *scriptConfig)
+
+// scriptConfig holds the settings of a script.
+type scriptConfig struct {
+	// connectionReuse merges consecutive cURL commands to the same origin.
+	connectionReuse bool
+
+	// tokenRefresh replaces the expiring bearer tokens.
+	tokenRefresh string
+
+	// staleAt is the replay time the staleness of the requests is checked against, none when zero.
+	staleAt time.Time
+}
+
+// WithConnectionReuse merges consecutive cURL commands to the same origin
+// into a single invocation, separating the requests with --next, so that
+// the replay reuses the connection as a keep-alive client does.
+// Commands that can't be merged, because they use another renderer,
+// are annotated with a comment instead.
+func WithConnectionReuse() ScriptOption {
+	return func(config *scriptConfig) {
+		config.connectionReuse = true
+	}
+}
+
+// Script returns a POSIX shell script that replays the commands in order.
+func (s CommandSet) Script(opts ...ScriptOption) string {
+	var config scriptConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	blocks := make([]string, 0, len(s))
+	for i := 0; i < len(s); {
+		n := 1
+		if config.connectionReuse {
+			for i+n < len(s) && s[i+n].origin() == s[i].origin() {
+				n++
+			}
+		}
+
+		blocks = append(blocks, s[i:i+n].scriptBlock(config))
+		i += n
+	}
+
+	if len(blocks) == 0 {
+		return "#!/bin/sh\n"
+	}
+
+	return "#!/bin/sh\n\n" + strings.Join(blocks, "\n\n") + "\n"
+}
+
+// scriptBlock renders commands sharing the same origin as a single script block.
+func (s CommandSet) scriptBlock(config scriptConfig) string {
+	if len(s) == 1 {
+		command, comment := config.refreshToken(s[0], s[0].String())
+		return withComments(append(config.staleComments(s[0]), comment), command)
+	}
+
+	for _, c := range s {
+		if !c.rendersCurl() {
+			lines := make([]string, 0, len(s)+1)
+			lines = append(lines, "# The following requests share the origin "+s[0].origin()+
+				", replay them with a client that keeps the connection alive.")
+			for _, c := range s {
+				command, comment := config.refreshToken(c, c.String())
+				lines = append(lines, withComments(append(config.staleComments(c), comment), command))
+			}
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	var preamble []string
+	var tokens []string
+
+	for i, c := range s {
+		preamble = append(preamble, config.staleComments(c)...)
+
+		for _, line := range strings.Split(c.decorate(""), "\n") {
+			if line != "" && !slices.Contains(preamble, line) {
+				preamble = append(preamble, line)
+			}
+		}
+
+		c.ensureConstructed()
+		commandTokens := make([]string, len(c.tokens))
+		for j, token := range c.tokens {
+			var comment string
+			commandTokens[j], comment = config.refreshToken(c, token)
+			if comment != "" && !slices.Contains(preamble, comment) {
+				preamble = append(preamble, comment)
+			}
+		}
+
+		if i == 0 {
+			tokens = append(tokens, commandTokens...)
+			continue
+		}
+
+		tokens = append(tokens, "--next "+strings.TrimPrefix(commandTokens[0], "curl "))
+		tokens = append(tokens, commandTokens[1:]...)
+	}
+
+	return withComments(preamble, s[0].join(tokens))
+}
+
+// withComments returns command preceded by the non-empty comment lines.
+func withComments(comments []string, command string) string {
+	var lines []string
+	for _, comment := range comments {
+		if comment != "" {
+			lines = append(lines, comment)
+		}
+	}
+
+	if len(lines) == 0 {
+		return command
+	}
+
+	return strings.Join(lines, "\n") + "\n" + command
+}
+
+// origin returns the scheme and host the command sends the request to.
+func (c *Command) origin() string {
+	return c.request.url.Scheme + "://" + c.request.url.Host
+}
@@ -0,0 +1,103 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// mustNewFromRequest returns a new command or stops the test on error.
+func mustNewFromRequest(t *testing.T, method, rawurl string, opts ...Option) *Command {
+	t.Helper()
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		t.Fatalf("parsing url: %v", err)
+	}
+
+	c, err := NewFromRequest(&http.Request{Method: method, URL: u}, opts...)
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	return c
+}
+
+func TestCommandSet_Script(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(t *testing.T) CommandSet
+		opts []ScriptOption
+		want string
+	}{
+		{
+			name: "empty set",
+			set: func(t *testing.T) CommandSet {
+				return nil
+			},
+			want: "#!/bin/sh\n",
+		},
+		{
+			name: "without connection reuse",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/a"),
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/b"),
+				}
+			},
+			want: "#!/bin/sh\n\n" +
+				"curl -X 'GET' 'https://localhost/a'\n\n" +
+				"curl -X 'GET' 'https://localhost/b'\n",
+		},
+		{
+			name: "with connection reuse",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/a", WithSilent()),
+					mustNewFromRequest(t, http.MethodPost, "https://localhost/b", WithSilent()),
+					mustNewFromRequest(t, http.MethodGet, "https://example.com/c"),
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/d"),
+				}
+			},
+			opts: []ScriptOption{WithConnectionReuse()},
+			want: "#!/bin/sh\n\n" +
+				"curl -s -X 'GET' 'https://localhost/a' --next -s -X 'POST' 'https://localhost/b'\n\n" +
+				"curl -X 'GET' 'https://example.com/c'\n\n" +
+				"curl -X 'GET' 'https://localhost/d'\n",
+		},
+		{
+			name: "with connection reuse and multiline",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/a", WithWindowsMultiLine(), WithInlineWarnings()),
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/b", WithWindowsMultiLine(), WithInlineWarnings()),
+				}
+			},
+			opts: []ScriptOption{WithConnectionReuse()},
+			want: "#!/bin/sh\n\n" +
+				"REM WARNING: the Windows shell does not support single quotes, use double quotes\n" +
+				"curl -X 'GET' 'https://localhost/a' ^\n--next -X 'GET' 'https://localhost/b'\n",
+		},
+		{
+			name: "with connection reuse and another renderer",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/a", WithRenderer(Wget)),
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/b"),
+				}
+			},
+			opts: []ScriptOption{WithConnectionReuse()},
+			want: "#!/bin/sh\n\n" +
+				"# The following requests share the origin https://localhost, replay them with a client that keeps the connection alive.\n" +
+				"wget -O - --method 'GET' 'https://localhost/a'\n" +
+				"curl -X 'GET' 'https://localhost/b'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set(t).Script(tt.opts...); got != tt.want {
+				t.Errorf("Script() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
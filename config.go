@@ -0,0 +1,444 @@
+package curling
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// A Config holds the serializable settings of a conversion, so that services
+// can persist and ship them, such as in a policy file, instead of composing options.
+// The options taking functions or interfaces, such as [WithHeaderRedactor],
+// [WithScrubber], [WithQuoter] and [WithRenderer], have no field.
+type Config struct {
+	// LongForm enables [WithLongForm].
+	LongForm bool `json:"longForm,omitempty"`
+
+	// FollowRedirects enables [WithFollowRedirects].
+	FollowRedirects bool `json:"followRedirects,omitempty"`
+
+	// MaxRedirects is the value of [WithMaxRedirects].
+	MaxRedirects int `json:"maxRedirects,omitempty"`
+
+	// Compressed enables [WithCompression].
+	Compressed bool `json:"compressed,omitempty"`
+
+	// Insecure enables [WithInsecure].
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Silent enables [WithSilent].
+	Silent bool `json:"silent,omitempty"`
+
+	// RequestTimeout is the value of [WithRequestTimeout], in seconds.
+	RequestTimeout int `json:"requestTimeout,omitempty"`
+
+	// RequestTimeoutDuration is the value of [WithRequestTimeoutDuration],
+	// set instead of RequestTimeout for timeouts that aren't whole seconds.
+	RequestTimeoutDuration time.Duration `json:"requestTimeoutDuration,omitempty"`
+
+	// Verbose enables [WithVerbose].
+	Verbose bool `json:"verbose,omitempty"`
+
+	// IncludeResponseHeaders enables [WithIncludeResponseHeaders].
+	IncludeResponseHeaders bool `json:"includeResponseHeaders,omitempty"`
+
+	// FailFast enables [WithFailFast].
+	FailFast bool `json:"failFast,omitempty"`
+
+	// FailWithBody enables [WithFailWithBody].
+	FailWithBody bool `json:"failWithBody,omitempty"`
+
+	// AutoConfigFile enables [WithAutoConfigFile].
+	AutoConfigFile bool `json:"autoConfigFile,omitempty"`
+
+	// AutoConfigDir is the dir of [WithAutoConfigFile].
+	AutoConfigDir string `json:"autoConfigDir,omitempty"`
+
+	// ContextTimeout enables [WithContextTimeout].
+	ContextTimeout bool `json:"contextTimeout,omitempty"`
+
+	// OutputFile is the value of [WithOutputFile].
+	OutputFile string `json:"outputFile,omitempty"`
+
+	// RemoteName enables [WithRemoteName].
+	RemoteName bool `json:"remoteName,omitempty"`
+
+	// OutputDir is the value of [WithOutputDir].
+	OutputDir string `json:"outputDir,omitempty"`
+
+	// CreateDirs enables [WithCreateDirs].
+	CreateDirs bool `json:"createDirs,omitempty"`
+
+	// ConnectTimeout is the value of [WithConnectTimeout].
+	ConnectTimeout time.Duration `json:"connectTimeout,omitempty"`
+
+	// DoubleQuotes enables [WithDoubleQuotes].
+	DoubleQuotes bool `json:"doubleQuotes,omitempty"`
+
+	// ExtraFlags are the arguments of [WithExtraFlags].
+	ExtraFlags []string `json:"extraFlags,omitempty"`
+
+	// MultiLine is the shell targeted by a multiline snippet, one of ShellPOSIX,
+	// ShellWindows and ShellPowerShell, or empty for a single line.
+	MultiLine string `json:"multiLine,omitempty"`
+
+	// LineSeparator is the separator of [WithLineContinuation], it takes precedence over MultiLine.
+	LineSeparator string `json:"lineSeparator,omitempty"`
+
+	// FlagGrouping enables [WithFlagGrouping].
+	FlagGrouping bool `json:"flagGrouping,omitempty"`
+
+	// FormEncoding enables [WithFormEncoding].
+	FormEncoding bool `json:"formEncoding,omitempty"`
+
+	// JSONFlag enables [WithJSONFlag].
+	JSONFlag bool `json:"jsonFlag,omitempty"`
+
+	// Minimal enables [WithMinimal].
+	Minimal bool `json:"minimal,omitempty"`
+
+	// Faithful enables [WithFaithful], it takes precedence over Minimal.
+	Faithful bool `json:"faithful,omitempty"`
+
+	// IdiomaticFlags enables [WithIdiomaticFlags].
+	IdiomaticFlags bool `json:"idiomaticFlags,omitempty"`
+
+	// UserAgentFlag enables [WithUserAgentFlag].
+	UserAgentFlag bool `json:"userAgentFlag,omitempty"`
+
+	// ForceScheme is the scheme of [WithForceHTTPS] or [WithForceHTTP].
+	ForceScheme string `json:"forceScheme,omitempty"`
+
+	// InsecureOnRewrite enables [WithInsecureOnRewrite].
+	InsecureOnRewrite bool `json:"insecureOnRewrite,omitempty"`
+
+	// CookieJar is the value of [WithCookieJar].
+	CookieJar string `json:"cookieJar,omitempty"`
+
+	// CookieJarLoad enables [WithCookieJarLoad].
+	CookieJarLoad bool `json:"cookieJarLoad,omitempty"`
+
+	// JunkSessionCookies enables [WithJunkSessionCookies].
+	JunkSessionCookies bool `json:"junkSessionCookies,omitempty"`
+
+	// RefererFlag enables [WithRefererFlag].
+	RefererFlag bool `json:"refererFlag,omitempty"`
+
+	// EnvFile is the value of [WithEnvFile].
+	EnvFile string `json:"envFile,omitempty"`
+
+	// OAuth2BearerFlag enables [WithOAuth2BearerFlag].
+	OAuth2BearerFlag bool `json:"oauth2BearerFlag,omitempty"`
+
+	// DecodedBody enables [WithDecodedBody].
+	DecodedBody bool `json:"decodedBody,omitempty"`
+
+	// InlineWarnings enables [WithInlineWarnings].
+	InlineWarnings bool `json:"inlineWarnings,omitempty"`
+
+	// BodyKindComment enables [WithBodyKindComment].
+	BodyKindComment bool `json:"bodyKindComment,omitempty"`
+
+	// OutputVersion is the value of [WithOutputVersion].
+	OutputVersion int `json:"outputVersion,omitempty"`
+
+	// Proxy is the value of [WithProxy].
+	Proxy string `json:"proxy,omitempty"`
+
+	// StripManagedHeaders enables [WithStripManagedHeaders].
+	StripManagedHeaders bool `json:"stripManagedHeaders,omitempty"`
+
+	// AuthScheme is the value of [WithAuthScheme].
+	AuthScheme AuthScheme `json:"authScheme,omitempty"`
+
+	// AuthUser is the user of [WithAuthUser].
+	// The password has no field, so that it is never persisted.
+	AuthUser string `json:"authUser,omitempty"`
+
+	// ProxyUser is the user of [WithProxyUser].
+	// The password has no field, so that it is never persisted.
+	ProxyUser string `json:"proxyUser,omitempty"`
+
+	// NoProxy holds the hosts of [WithNoProxy].
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// SOCKS5 is the value of [WithSOCKS5].
+	SOCKS5 string `json:"socks5,omitempty"`
+
+	// SOCKS5Hostname is the value of [WithSOCKS5Hostname], it takes precedence over SOCKS5.
+	SOCKS5Hostname string `json:"socks5Hostname,omitempty"`
+
+	// ProxyFromEnvironment enables [WithProxyFromEnvironment].
+	ProxyFromEnvironment bool `json:"proxyFromEnvironment,omitempty"`
+
+	// ClientCert and ClientKey are the values of [WithClientCert].
+	// The password of [WithCertPassword] has no field, so that it is never persisted.
+	ClientCert string `json:"clientCert,omitempty"`
+	ClientKey  string `json:"clientKey,omitempty"`
+
+	// CACert is the value of [WithCACert].
+	CACert string `json:"caCert,omitempty"`
+
+	// MaxBodySize is the value of [WithMaxBodySize].
+	MaxBodySize int `json:"maxBodySize,omitempty"`
+
+	// MaxCaptureSize is the value of [WithMaxCaptureSize].
+	MaxCaptureSize int `json:"maxCaptureSize,omitempty"`
+
+	// MaxOutputSize is the value of [WithMaxOutputSize].
+	MaxOutputSize int `json:"maxOutputSize,omitempty"`
+
+	// HeaderSpillSize and HeaderSpillDir are the values of [WithHeaderSpill].
+	HeaderSpillSize int    `json:"headerSpillSize,omitempty"`
+	HeaderSpillDir  string `json:"headerSpillDir,omitempty"`
+
+	// HeaderFile is the value of [WithHeaderFile].
+	HeaderFile string `json:"headerFile,omitempty"`
+
+	// BinaryBodyFiles writes binary bodies to files in BinaryBodyDir, see [WithBinaryBodyDir].
+	BinaryBodyFiles bool   `json:"binaryBodyFiles,omitempty"`
+	BinaryBodyDir   string `json:"binaryBodyDir,omitempty"`
+
+	// BodyToFile writes every body to BodyFile, see [WithBodyToFile].
+	BodyToFile bool   `json:"bodyToFile,omitempty"`
+	BodyFile   string `json:"bodyFile,omitempty"`
+
+	// Placeholders are the variables of [WithPlaceholders].
+	Placeholders map[string]string `json:"placeholders,omitempty"`
+
+	// RedactedHeaders are the keys of [WithRedactedHeaders].
+	RedactedHeaders []string `json:"redactedHeaders,omitempty"`
+
+	// Retry is the value of [WithRetry].
+	Retry int `json:"retry,omitempty"`
+
+	// RetryDelay is the value of [WithRetryDelay].
+	RetryDelay time.Duration `json:"retryDelay,omitempty"`
+
+	// RetryMaxTime is the value of [WithRetryMaxTime].
+	RetryMaxTime time.Duration `json:"retryMaxTime,omitempty"`
+
+	// RetryAllErrors enables [WithRetryAllErrors].
+	RetryAllErrors bool `json:"retryAllErrors,omitempty"`
+
+	// CookieOrder is the value of [WithCookieOrder].
+	CookieOrder CookieOrder `json:"cookieOrder,omitempty"`
+
+	// HTTPVersion is the value of [WithHTTPVersion].
+	HTTPVersion HTTPVersion `json:"httpVersion,omitempty"`
+
+	// InferredHTTPVersion enables [WithInferredHTTPVersion], it takes precedence over HTTPVersion.
+	InferredHTTPVersion bool `json:"inferredHttpVersion,omitempty"`
+
+	// HeaderEncoding is the value of [WithHeaderEncoding].
+	HeaderEncoding HeaderEncoding `json:"headerEncoding,omitempty"`
+}
+
+// WithConfig applies the settings of cfg, as the related options would.
+// Zero values leave the related settings untouched.
+func WithConfig(cfg Config) Option {
+	return func(curling *Command) {
+		for _, opt := range cfg.options() {
+			opt(curling)
+		}
+	}
+}
+
+// options returns the options applying the settings of cfg.
+func (cfg Config) options() []Option {
+	var opts []Option
+
+	flags := []struct {
+		set bool
+		opt Option
+	}{
+		{cfg.LongForm, WithLongForm()},
+		{cfg.FollowRedirects, WithFollowRedirects()},
+		{cfg.MaxRedirects != 0, WithMaxRedirects(cfg.MaxRedirects)},
+		{cfg.Compressed, WithCompression()},
+		{cfg.Insecure, WithInsecure()},
+		{cfg.Silent, WithSilent()},
+		{cfg.DoubleQuotes, WithDoubleQuotes()},
+		{cfg.MultiLine == ShellPOSIX, WithMultiLine()},
+		{cfg.MultiLine == ShellWindows, WithWindowsMultiLine()},
+		{cfg.MultiLine == ShellPowerShell, WithPowerShellMultiLine()},
+		{cfg.LineSeparator != "", WithLineContinuation(cfg.LineSeparator)},
+		{cfg.FlagGrouping, WithFlagGrouping()},
+		{cfg.FormEncoding, WithFormEncoding()},
+		{cfg.JSONFlag, WithJSONFlag()},
+		{cfg.Minimal, WithMinimal()},
+		{cfg.Faithful, WithFaithful()},
+		{cfg.IdiomaticFlags, WithIdiomaticFlags()},
+		{cfg.UserAgentFlag, WithUserAgentFlag()},
+		{cfg.RefererFlag, WithRefererFlag()},
+		{cfg.OAuth2BearerFlag, WithOAuth2BearerFlag()},
+		{cfg.EnvFile != "", WithEnvFile(cfg.EnvFile)},
+		{cfg.CookieJar != "", WithCookieJar(cfg.CookieJar)},
+		{cfg.CookieJarLoad, WithCookieJarLoad()},
+		{cfg.JunkSessionCookies, WithJunkSessionCookies()},
+		{cfg.ForceScheme == "https", WithForceHTTPS()},
+		{cfg.ForceScheme == "http", WithForceHTTP()},
+		{cfg.InsecureOnRewrite, WithInsecureOnRewrite()},
+		{cfg.DecodedBody, WithDecodedBody()},
+		{cfg.InlineWarnings, WithInlineWarnings()},
+		{cfg.BodyKindComment, WithBodyKindComment()},
+		{cfg.BinaryBodyFiles, WithBinaryBodyDir(cfg.BinaryBodyDir)},
+		{cfg.BodyToFile, WithBodyToFile(cfg.BodyFile)},
+		{cfg.Placeholders != nil, WithPlaceholders(cfg.Placeholders)},
+		{len(cfg.RedactedHeaders) > 0, WithRedactedHeaders(cfg.RedactedHeaders...)},
+		{len(cfg.ExtraFlags) > 0, WithExtraFlags(cfg.ExtraFlags...)},
+		{cfg.RequestTimeout != 0, WithRequestTimeout(cfg.RequestTimeout)},
+		{cfg.RequestTimeoutDuration != 0, WithRequestTimeoutDuration(cfg.RequestTimeoutDuration)},
+		{cfg.Verbose, WithVerbose()},
+		{cfg.IncludeResponseHeaders, WithIncludeResponseHeaders()},
+		{cfg.FailFast, WithFailFast()},
+		{cfg.FailWithBody, WithFailWithBody()},
+		{cfg.AutoConfigFile, WithAutoConfigFile(cfg.AutoConfigDir)},
+		{cfg.ContextTimeout, WithContextTimeout()},
+		{cfg.OutputFile != "", WithOutputFile(cfg.OutputFile)},
+		{cfg.RemoteName, WithRemoteName()},
+		{cfg.OutputDir != "", WithOutputDir(cfg.OutputDir)},
+		{cfg.CreateDirs, WithCreateDirs()},
+		{cfg.ConnectTimeout != 0, WithConnectTimeout(cfg.ConnectTimeout)},
+		{cfg.OutputVersion != 0, WithOutputVersion(cfg.OutputVersion)},
+		{cfg.Proxy != "", WithProxy(cfg.Proxy)},
+		{cfg.ProxyUser != "", WithProxyUser(cfg.ProxyUser, "")},
+		{cfg.AuthScheme != "", WithAuthScheme(cfg.AuthScheme)},
+		{cfg.StripManagedHeaders, WithStripManagedHeaders()},
+		{cfg.AuthUser != "", WithAuthUser(cfg.AuthUser, "")},
+		{len(cfg.NoProxy) > 0, WithNoProxy(cfg.NoProxy...)},
+		{cfg.SOCKS5 != "", WithSOCKS5(cfg.SOCKS5)},
+		{cfg.SOCKS5Hostname != "", WithSOCKS5Hostname(cfg.SOCKS5Hostname)},
+		{cfg.ProxyFromEnvironment, WithProxyFromEnvironment()},
+		{cfg.ClientCert != "", WithClientCert(cfg.ClientCert, cfg.ClientKey)},
+		{cfg.CACert != "", WithCACert(cfg.CACert)},
+		{cfg.MaxBodySize != 0, WithMaxBodySize(cfg.MaxBodySize)},
+		{cfg.MaxCaptureSize != 0, WithMaxCaptureSize(cfg.MaxCaptureSize)},
+		{cfg.MaxOutputSize != 0, WithMaxOutputSize(cfg.MaxOutputSize)},
+		{cfg.HeaderSpillSize != 0, WithHeaderSpill(cfg.HeaderSpillSize, cfg.HeaderSpillDir)},
+		{cfg.HeaderFile != "", WithHeaderFile(cfg.HeaderFile)},
+		{cfg.HeaderEncoding != HeaderEncodingRaw, WithHeaderEncoding(cfg.HeaderEncoding)},
+		{cfg.HTTPVersion != HTTPVersionDefault, WithHTTPVersion(cfg.HTTPVersion)},
+		{cfg.CookieOrder != CookieOrderWire, WithCookieOrder(cfg.CookieOrder)},
+		{cfg.Retry != 0, WithRetry(cfg.Retry)},
+		{cfg.RetryDelay != 0, WithRetryDelay(cfg.RetryDelay)},
+		{cfg.RetryMaxTime != 0, WithRetryMaxTime(cfg.RetryMaxTime)},
+		{cfg.RetryAllErrors, WithRetryAllErrors()},
+		{cfg.InferredHTTPVersion, WithInferredHTTPVersion()},
+	}
+
+	for _, f := range flags {
+		if f.set {
+			opts = append(opts, f.opt)
+		}
+	}
+
+	return opts
+}
+
+// Config returns the serializable settings the command was built with.
+func (c *Command) Config() Config {
+	cfg := Config{
+		LongForm:               c.useLongForm,
+		FollowRedirects:        c.location,
+		MaxRedirects:           c.maxRedirects,
+		Compressed:             c.compressed,
+		Insecure:               c.insecure && !c.insecureFromRewrite,
+		Silent:                 c.silent,
+		ContextTimeout:         c.contextTimeout,
+		AutoConfigFile:         c.autoConfigFile,
+		AutoConfigDir:          c.autoConfigDir,
+		Verbose:                c.verbose,
+		IncludeResponseHeaders: c.include,
+		FailFast:               c.fail == failFast,
+		FailWithBody:           c.fail == failWithBody,
+		OutputFile:             c.outputFile,
+		RemoteName:             c.remoteName,
+		OutputDir:              c.outputDir,
+		CreateDirs:             c.createDirs,
+		ConnectTimeout:         c.connectTimeout,
+		DoubleQuotes:           c.useDoubleQuotes,
+		LineSeparator:          c.separator,
+		FlagGrouping:           c.groupFlags,
+		FormEncoding:           c.formEncoding,
+		JSONFlag:               c.jsonFlag,
+		Minimal:                c.minimal,
+		Faithful:               c.faithful,
+		IdiomaticFlags:         c.idiomaticFlags,
+		UserAgentFlag:          c.userAgentFlag,
+		RefererFlag:            c.refererFlag,
+		OAuth2BearerFlag:       c.oauth2BearerFlag,
+		EnvFile:                c.envFile,
+		CookieJar:              c.cookieJar,
+		CookieJarLoad:          c.cookieJarLoad,
+		JunkSessionCookies:     c.junkSessionCookies,
+		ForceScheme:            c.forceScheme,
+		InsecureOnRewrite:      c.insecureOnRewrite,
+		DecodedBody:            c.decodeBody,
+		InlineWarnings:         c.inlineWarnings,
+		BodyKindComment:        c.bodyKindComment,
+		OutputVersion:          c.outputVersion,
+		MaxBodySize:            c.maxBodySize,
+		MaxCaptureSize:         c.maxCaptureSize,
+		MaxOutputSize:          c.maxOutputSize,
+		HeaderSpillSize:        c.headerSpillSize,
+		HeaderSpillDir:         c.headerSpillDir,
+		HeaderFile:             c.headerFile,
+		BinaryBodyFiles:        c.binaryBodyFile,
+		BinaryBodyDir:          c.binaryBodyDir,
+		BodyToFile:             c.bodyToFile,
+		BodyFile:               c.bodyFile,
+		Placeholders:           maps.Clone(c.placeholderVars),
+		HeaderEncoding:         c.headerEncoding,
+		HTTPVersion:            c.httpVersion,
+		CookieOrder:            c.cookieOrder,
+		Retry:                  c.retry,
+		RetryDelay:             c.retryDelay,
+		RetryMaxTime:           c.retryMaxTime,
+		RetryAllErrors:         c.retryAllErrors,
+		InferredHTTPVersion:    c.inferHTTPVersion,
+		Proxy:                  c.proxy,
+		ProxyUser:              c.proxyUser,
+		AuthScheme:             c.authScheme,
+		StripManagedHeaders:    c.stripManagedHeaders,
+		AuthUser:               c.authUser,
+		NoProxy:                slices.Clone(c.noProxy),
+		ProxyFromEnvironment:   c.proxyFromEnv,
+	}
+
+	if c.useMultiLine && c.separator == "" {
+		cfg.MultiLine = c.shell()
+	}
+
+	if !c.timeoutFromContext {
+		if c.requestTimeout%time.Second == 0 {
+			cfg.RequestTimeout = int(c.requestTimeout / time.Second)
+		} else {
+			cfg.RequestTimeoutDuration = c.requestTimeout
+		}
+	}
+
+	if c.socksRemoteDNS {
+		cfg.SOCKS5Hostname = c.socksProxy
+	} else {
+		cfg.SOCKS5 = c.socksProxy
+	}
+
+	if !c.certInMemory {
+		cfg.ClientCert, cfg.ClientKey = c.certFile, c.keyFile
+	}
+
+	if !c.caCertInMemory {
+		cfg.CACert = c.caCertFile
+	}
+
+	if len(c.redactedHeaders) > 0 {
+		cfg.RedactedHeaders = append([]string{}, c.redactedHeaders...)
+	}
+
+	if len(c.extraArgs) > 0 {
+		cfg.ExtraFlags = append([]string{}, c.extraArgs...)
+	}
+
+	return cfg
+}
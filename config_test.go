@@ -0,0 +1,147 @@
+package curling
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCommand_Config(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want Config
+	}{
+		{
+			name: "no options",
+			want: Config{},
+		},
+		{
+			name: "options",
+			opts: []Option{
+				WithLongForm(), WithSilent(), WithRequestTimeout(5), WithCompression(), WithIdiomaticFlags(),
+				WithPowerShellMultiLine(), WithFlagGrouping(), WithMaxBodySize(3), WithMaxCaptureSize(64), WithExtraFlags("--http2"),
+				WithPlaceholders(map[string]string{"TOKEN": "s3cr3t"}), WithRedactedHeaders("x-api-key"),
+				WithHeaderEncoding(HeaderEncodingMIME), WithOutputVersion(OutputVersion1),
+			},
+			want: Config{
+				LongForm:        true,
+				Silent:          true,
+				RequestTimeout:  5,
+				Compressed:      true,
+				IdiomaticFlags:  true,
+				MultiLine:       ShellPowerShell,
+				FlagGrouping:    true,
+				MaxBodySize:     3,
+				MaxCaptureSize:  64,
+				ExtraFlags:      []string{"--http2"},
+				Placeholders:    map[string]string{"TOKEN": "s3cr3t"},
+				RedactedHeaders: []string{"X-Api-Key"},
+				HeaderEncoding:  HeaderEncodingMIME,
+				OutputVersion:   OutputVersion1,
+			},
+		},
+		{
+			name: "line continuation and files",
+			opts: []Option{
+				WithMultiLine(), WithLineContinuation(" \\\n  "), WithBodyToFile("body.json"),
+				WithBinaryBodyDir("/tmp"), WithHeaderSpill(100, "/tmp"), WithHeaderFile("headers.txt"),
+			},
+			want: Config{
+				LineSeparator:   " \\\n  ",
+				BodyToFile:      true,
+				BodyFile:        "body.json",
+				BinaryBodyFiles: true,
+				BinaryBodyDir:   "/tmp",
+				HeaderSpillSize: 100,
+				HeaderSpillDir:  "/tmp",
+				HeaderFile:      "headers.txt",
+			},
+		},
+		{
+			name: "fractional timeouts",
+			opts: []Option{WithRequestTimeoutDuration(1500 * time.Millisecond), WithConnectTimeout(time.Second), WithContextTimeout()},
+			want: Config{
+				RequestTimeoutDuration: 1500 * time.Millisecond,
+				ConnectTimeout:         time.Second,
+				ContextTimeout:         true,
+			},
+		},
+		{
+			name: "output files",
+			opts: []Option{WithOutputFile("out/artifact.zip"), WithOutputDir("/tmp"), WithCreateDirs()},
+			want: Config{
+				OutputFile: "out/artifact.zip",
+				OutputDir:  "/tmp",
+				CreateDirs: true,
+			},
+		},
+		{
+			name: "redirects",
+			opts: []Option{WithMaxRedirects(5)},
+			want: Config{
+				FollowRedirects: true,
+				MaxRedirects:    5,
+			},
+		},
+		{
+			name: "diagnostics",
+			opts: []Option{WithVerbose(), WithIncludeResponseHeaders(), WithFailWithBody()},
+			want: Config{
+				Verbose:                true,
+				IncludeResponseHeaders: true,
+				FailWithBody:           true,
+			},
+		},
+		{
+			name: "tls",
+			opts: []Option{
+				WithClientCert("client.pem", "client.key"), WithCACert("ca.pem"),
+			},
+			want: Config{
+				ClientCert: "client.pem",
+				ClientKey:  "client.key",
+				CACert:     "ca.pem",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodHead, "https://localhost/test", tt.opts...)
+
+			got := c.Config()
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("Config() diff = %v", cmp.Diff(got, tt.want))
+			}
+
+			data, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			var cfg Config
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			restored := mustNewFromRequest(t, http.MethodHead, "https://localhost/test", WithConfig(cfg))
+			if restored.String() != c.String() {
+				t.Errorf("WithConfig() = %v, want %v", restored, c)
+			}
+			if !cmp.Equal(restored.Config(), tt.want) {
+				t.Errorf("WithConfig() config diff = %v", cmp.Diff(restored.Config(), tt.want))
+			}
+		})
+	}
+}
+
+func TestWithConfig_zeroValues(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", WithRequestTimeout(5), WithConfig(Config{Silent: true}))
+
+	want := "curl -s -m 5 -X 'GET' 'https://localhost/test'"
+	if got := c.String(); got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+package curling
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// The sizes above which a command line is rejected, by shell.
+const (
+	// posixCommandLineLimit is the size of the longest argument Linux accepts,
+	// which bounds the commands run with sh -c.
+	posixCommandLineLimit = 128 << 10
+
+	// windowsCommandLineLimit is the size of the longest command line cmd.exe accepts.
+	windowsCommandLineLimit = 8191
+
+	// powerShellCommandLineLimit is the size of the longest command line Windows accepts.
+	powerShellCommandLineLimit = 32767
+)
+
+// WithAutoConfigFile keeps the command executable when it grows above the size
+// the target shell accepts on a command line: its options are written to a
+// cURL config file in dir, and the command is rendered as curl -K file instead.
+// An empty dir means the default temporary directory.
+// The written file is returned by [Command.Files], and the split is reported
+// by [Command.Validate]. Commands with placeholders, such as those of
+// [WithPlaceholders] and [WithRedactedHeaders], are never split, since cURL
+// doesn't expand variables in config files: the values would be either
+// disclosed or wrong. They are reported by [Command.Validate] instead.
+func WithAutoConfigFile(dir string) Option {
+	return func(curling *Command) {
+		curling.autoConfigFile = true
+		curling.autoConfigDir = dir
+	}
+}
+
+// commandLineLimit returns the size of the longest command line the target shell accepts.
+func (c *Command) commandLineLimit() int {
+	switch c.shell() {
+	case ShellWindows:
+		return windowsCommandLineLimit
+	case ShellPowerShell:
+		return powerShellCommandLineLimit
+	default:
+		return posixCommandLineLimit
+	}
+}
+
+// splitConfigFile writes the options of a constructed command that exceeds the
+// command line limit to a config file, when enabled with WithAutoConfigFile,
+// and replaces them with a single -K, --config option referencing it.
+// A command with placeholders is left as is.
+// If splitConfigFile can't write the config file, it returns an error.
+func (c *Command) splitConfigFile() error {
+	if !c.autoConfigFile {
+		return nil
+	}
+
+	size, limit := len(c.curl()), c.commandLineLimit()
+	if size <= limit {
+		return nil
+	}
+
+	if len(c.placeholders) > 0 {
+		c.warn(WarningConfigFilePlaceholders, "command is %d bytes, above the %d bytes a command line accepts, but its placeholders can't be written to a config file", size, limit)
+		return nil
+	}
+
+	path, err := c.writeFile(c.autoConfigDir, "curling-*.curlrc", c.configFile())
+	if err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+
+	c.tokens, c.args = nil, nil
+	c.appendToken(literal("curl"), literal(c.option(flagConfig)), value(path))
+	c.warn(WarningConfigFile, "command is %d bytes, above the %d bytes a command line accepts, its options were written to %s", size, limit, path)
+
+	return nil
+}
+
+// configFile returns the arguments of the command as a cURL config file,
+// with an option and its argument on each line and the URL set with url.
+// The arguments are the words of the command after the redaction of the
+// request, such as by [WithHeaderRedactor], while the placeholders, which are
+// replaced when the words are quoted, must not be set.
+// Extra flags take the following argument when it isn't an option or the URL.
+func (c *Command) configFile() []byte {
+	var b strings.Builder
+
+	args := c.args[1:]
+	url := c.request.url.String()
+	urlSeen := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "-") {
+			if arg == url && !urlSeen {
+				urlSeen = true
+			}
+			fmt.Fprintf(&b, "url = %s\n", configQuote(arg))
+			continue
+		}
+
+		next := ""
+		if i+1 < len(args) {
+			next = args[i+1]
+		}
+
+		switch {
+		case takesValue(arg), !isKnownFlag(arg) && next != "" && !strings.HasPrefix(next, "-") && (next != url || urlSeen):
+			fmt.Fprintf(&b, "%s %s\n", arg, configQuote(next))
+			i++
+		default:
+			fmt.Fprintf(&b, "%s\n", arg)
+		}
+	}
+
+	return []byte(b.String())
+}
+
+// configQuote returns s double-quoted, with the escapes of the cURL config file syntax.
+func configQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\t", `\t`, "\n", `\n`, "\r", `\r`, "\v", `\v`)
+
+	return `"` + r.Replace(s) + `"`
+}
+
+// takesValue reports whether arg is one of the flags that take an argument.
+func takesValue(arg string) bool {
+	return slices.ContainsFunc(valueFlags, func(f flag) bool {
+		return arg == f.short || arg == f.long
+	})
+}
+
+// isKnownFlag reports whether arg is one of the flags the library emits.
+func isKnownFlag(arg string) bool {
+	return takesValue(arg) || slices.ContainsFunc(switchFlags, func(f flag) bool {
+		return arg == f.short || arg == f.long
+	})
+}
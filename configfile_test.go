@@ -0,0 +1,174 @@
+package curling
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithAutoConfigFile(t *testing.T) {
+	header := http.Header{"Content-Type": {"text/plain"}}
+
+	t.Run("too long for a command line", func(t *testing.T) {
+		dir := t.TempDir()
+		body := strings.Repeat("a", posixCommandLineLimit)
+
+		c, err := mustNewWithBody(t, http.MethodPost, "https://localhost/test", header, body).With(WithSilent(), WithAutoConfigFile(dir))
+		if err != nil {
+			t.Fatalf("With() error = %v", err)
+		}
+
+		files := c.Files()
+		if len(files) != 1 || filepath.Dir(files[0]) != dir {
+			t.Fatalf("Files() = %v, want one file in %v", files, dir)
+		}
+
+		want := "curl -K '" + files[0] + "'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+
+		b, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatalf("reading config file: %v", err)
+		}
+
+		wantFile := "-s\n-X \"POST\"\nurl = \"https://localhost/test\"\n-H \"Content-Type: text/plain\"\n-d \"" + body + "\"\n"
+		if string(b) != wantFile {
+			t.Errorf("config file = %.200q, want %.200q", b, wantFile)
+		}
+
+		if w := c.Validate(); len(w) != 1 || w[0].Code != WarningConfigFile {
+			t.Errorf("Validate() = %v, want a %s warning", w, WarningConfigFile)
+		}
+	})
+
+	t.Run("windows limit", func(t *testing.T) {
+		body := strings.Repeat("a", windowsCommandLineLimit)
+
+		c, err := mustNewWithBody(t, http.MethodPost, "https://localhost/test", header, body).With(WithWindowsMultiLine(), WithAutoConfigFile(t.TempDir()))
+		if err != nil {
+			t.Fatalf("With() error = %v", err)
+		}
+
+		if files := c.Files(); len(files) != 1 {
+			t.Errorf("Files() = %v, want one file", files)
+		}
+	})
+
+	t.Run("short enough", func(t *testing.T) {
+		c, err := mustNewWithBody(t, http.MethodPost, "https://localhost/test", header, "a=1").With(WithAutoConfigFile(t.TempDir()))
+		if err != nil {
+			t.Fatalf("With() error = %v", err)
+		}
+
+		if files := c.Files(); files != nil {
+			t.Errorf("Files() = %v, want nil", files)
+		}
+
+		want := "curl -X 'POST' 'https://localhost/test' -H 'Content-Type: text/plain' -d 'a=1'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("placeholders", func(t *testing.T) {
+		body := strings.Repeat("a", posixCommandLineLimit)
+		header := http.Header{"Authorization": {"Bearer SECRET123"}, "X-Api-Key": {"k3y"}}
+
+		tests := []struct {
+			name string
+			opt  Option
+			want string
+		}{
+			{
+				name: "placeholders",
+				opt:  WithPlaceholders(map[string]string{"TOKEN": "SECRET123"}),
+				want: `-H 'Authorization: Bearer '"${TOKEN}"`,
+			},
+			{
+				name: "redacted headers",
+				opt:  WithRedactedHeaders("X-Api-Key"),
+				want: `-H 'X-Api-Key: '"${X_API_KEY}"`,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				dir := t.TempDir()
+
+				c, err := mustNewWithBody(t, http.MethodPost, "https://localhost/test", header, body).With(tt.opt, WithAutoConfigFile(dir))
+				if err != nil {
+					t.Fatalf("With() error = %v", err)
+				}
+
+				if files := c.Files(); files != nil {
+					t.Errorf("Files() = %v, want nil", files)
+				}
+				if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+					t.Errorf("config directory holds %d files, want none", len(entries))
+				}
+
+				if got := c.String(); !strings.Contains(got, tt.want) || strings.Contains(got, " -K ") {
+					t.Errorf("String() = %.200q, want the command with %v", got, tt.want)
+				}
+
+				if w := c.Validate(); len(w) != 1 || w[0].Code != WarningConfigFilePlaceholders {
+					t.Errorf("Validate() = %v, want a %s warning", w, WarningConfigFilePlaceholders)
+				}
+			})
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "missing")
+		body := strings.Repeat("a", posixCommandLineLimit)
+
+		r, err := http.NewRequest(http.MethodPost, "https://localhost/test", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+
+		if _, err := NewFromRequest(r, WithAutoConfigFile(dir)); err == nil {
+			t.Errorf("NewFromRequest() error = nil, want error")
+		}
+	})
+}
+
+func TestCommand_configFile(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "escapes",
+			want: "-X \"GET\"\nurl = \"https://localhost/test\"\n-H \"X-Quote: \\\"a\\\\b\\\"\\n\"\n",
+		},
+		{
+			name: "extra flags",
+			opts: []Option{WithMinimal(), WithExtraFlags("--limit-rate", "1M", "--http2")},
+			want: "--limit-rate \"1M\"\n--http2\nurl = \"https://localhost/test\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header.Set("X-Quote", "\"a\\b\"\n")
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+			c.ensureConstructed()
+
+			if got := string(c.configFile()); got != tt.want {
+				t.Errorf("configFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package curling
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A CookieOrder sets the order of the cookies in the Cookie header.
+type CookieOrder int
+
+const (
+	// CookieOrderWire keeps the cookies in the order the request sends them,
+	// as fingerprint-sensitive servers expect.
+	CookieOrderWire CookieOrder = iota
+
+	// CookieOrderSorted sorts the cookies by name, keeping the order of the
+	// cookies sharing a name, so that snapshot tests are deterministic.
+	CookieOrderSorted
+)
+
+// WithCookieOrder sets the order of the cookies in the Cookie header,
+// [CookieOrderWire] by default.
+func WithCookieOrder(order CookieOrder) Option {
+	return func(curling *Command) {
+		curling.cookieOrder = order
+	}
+}
+
+// WithCookieJar sets the flag -c, --cookie-jar, so that cURL saves the cookies
+// of the session to the file at path, for flows such as a login followed by
+// a call. An empty path will be silently ignored.
+func WithCookieJar(path string) Option {
+	return func(curling *Command) {
+		if path == "" {
+			return
+		}
+
+		curling.cookieJar = path
+	}
+}
+
+// WithCookieJarLoad also sets the flag -b, --cookie with the file of
+// [WithCookieJar], so that cURL sends the cookies saved by the previous commands.
+// It takes effect with [WithCookieJar].
+func WithCookieJarLoad() Option {
+	return func(curling *Command) {
+		curling.cookieJarLoad = true
+	}
+}
+
+// WithJunkSessionCookies sets the flag -j, --junk-session-cookies, so that cURL
+// discards the session cookies loaded from the cookie jar, as a new browser session does.
+// It takes effect with [WithCookieJarLoad].
+func WithJunkSessionCookies() Option {
+	return func(curling *Command) {
+		curling.junkSessionCookies = true
+	}
+}
+
+// cookieJarOptions returns the options driving the cookie jar, none without one.
+func (c *Command) cookieJarOptions() []word {
+	if c.cookieJar == "" {
+		return nil
+	}
+
+	var s []word
+
+	if c.cookieJarLoad {
+		s = append(s, literal(c.option(flagCookie)), value(c.cookieJar))
+
+		if c.junkSessionCookies {
+			s = append(s, literal(c.option(flagJunkSessionCookies)))
+		}
+	}
+
+	return append(s, literal(c.option(flagCookieJar)), value(c.cookieJar))
+}
+
+// orderCookies sorts the cookies of each Cookie header value by name,
+// when enabled with WithCookieOrder.
+func (c *Command) orderCookies() {
+	if c.cookieOrder != CookieOrderSorted {
+		return
+	}
+
+	values := c.request.header.Values("Cookie")
+	for i, value := range values {
+		values[i] = sortCookies(value)
+	}
+}
+
+// checkCookies warns about and records an issue for the cookies of the Cookie
+// headers whose name is not a valid token, which servers may reject or ignore.
+func (c *Command) checkCookies() {
+	for _, value := range c.request.header.Values("Cookie") {
+		for _, pair := range strings.Split(value, ";") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+
+			if name := cookieName(pair); !isToken(name) {
+				c.warn(WarningInvalidCookie, "cookie %q has an invalid name", name)
+				c.issue(fmt.Errorf("cookie %q has an invalid name", name))
+			}
+		}
+	}
+}
+
+// sortCookies returns the cookie pairs of value sorted by name.
+func sortCookies(value string) string {
+	pairs := strings.Split(value, ";")
+	for i, pair := range pairs {
+		pairs[i] = strings.TrimSpace(pair)
+	}
+
+	pairs = slices.DeleteFunc(pairs, func(pair string) bool {
+		return pair == ""
+	})
+
+	slices.SortStableFunc(pairs, func(a, b string) int {
+		return strings.Compare(cookieName(a), cookieName(b))
+	})
+
+	return strings.Join(pairs, "; ")
+}
+
+// cookieName returns the name of the cookie pair.
+func cookieName(pair string) string {
+	name, _, _ := strings.Cut(pair, "=")
+	return strings.TrimSpace(name)
+}
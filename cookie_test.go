@@ -0,0 +1,97 @@
+package curling
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithCookieOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		cookies []string
+		opts    []Option
+		want    string
+	}{
+		{
+			name:    "wire order",
+			cookies: []string{"session=abc; theme=dark; lang=en"},
+			want:    "curl -X 'GET' 'https://localhost/test' -H 'Cookie: session=abc; theme=dark; lang=en'",
+		},
+		{
+			name:    "sorted",
+			cookies: []string{"session=abc; theme=dark; lang=en"},
+			opts:    []Option{WithCookieOrder(CookieOrderSorted)},
+			want:    "curl -X 'GET' 'https://localhost/test' -H 'Cookie: lang=en; session=abc; theme=dark'",
+		},
+		{
+			name:    "sorted keeps duplicates in order",
+			cookies: []string{"b=2;a=1;  b=1;;flag"},
+			opts:    []Option{WithCookieOrder(CookieOrderSorted)},
+			want:    "curl -X 'GET' 'https://localhost/test' -H 'Cookie: a=1; b=2; b=1; flag'",
+		},
+		{
+			name:    "sorted values",
+			cookies: []string{"b=2; a=1", "d=4; c=3"},
+			opts:    []Option{WithCookieOrder(CookieOrderSorted), WithFaithful()},
+			want:    "curl --path-as-is -g -X 'GET' 'https://localhost/test' -H 'Cookie: a=1; b=2' -H 'Cookie: c=3; d=4'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			r.Header["Cookie"] = tt.cookies
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCookieJar(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "save",
+			opts: []Option{WithCookieJar("session.txt")},
+			want: "curl -c 'session.txt' -X 'POST' 'https://localhost/login'",
+		},
+		{
+			name: "save and load",
+			opts: []Option{WithCookieJar("session.txt"), WithCookieJarLoad()},
+			want: "curl -b 'session.txt' -c 'session.txt' -X 'POST' 'https://localhost/login'",
+		},
+		{
+			name: "junk session cookies",
+			opts: []Option{WithLongForm(), WithCookieJar("session.txt"), WithCookieJarLoad(), WithJunkSessionCookies()},
+			want: "curl --cookie 'session.txt' --junk-session-cookies --cookie-jar 'session.txt' --request 'POST' 'https://localhost/login'",
+		},
+		{
+			name: "junk session cookies without load",
+			opts: []Option{WithCookieJar("session.txt"), WithJunkSessionCookies()},
+			want: "curl -c 'session.txt' -X 'POST' 'https://localhost/login'",
+		},
+		{
+			name: "without cookie jar",
+			opts: []Option{WithCookieJar(""), WithCookieJarLoad(), WithJunkSessionCookies()},
+			want: "curl -X 'POST' 'https://localhost/login'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodPost, "https://localhost/login", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Package curlingzap adapts curling commands to the zap logger.
+package curlingzap
+
+import (
+	"github.com/aoliveti/curling"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"log/slog"
+)
+
+// Field returns a zap field holding the structured fields of c, as returned by
+// [curling.Command.LogAttrs], under the "curl" key.
+// The command is rendered only when the entry is encoded, so entries dropped
+// by the log level or by sampling don't pay for it.
+func Field(c *curling.Command) zap.Field {
+	return zap.Object("curl", command{c})
+}
+
+// command implements [zapcore.ObjectMarshaler] for a curling command.
+type command struct {
+	c *curling.Command
+}
+
+// MarshalLogObject implements the [zapcore.ObjectMarshaler] interface.
+func (m command) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, attr := range m.c.LogAttrs() {
+		switch attr.Value.Kind() {
+		case slog.KindBool:
+			enc.AddBool(attr.Key, attr.Value.Bool())
+		default:
+			enc.AddString(attr.Key, attr.Value.String())
+		}
+	}
+
+	return nil
+}
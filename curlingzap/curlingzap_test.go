@@ -0,0 +1,43 @@
+package curlingzap
+
+import (
+	"github.com/aoliveti/curling"
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"net/http"
+	"testing"
+)
+
+func TestField(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	c, err := curling.NewFromRequest(r)
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	zap.New(core).Info("request", Field(c))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %v, want 1", len(entries))
+	}
+
+	want := map[string]any{
+		"curl": map[string]any{
+			"method":    "GET",
+			"url":       "https://localhost/test",
+			"curl":      "curl -X 'GET' 'https://localhost/test'",
+			"truncated": false,
+		},
+	}
+	if got := entries[0].ContextMap(); !cmp.Equal(got, want) {
+		t.Errorf("fields diff = %v", cmp.Diff(got, want))
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/aoliveti/curling/curlingzap
+
+go 1.21
+
+require (
+	github.com/aoliveti/curling v0.0.0-20261015154103-43f339ff3255
+	github.com/google/go-cmp v0.6.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,32 @@
+// Package curlingzerolog adapts curling commands to the zerolog logger.
+package curlingzerolog
+
+import (
+	"github.com/aoliveti/curling"
+	"github.com/rs/zerolog"
+	"log/slog"
+)
+
+// Marshal returns a zerolog object holding the structured fields of c,
+// as returned by [curling.Command.LogAttrs], to be added to an event with Object.
+// The command is rendered only when the event is enabled.
+func Marshal(c *curling.Command) zerolog.LogObjectMarshaler {
+	return command{c}
+}
+
+// command implements [zerolog.LogObjectMarshaler] for a curling command.
+type command struct {
+	c *curling.Command
+}
+
+// MarshalZerologObject implements the [zerolog.LogObjectMarshaler] interface.
+func (m command) MarshalZerologObject(e *zerolog.Event) {
+	for _, attr := range m.c.LogAttrs() {
+		switch attr.Value.Kind() {
+		case slog.KindBool:
+			e.Bool(attr.Key, attr.Value.Bool())
+		default:
+			e.Str(attr.Key, attr.Value.String())
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package curlingzerolog
+
+import (
+	"bytes"
+	"github.com/aoliveti/curling"
+	"github.com/rs/zerolog"
+	"net/http"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	c, err := curling.NewFromRequest(r)
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		level zerolog.Level
+		want  string
+	}{
+		{
+			name:  "enabled",
+			level: zerolog.InfoLevel,
+			want:  `{"level":"info","curl":{"method":"GET","url":"https://localhost/test","curl":"curl -X 'GET' 'https://localhost/test'","truncated":false},"message":"request"}` + "\n",
+		},
+		{
+			name:  "disabled",
+			level: zerolog.WarnLevel,
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			logger := zerolog.New(&b).Level(tt.level)
+
+			logger.Info().Object("curl", Marshal(c)).Msg("request")
+
+			if got := b.String(); got != tt.want {
+				t.Errorf("log = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/aoliveti/curling/curlingzerolog
+
+go 1.21
+
+require (
+	github.com/aoliveti/curling v0.0.0-20261015154103-43f339ff3255
+	github.com/rs/zerolog v1.33.0
+)
+
+require (
+	github.com/andybalholm/brotli v1.1.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	golang.org/x/sys v0.12.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
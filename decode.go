@@ -0,0 +1,57 @@
+package curling
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"github.com/andybalholm/brotli"
+	"io"
+	"strings"
+)
+
+// decodeBody replaces a gzip, deflate or br encoded body with its decoded bytes
+// and drops the Content-Encoding and Content-Length headers, which no longer apply.
+// Bodies with another or no encoding are left as they are.
+// If the body can't be decoded, decodeBody returns an error and leaves it unchanged.
+func (p *parsedRequest) decodeBody() error {
+	encoding := strings.ToLower(strings.TrimSpace(p.header.Get("Content-Encoding")))
+	if !p.hasBody || encoding == "" {
+		return nil
+	}
+
+	var r io.Reader
+	switch encoding {
+	case "gzip", "x-gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(p.body))
+		if err != nil {
+			return fmt.Errorf("decoding %s body: %w", encoding, err)
+		}
+		r = gr
+	case "deflate":
+		// The deflate coding is zlib wrapped, but some clients send raw deflate data.
+		zr, err := zlib.NewReader(bytes.NewReader(p.body))
+		if err != nil {
+			r = flate.NewReader(bytes.NewReader(p.body))
+		} else {
+			r = zr
+		}
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(p.body))
+	default:
+		return nil
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("decoding %s body: %w", encoding, err)
+	}
+
+	p.body = b
+	p.bodySize = len(b)
+	p.header.Del("Content-Encoding")
+	p.header.Del("Content-Length")
+
+	return nil
+}
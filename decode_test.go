@@ -0,0 +1,108 @@
+package curling
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"github.com/andybalholm/brotli"
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func compress(t *testing.T, newWriter func(w io.Writer) io.WriteCloser, s string) string {
+	t.Helper()
+
+	var b bytes.Buffer
+	w := newWriter(&b)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatalf("compressing: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("compressing: %v", err)
+	}
+
+	return b.String()
+}
+
+func TestWithDecodedBody(t *testing.T) {
+	body := `{"key":"value"}`
+
+	tests := []struct {
+		name         string
+		encoding     string
+		body         string
+		want         string
+		wantWarnings []Warning
+	}{
+		{
+			name:     "gzip",
+			encoding: "gzip",
+			body:     compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }, body),
+			want:     `curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/json' -d '{"key":"value"}'`,
+		},
+		{
+			name:     "zlib deflate",
+			encoding: "deflate",
+			body:     compress(t, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }, body),
+			want:     `curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/json' -d '{"key":"value"}'`,
+		},
+		{
+			name:     "raw deflate",
+			encoding: "deflate",
+			body: compress(t, func(w io.Writer) io.WriteCloser {
+				fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+				return fw
+			}, body),
+			want: `curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/json' -d '{"key":"value"}'`,
+		},
+		{
+			name:     "br",
+			encoding: "br",
+			body:     compress(t, func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) }, body),
+			want:     `curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/json' -d '{"key":"value"}'`,
+		},
+		{
+			name:     "unknown encoding",
+			encoding: "zstd",
+			body:     body,
+			want:     `curl -X 'POST' 'https://localhost/test' -H 'Content-Encoding: zstd' -H 'Content-Length: 15' -H 'Content-Type: application/json' -d '{"key":"value"}'`,
+		},
+		{
+			name:     "invalid gzip",
+			encoding: "gzip",
+			body:     body,
+			want:     `curl -X 'POST' 'https://localhost/test' -H 'Content-Encoding: gzip' -H 'Content-Length: 15' -H 'Content-Type: application/json' -d '{"key":"value"}'`,
+			wantWarnings: []Warning{
+				{
+					Code:    WarningUndecodableBody,
+					Message: "body was left encoded: decoding gzip body: gzip: invalid header",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{
+				"Content-Encoding": {tt.encoding},
+				"Content-Length":   {"15"},
+				"Content-Type":     {"application/json"},
+			}
+
+			c, err := NewFromParts(http.MethodPost, "https://localhost/test", header, bytes.NewBufferString(tt.body), WithDecodedBody())
+			if err != nil {
+				t.Fatalf("NewFromParts() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+
+			if got := c.Validate(); !cmp.Equal(got, tt.wantWarnings) {
+				t.Errorf("Validate() diff = %v", cmp.Diff(got, tt.wantWarnings))
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Package dialect publishes output formats, or dialects, by name, so that
+// third parties can plug their own renderers into the curling command model
+// and option pipeline. A registered dialect is rendered with [curling.Command.Render].
+//
+// The built-in renderers are registered by the curling package as curl,
+// powershell, wget, fetch, python, go and hurl.
+package dialect
+
+import (
+	"github.com/aoliveti/curling"
+	"github.com/aoliveti/curling/internal/registry"
+)
+
+// Register makes the renderer r available under name, usually from the init
+// function of the package providing it.
+// If Register is called twice with the same name, or if r is nil, it panics.
+func Register(name string, r curling.Renderer) {
+	if r == nil {
+		panic("dialect: Register renderer is nil")
+	}
+
+	if !registry.Register(name, r) {
+		panic("dialect: Register called twice for dialect " + name)
+	}
+}
+
+// Lookup returns the renderer registered under name and reports whether there is one.
+func Lookup(name string) (curling.Renderer, bool) {
+	v, ok := registry.Lookup(name)
+	if !ok {
+		return nil, false
+	}
+
+	return v.(curling.Renderer), true
+}
+
+// Names returns the sorted names of the registered dialects.
+func Names() []string {
+	return registry.Names()
+}
@@ -0,0 +1,104 @@
+package dialect
+
+import (
+	"github.com/aoliveti/curling"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	Register("httpie", curling.RendererFunc(func(c *curling.Command) string {
+		r := c.Request()
+		return "http " + r.Method + " " + r.URL.String()
+	}))
+
+	r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	c, err := curling.NewFromRequest(r, curling.WithLongForm())
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	got, err := c.Render("httpie")
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if want := "http GET https://localhost/test"; got != want {
+		t.Errorf("Render() = %v, want %v", got, want)
+	}
+
+	if _, ok := Lookup("httpie"); !ok {
+		t.Errorf("Lookup() = false, want true")
+	}
+}
+
+func TestRegister_panics(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		r       curling.Renderer
+	}{
+		{
+			name:    "built-in name",
+			dialect: "curl",
+			r:       curling.Wget,
+		},
+		{
+			name:    "nil renderer",
+			dialect: "nil",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Register() did not panic")
+				}
+			}()
+
+			Register(tt.dialect, tt.r)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	c, err := curling.NewFromRequest(r)
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	wget, ok := Lookup("wget")
+	if !ok {
+		t.Fatalf("Lookup() = false, want true")
+	}
+
+	if got, want := wget.Render(c), c.ToWget(); got != want {
+		t.Errorf("Render() = %v, want %v", got, want)
+	}
+
+	if _, ok := Lookup("unknown"); ok {
+		t.Errorf("Lookup() = true, want false")
+	}
+}
+
+func TestNames(t *testing.T) {
+	builtins := []string{"curl", "fetch", "go", "hurl", "powershell", "python", "wget"}
+
+	got := slices.DeleteFunc(Names(), func(name string) bool {
+		return !slices.Contains(builtins, name)
+	})
+	if !cmp.Equal(got, builtins) {
+		t.Errorf("Names() diff = %v", cmp.Diff(got, builtins))
+	}
+}
@@ -0,0 +1,73 @@
+package curling
+
+// WithOutputFile sets the flag -o, --output, so that cURL saves the response
+// body to path instead of writing it to the terminal.
+// It replaces [WithRemoteName]. An empty path will be silently ignored.
+func WithOutputFile(path string) Option {
+	return func(curling *Command) {
+		if path == "" {
+			return
+		}
+
+		curling.outputFile = path
+		curling.remoteName = false
+	}
+}
+
+// WithRemoteName sets the flag -O, --remote-name, so that cURL saves the
+// response body to a file named after the last segment of the URL path.
+// It replaces [WithOutputFile].
+func WithRemoteName() Option {
+	return func(curling *Command) {
+		curling.remoteName = true
+		curling.outputFile = ""
+	}
+}
+
+// WithOutputDir sets the flag --output-dir, the directory the files set with
+// [WithOutputFile] or [WithRemoteName] are saved in, with which it takes effect.
+// Without them, the flag is left out and [WarningOutputDirIgnored] is reported.
+// An empty dir will be silently ignored.
+func WithOutputDir(dir string) Option {
+	return func(curling *Command) {
+		if dir == "" {
+			return
+		}
+
+		curling.outputDir = dir
+	}
+}
+
+// WithCreateDirs sets the flag --create-dirs, so that cURL creates the missing
+// directories of the output file and of the output directory.
+// It takes effect with [WithOutputFile] or [WithRemoteName].
+func WithCreateDirs() Option {
+	return func(curling *Command) {
+		curling.createDirs = true
+	}
+}
+
+// outputOptions returns the options saving the response body to disk,
+// none when the body is written to the terminal.
+func (c *Command) outputOptions() []word {
+	var s []word
+
+	switch {
+	case c.outputFile != "":
+		s = append(s, literal(c.option(flagOutput)), value(c.outputFile))
+	case c.remoteName:
+		s = append(s, literal(c.option(flagRemoteName)))
+	default:
+		return nil
+	}
+
+	if c.outputDir != "" {
+		s = append(s, literal(c.option(flagOutputDir)), value(c.outputDir))
+	}
+
+	if c.createDirs {
+		s = append(s, literal(c.option(flagCreateDirs)))
+	}
+
+	return s
+}
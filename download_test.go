@@ -0,0 +1,96 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"testing"
+)
+
+func TestWithOutputFile(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "output file",
+			opts: []Option{WithOutputFile("artifact.zip")},
+			want: "curl -o 'artifact.zip' -X 'GET' 'https://localhost/files/artifact.zip'",
+		},
+		{
+			name: "remote name",
+			opts: []Option{WithRemoteName()},
+			want: "curl -O -X 'GET' 'https://localhost/files/artifact.zip'",
+		},
+		{
+			name: "long form",
+			opts: []Option{WithLongForm(), WithRemoteName(), WithOutputDir("downloads"), WithCreateDirs()},
+			want: "curl --remote-name --output-dir 'downloads' --create-dirs --request 'GET' 'https://localhost/files/artifact.zip'",
+		},
+		{
+			name: "output file replaces remote name",
+			opts: []Option{WithRemoteName(), WithOutputFile("out/a b.zip"), WithCreateDirs()},
+			want: "curl -o 'out/a b.zip' --create-dirs -X 'GET' 'https://localhost/files/artifact.zip'",
+		},
+		{
+			name: "remote name replaces output file",
+			opts: []Option{WithOutputFile("artifact.zip"), WithRemoteName()},
+			want: "curl -O -X 'GET' 'https://localhost/files/artifact.zip'",
+		},
+		{
+			name: "without output file",
+			opts: []Option{WithOutputDir("downloads"), WithCreateDirs()},
+			want: "curl -X 'GET' 'https://localhost/files/artifact.zip'",
+		},
+		{
+			name: "empty values",
+			opts: []Option{WithOutputFile("artifact.zip"), WithOutputFile(""), WithOutputDir("")},
+			want: "curl -o 'artifact.zip' -X 'GET' 'https://localhost/files/artifact.zip'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/files/artifact.zip", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithOutputDir_warning(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want []Warning
+	}{
+		{
+			name: "with output file",
+			opts: []Option{WithOutputFile("artifact.zip"), WithOutputDir("downloads")},
+		},
+		{
+			name: "with remote name",
+			opts: []Option{WithRemoteName(), WithOutputDir("downloads")},
+		},
+		{
+			name: "without output file",
+			opts: []Option{WithOutputDir("downloads")},
+			want: []Warning{
+				{
+					Code:    WarningOutputDirIgnored,
+					Message: `output directory "downloads" was left out, set an output file or the remote name to use it`,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/files/artifact.zip", tt.opts...)
+
+			if diff := cmp.Diff(tt.want, c.Validate()); diff != "" {
+				t.Errorf("Validate() diff = %s", diff)
+			}
+		})
+	}
+}
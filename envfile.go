@@ -0,0 +1,57 @@
+package curling
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WithEnvFile writes the values replaced by the placeholders, such as the redacted
+// headers of [WithRedactedHeaders], the Authorization value of [WithMinimal],
+// the passwords of [WithProxyUser] and [WithAuthUser] and the values of
+// [WithPlaceholders], to the dotenv file at path, and renders the command
+// sourcing it instead of the masked export block. The command can then be
+// shared as is, while the file alone holds the secrets: keep it out of version
+// control, such as with a .env entry in .gitignore. The file is listed by
+// [Command.Files] and written only when the command has placeholders.
+// An empty path will be silently ignored.
+func WithEnvFile(path string) Option {
+	return func(curling *Command) {
+		if path == "" {
+			return
+		}
+
+		curling.envFile = path
+	}
+}
+
+// writeEnvFile writes the placeholder values to the file set with WithEnvFile,
+// one NAME='value' line for each variable, sorted by name.
+// If writeEnvFile can't write the file, it returns an error.
+func (c *Command) writeEnvFile() error {
+	if c.envFile == "" || len(c.placeholders) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(c.placeholders))
+	for _, p := range c.placeholders {
+		v := p.value
+		if secret, ok := c.secretValues[p.name]; ok {
+			v = secret
+		}
+		lines = append(lines, p.name+"="+singleQuote(v)+"\n")
+	}
+	slices.Sort(lines)
+
+	if _, err := c.writeFileAt(c.envFile, []byte(strings.Join(lines, ""))); err != nil {
+		return fmt.Errorf("writing env file: %w", err)
+	}
+
+	return nil
+}
+
+// sourceBlock returns the line sourcing the file set with WithEnvFile,
+// which declares the placeholder variables.
+func (c *Command) sourceBlock() string {
+	return ". " + c.escape(c.envFile) + "\n"
+}
@@ -0,0 +1,87 @@
+package curling
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithEnvFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		opts     []Option
+		want     string
+		wantFile string
+	}{
+		{
+			name:   "redacted headers",
+			header: http.Header{"X-Api-Key": {"k'1"}, "Authorization": {"Bearer abc.def"}},
+			opts:   []Option{WithRedactedHeaders("X-Api-Key", "Authorization"), WithOAuth2BearerFlag()},
+			want: "-X 'GET' 'https://localhost/test' --oauth2-bearer \"${BEARER_TOKEN}\" " +
+				"-H 'X-Api-Key: '\"${X_API_KEY}\"",
+			wantFile: "BEARER_TOKEN='abc.def'\nX_API_KEY='k'\\''1'\n",
+		},
+		{
+			name:     "distinct values of a redacted header",
+			header:   http.Header{"X-Api-Key": {"k1", "k2", "k1"}},
+			opts:     []Option{WithRedactedHeaders("X-Api-Key")},
+			want:     "-X 'GET' 'https://localhost/test' -H 'X-Api-Key: '\"${X_API_KEY}\"', '\"${X_API_KEY_2}\"', '\"${X_API_KEY}\"",
+			wantFile: "X_API_KEY='k1'\nX_API_KEY_2='k2'\n",
+		},
+		{
+			name:     "placeholders and proxy password",
+			header:   http.Header{"X-Tenant": {"acme"}},
+			opts:     []Option{WithPlaceholders(map[string]string{"TENANT": "acme"}), WithProxy("http://proxy:3128"), WithProxyUser("alice", "s3cr3t")},
+			want:     "-x 'http://proxy:3128' -U 'alice:'\"${PROXY_PASSWORD}\" -X 'GET' 'https://localhost/test' -H 'X-Tenant: '\"${TENANT}\"",
+			wantFile: "PROXY_PASSWORD='s3cr3t'\nTENANT='acme'\n",
+		},
+		{
+			name:   "without placeholders",
+			header: http.Header{"X-Tenant": {"acme"}},
+			want:   "-X 'GET' 'https://localhost/test' -H 'X-Tenant: acme'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header = tt.header
+
+			c, err := NewFromRequest(r, append(tt.opts, WithEnvFile(path))...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			want := "curl " + tt.want
+			if tt.wantFile != "" {
+				want = ". '" + path + "'\n" + want
+			}
+			if got := c.String(); got != want {
+				t.Errorf("String() = %v, want %v", got, want)
+			}
+
+			b, err := os.ReadFile(path)
+			if tt.wantFile == "" {
+				if !os.IsNotExist(err) {
+					t.Errorf("ReadFile() error = %v, want not exist", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if got := string(b); got != tt.wantFile {
+				t.Errorf("env file = %q, want %q", got, tt.wantFile)
+			}
+			if files := c.Files(); len(files) != 1 || files[0] != path {
+				t.Errorf("Files() = %v, want [%v]", files, path)
+			}
+		})
+	}
+}
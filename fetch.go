@@ -0,0 +1,62 @@
+package curling
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToFetch returns a JavaScript fetch() call equivalent to the cURL command.
+//
+// Credentials embedded in the URL are moved to a basic Authorization header,
+// since fetch rejects URLs that include them. When the request carries cookies,
+// the call also sets credentials to "include" so that browsers send the stored ones.
+// The request timeout option is rendered as an AbortSignal.
+// Note that fetch follows redirects by default.
+func (c *Command) ToFetch() string {
+	u, header := c.request.withoutUserinfo()
+	p := parsedRequest{header: header}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "fetch(%s, {\n", jsonQuote(u.String()))
+	fmt.Fprintf(&b, "  method: %s,\n", jsonQuote(c.request.method))
+
+	if fields := p.headerFields(); len(fields) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, field := range fields {
+			fmt.Fprintf(&b, "    %s: %s,\n", jsonQuote(field.key), jsonQuote(field.value))
+		}
+		b.WriteString("  },\n")
+	}
+
+	if c.request.hasBody {
+		fmt.Fprintf(&b, "  body: %s,\n", jsonQuote(string(c.request.body)))
+	}
+
+	if header.Get("Cookie") != "" {
+		b.WriteString("  credentials: \"include\",\n")
+	}
+
+	if c.requestTimeout > 0 {
+		fmt.Fprintf(&b, "  signal: AbortSignal.timeout(%d),\n", c.requestTimeout.Milliseconds())
+	}
+
+	b.WriteString("});")
+
+	return b.String()
+}
+
+// jsonQuote returns s as a JSON string literal, which is also a valid
+// JavaScript and Python string literal.
+func jsonQuote(s string) string {
+	var b bytes.Buffer
+
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+
+	// Encoding a string never fails.
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
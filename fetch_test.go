@@ -0,0 +1,118 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_ToFetch(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	type args struct {
+		r    *http.Request
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty method",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+			},
+			want: "fetch(\"https://localhost/test\", {\n" +
+				"  method: \"GET\",\n" +
+				"});",
+		},
+		{
+			name: "headers body and cookies",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Header: http.Header{
+						"Content-Type": {"application/json"},
+						"Cookie":       {"session=1"},
+					},
+					Body: readCloser(`{"key":"<value>"}`),
+				},
+			},
+			want: "fetch(\"https://localhost/test\", {\n" +
+				"  method: \"POST\",\n" +
+				"  headers: {\n" +
+				"    \"Content-Type\": \"application/json\",\n" +
+				"    \"Cookie\": \"session=1\",\n" +
+				"  },\n" +
+				"  body: \"{\\\"key\\\":\\\"<value>\\\"}\",\n" +
+				"  credentials: \"include\",\n" +
+				"});",
+		},
+		{
+			name: "url credentials and timeout",
+			args: args{
+				r: &http.Request{
+					URL: &url.URL{
+						Scheme: "https",
+						User:   url.UserPassword("user", "pass"),
+						Host:   "localhost",
+					},
+				},
+				opts: []Option{WithRequestTimeout(5)},
+			},
+			want: "fetch(\"https://localhost\", {\n" +
+				"  method: \"GET\",\n" +
+				"  headers: {\n" +
+				"    \"Authorization\": \"Basic dXNlcjpwYXNz\",\n" +
+				"  },\n" +
+				"  signal: AbortSignal.timeout(5000),\n" +
+				"});",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.args.r, tt.args.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.ToFetch(); got != tt.want {
+				t.Errorf("ToFetch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_jsonQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "quotes and html",
+			s:    `"<a>"`,
+			want: `"\"<a>\""`,
+		},
+		{
+			name: "line separator",
+			s:    "a\u2028b\n",
+			want: `"a\u2028b\n"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonQuote(tt.s); got != tt.want {
+				t.Errorf("jsonQuote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
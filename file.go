@@ -0,0 +1,145 @@
+package curling
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A TempFS creates the side files written while building a command,
+// such as header and body files. The default one uses the os package.
+type TempFS interface {
+	// CreateTemp creates a new file in dir, named after pattern as in [os.CreateTemp],
+	// and returns it along with its path. An empty dir means the default temporary directory.
+	CreateTemp(dir, pattern string) (io.WriteCloser, string, error)
+
+	// Create creates or truncates the file at path.
+	Create(path string) (io.WriteCloser, error)
+}
+
+// osFS is the [TempFS] backed by the os package.
+type osFS struct{}
+
+// CreateTemp implements the [TempFS] interface.
+func (osFS) CreateTemp(dir, pattern string) (io.WriteCloser, string, error) {
+	f, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return f, f.Name(), nil
+}
+
+// Create implements the [TempFS] interface.
+func (osFS) Create(path string) (io.WriteCloser, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+}
+
+// Files returns the paths of the side files written while building the command,
+// such as the header files referenced with -H @file.
+// The caller is responsible for removing them.
+func (c *Command) Files() []string {
+	c.ensureConstructed()
+
+	if len(c.files) == 0 {
+		return nil
+	}
+
+	files := make([]string, len(c.files))
+	copy(files, c.files)
+
+	return files
+}
+
+// fs returns the configured [TempFS], the os package when none is set.
+func (c *Command) fs() TempFS {
+	if c.tempFS != nil {
+		return c.tempFS
+	}
+
+	return osFS{}
+}
+
+// writeFile writes data to a new file in dir, named after pattern as in [os.CreateTemp],
+// and records its path among the command files. With an ID generator, the file is
+// named after pattern with the last "*" replaced by a generated ID.
+func (c *Command) writeFile(dir, pattern string, data []byte) (string, error) {
+	if c.dryRun {
+		return dryRunPath(dir, pattern, c.newID != nil), nil
+	}
+
+	if c.newID != nil {
+		if dir == "" {
+			dir = os.TempDir()
+		}
+
+		name := pattern
+		if i := strings.LastIndex(pattern, "*"); i >= 0 {
+			name = pattern[:i] + c.newID() + pattern[i+1:]
+		}
+
+		return c.writeFileAt(filepath.Join(dir, name), data)
+	}
+
+	w, path, err := c.fs().CreateTemp(dir, pattern)
+	if err != nil {
+		return "", fmt.Errorf("creating file: %w", err)
+	}
+
+	return c.saveFile(w, path, data)
+}
+
+// dryRunPath returns the stand-in of the path of a file written by writeFile during
+// a dry run, at least as long as the path it would have: the "*" of pattern is
+// replaced by as many digits as the random names of [os.CreateTemp] have at most,
+// or as a UUID has with an ID generator.
+func dryRunPath(dir, pattern string, generatedID bool) string {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	size := 10
+	if generatedID {
+		size = 36
+	}
+
+	name := pattern
+	if i := strings.LastIndex(pattern, "*"); i >= 0 {
+		name = pattern[:i] + strings.Repeat("0", size) + pattern[i+1:]
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// writeFileAt writes data to the file at path, replacing its content,
+// and records the path among the command files.
+func (c *Command) writeFileAt(path string, data []byte) (string, error) {
+	if c.dryRun {
+		return path, nil
+	}
+
+	w, err := c.fs().Create(path)
+	if err != nil {
+		return "", fmt.Errorf("creating file: %w", err)
+	}
+
+	return c.saveFile(w, path, data)
+}
+
+// saveFile writes data to w, closes it and records path among the command files.
+func (c *Command) saveFile(w io.WriteCloser, path string, data []byte) (string, error) {
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return "", fmt.Errorf("writing file: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("closing file: %w", err)
+	}
+
+	c.files = append(c.files, path)
+
+	return path, nil
+}
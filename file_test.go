@@ -0,0 +1,381 @@
+package curling
+
+import (
+	"bytes"
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_headerSpill(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	token := strings.Repeat("x", 32)
+	header := http.Header{
+		"Authorization": {"Bearer " + token},
+		"X-Key":         {"1"},
+		"X-Saml":        {token},
+	}
+
+	t.Run("spill large values", func(t *testing.T) {
+		dir := t.TempDir()
+
+		c, err := NewFromRequest(&http.Request{URL: testUrl, Header: header}, WithHeaderSpill(16, dir))
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		files := c.Files()
+		if len(files) != 1 || filepath.Dir(files[0]) != dir {
+			t.Fatalf("Files() = %v, want one file in %v", files, dir)
+		}
+
+		want := "curl -X 'GET' 'https://localhost/test' -H 'X-Key: 1' -H '@" + files[0] + "'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+
+		b, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatalf("reading header file: %v", err)
+		}
+
+		wantFile := "Authorization: Bearer " + token + "\nX-Saml: " + token + "\n"
+		if string(b) != wantFile {
+			t.Errorf("header file = %q, want %q", b, wantFile)
+		}
+	})
+
+	t.Run("values below threshold", func(t *testing.T) {
+		c, err := NewFromRequest(&http.Request{URL: testUrl, Header: header}, WithHeaderSpill(64, t.TempDir()))
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		if files := c.Files(); files != nil {
+			t.Errorf("Files() = %v, want nil", files)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "missing")
+
+		_, err := NewFromRequest(&http.Request{URL: testUrl, Header: header}, WithHeaderSpill(16, dir))
+		if err == nil {
+			t.Errorf("NewFromRequest() error = nil, want error")
+		}
+	})
+}
+
+func TestCommand_headerFile(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	header := http.Header{
+		"Accept": {"application/json"},
+		"X-Key":  {"1"},
+	}
+
+	t.Run("write every header", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "headers.txt")
+		if err := os.WriteFile(path, []byte("stale content that must be replaced\n"), 0o600); err != nil {
+			t.Fatalf("writing header file: %v", err)
+		}
+
+		c, err := NewFromRequest(&http.Request{URL: testUrl, Header: header}, WithHeaderFile(path), WithHeaderSpill(64, ""))
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		want := "curl -X 'GET' 'https://localhost/test' -H '@" + path + "'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+
+		if files := c.Files(); len(files) != 1 || files[0] != path {
+			t.Errorf("Files() = %v, want [%v]", files, path)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("reading header file: %v", err)
+		}
+
+		wantFile := "Accept: application/json\nX-Key: 1\n"
+		if string(b) != wantFile {
+			t.Errorf("header file = %q, want %q", b, wantFile)
+		}
+	})
+
+	t.Run("without headers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "headers.txt")
+
+		c, err := NewFromRequest(&http.Request{URL: testUrl}, WithHeaderFile(path))
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		if files := c.Files(); files != nil {
+			t.Errorf("Files() = %v, want nil", files)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("header file exists, stat error = %v", err)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "headers.txt")
+
+		_, err := NewFromRequest(&http.Request{URL: testUrl, Header: header}, WithHeaderFile(path))
+		if err == nil {
+			t.Errorf("NewFromRequest() error = nil, want error")
+		}
+	})
+}
+
+func TestCommand_binaryBody(t *testing.T) {
+	body := "\x08\x96\x01\x00"
+
+	t.Run("inline body", func(t *testing.T) {
+		c, err := NewFromParts(http.MethodPost, "https://localhost/test", nil, strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("NewFromParts() error = %v", err)
+		}
+
+		want := "curl -X 'POST' 'https://localhost/test' -d '" + body + "'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("reference body.bin", func(t *testing.T) {
+		c, err := NewFromParts(http.MethodPost, "https://localhost/test", nil, strings.NewReader(body), WithOutputVersion(OutputVersion2))
+		if err != nil {
+			t.Fatalf("NewFromParts() error = %v", err)
+		}
+
+		want := "curl -X 'POST' 'https://localhost/test' --data-binary '@body.bin'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+
+		if files := c.Files(); files != nil {
+			t.Errorf("Files() = %v, want nil", files)
+		}
+	})
+
+	t.Run("write body file", func(t *testing.T) {
+		dir := t.TempDir()
+
+		c, err := NewFromParts(http.MethodPost, "https://localhost/test", nil, strings.NewReader(body), WithBinaryBodyDir(dir), WithLongForm())
+		if err != nil {
+			t.Fatalf("NewFromParts() error = %v", err)
+		}
+
+		files := c.Files()
+		if len(files) != 1 || filepath.Dir(files[0]) != dir {
+			t.Fatalf("Files() = %v, want one file in %v", files, dir)
+		}
+
+		want := "curl --request 'POST' 'https://localhost/test' --data-binary '@" + files[0] + "'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+
+		b, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatalf("reading body file: %v", err)
+		}
+
+		if string(b) != body {
+			t.Errorf("body file = %q, want %q", b, body)
+		}
+
+		if warnings := c.Validate(); warnings != nil {
+			t.Errorf("Validate() = %v, want nil", warnings)
+		}
+	})
+
+	t.Run("text body", func(t *testing.T) {
+		c, err := NewFromParts(http.MethodPost, "https://localhost/test", nil, strings.NewReader("key=value"), WithBinaryBodyDir(t.TempDir()))
+		if err != nil {
+			t.Fatalf("NewFromParts() error = %v", err)
+		}
+
+		if files := c.Files(); files != nil {
+			t.Errorf("Files() = %v, want nil", files)
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "missing")
+
+		_, err := NewFromParts(http.MethodPost, "https://localhost/test", nil, strings.NewReader(body), WithBinaryBodyDir(dir))
+		if err == nil {
+			t.Errorf("NewFromParts() error = nil, want error")
+		}
+	})
+}
+
+func TestCommand_bodyToFile(t *testing.T) {
+	body := `{"key":"value"}`
+
+	t.Run("user file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "body.json")
+
+		c, err := NewFromParts(http.MethodPost, "https://localhost/test", nil, strings.NewReader(body), WithBodyToFile(path), WithFormEncoding())
+		if err != nil {
+			t.Fatalf("NewFromParts() error = %v", err)
+		}
+
+		want := "curl -X 'POST' 'https://localhost/test' --data-binary '@" + path + "'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+
+		if files := c.Files(); len(files) != 1 || files[0] != path {
+			t.Errorf("Files() = %v, want [%v]", files, path)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("reading body file: %v", err)
+		}
+
+		if string(b) != body {
+			t.Errorf("body file = %q, want %q", b, body)
+		}
+	})
+
+	t.Run("temporary file", func(t *testing.T) {
+		c, err := NewFromParts(http.MethodPost, "https://localhost/test", nil, strings.NewReader(body), WithBodyToFile(""))
+		if err != nil {
+			t.Fatalf("NewFromParts() error = %v", err)
+		}
+
+		files := c.Files()
+		if len(files) != 1 {
+			t.Fatalf("Files() = %v, want one file", files)
+		}
+		t.Cleanup(func() { _ = os.Remove(files[0]) })
+
+		want := "curl -X 'POST' 'https://localhost/test' --data-binary '@" + files[0] + "'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("without body", func(t *testing.T) {
+		c, err := NewFromParts(http.MethodGet, "https://localhost/test", nil, nil, WithBodyToFile(""))
+		if err != nil {
+			t.Fatalf("NewFromParts() error = %v", err)
+		}
+
+		if files := c.Files(); files != nil {
+			t.Errorf("Files() = %v, want nil", files)
+		}
+	})
+}
+
+// memFS is an in-memory TempFS.
+type memFS map[string]*bytes.Buffer
+
+func (fs memFS) CreateTemp(dir, pattern string) (io.WriteCloser, string, error) {
+	path := filepath.Join(dir, strings.Replace(pattern, "*", strconv.Itoa(len(fs)), 1))
+	w, err := fs.Create(path)
+	return w, path, err
+}
+
+func (fs memFS) Create(path string) (io.WriteCloser, error) {
+	b := &bytes.Buffer{}
+	fs[path] = b
+	return nopWriteCloser{b}, nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func TestCommand_deterministicSources(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	header := http.Header{"X-Key": {"1"}}
+	body := "\x00\x01"
+
+	t.Run("temp fs", func(t *testing.T) {
+		fs := memFS{}
+
+		c, err := NewFromRequest(&http.Request{URL: testUrl, Header: header, Body: readCloser(body)}, WithTempFS(fs), WithHeaderSpill(0, "/spill"), WithHeaderFile("/headers.txt"), WithBinaryBodyDir("/bodies"))
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		want := "curl -X 'GET' 'https://localhost/test' -H '@/headers.txt' --data-binary '@/bodies/curling-body-1.bin'"
+		if got := c.String(); got != want {
+			t.Errorf("String() = %v, want %v", got, want)
+		}
+
+		got := map[string]string{}
+		for path, b := range fs {
+			got[path] = b.String()
+		}
+
+		wantFiles := map[string]string{
+			"/headers.txt":               "X-Key: 1\n",
+			"/bodies/curling-body-1.bin": body,
+		}
+		if !cmp.Equal(got, wantFiles) {
+			t.Errorf("files diff = %v", cmp.Diff(got, wantFiles))
+		}
+	})
+
+	t.Run("id generator", func(t *testing.T) {
+		dir := t.TempDir()
+
+		c, err := NewFromRequest(&http.Request{URL: testUrl, Body: readCloser(body)}, WithBinaryBodyDir(dir), WithIDGenerator(func() string { return "42" }))
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		path := filepath.Join(dir, "curling-body-42.bin")
+		if files := c.Files(); len(files) != 1 || files[0] != path {
+			t.Errorf("Files() = %v, want [%v]", files, path)
+		}
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		c, err := NewFromRequest(&http.Request{URL: testUrl}, WithClock(func() time.Time { return now }))
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		if !c.capturedAt.Equal(now) {
+			t.Errorf("capturedAt = %v, want %v", c.capturedAt, now)
+		}
+	})
+}
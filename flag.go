@@ -0,0 +1,116 @@
+package curling
+
+import "regexp"
+
+// A flag is a cURL option with its short and long forms.
+// Options without a short form have an empty short value.
+type flag struct {
+	short string
+	long  string
+}
+
+// The flags the library can emit.
+var (
+	flagSilent              = flag{short: "-s", long: "--silent"}
+	flagVerbose             = flag{short: "-v", long: "--verbose"}
+	flagInclude             = flag{short: "-i", long: "--include"}
+	flagHead                = flag{short: "-I", long: "--head"}
+	flagFail                = flag{short: "-f", long: "--fail"}
+	flagFailWithBody        = flag{long: "--fail-with-body"}
+	flagMaxTime             = flag{short: "-m", long: "--max-time"}
+	flagConnectTimeout      = flag{long: "--connect-timeout"}
+	flagRetry               = flag{long: "--retry"}
+	flagRetryDelay          = flag{long: "--retry-delay"}
+	flagRetryMaxTime        = flag{long: "--retry-max-time"}
+	flagRetryAllErrors      = flag{long: "--retry-all-errors"}
+	flagInsecure            = flag{short: "-k", long: "--insecure"}
+	flagCompressed          = flag{long: "--compressed"}
+	flagHTTP10              = flag{short: "-0", long: "--http1.0"}
+	flagHTTP11              = flag{long: "--http1.1"}
+	flagHTTP2               = flag{long: "--http2"}
+	flagHTTP2PriorKnowledge = flag{long: "--http2-prior-knowledge"}
+	flagHTTP3               = flag{long: "--http3"}
+	flagTrEncoding          = flag{long: "--tr-encoding"}
+	flagLocation            = flag{short: "-L", long: "--location"}
+	flagRequest             = flag{short: "-X", long: "--request"}
+	flagHeader              = flag{short: "-H", long: "--header"}
+	flagUserAgent           = flag{short: "-A", long: "--user-agent"}
+	flagReferer             = flag{short: "-e", long: "--referer"}
+	flagRange               = flag{short: "-r", long: "--range"}
+	flagData                = flag{short: "-d", long: "--data"}
+	flagURLEncode           = flag{long: "--data-urlencode"}
+	flagDataBinary          = flag{long: "--data-binary"}
+	flagDataRaw             = flag{long: "--data-raw"}
+	flagPathAsIs            = flag{long: "--path-as-is"}
+	flagJSON                = flag{long: "--json"}
+	flagGlobOff             = flag{short: "-g", long: "--globoff"}
+	flagOutput              = flag{short: "-o", long: "--output"}
+	flagRemoteName          = flag{short: "-O", long: "--remote-name"}
+	flagOutputDir           = flag{long: "--output-dir"}
+	flagCreateDirs          = flag{long: "--create-dirs"}
+	flagWriteOut            = flag{short: "-w", long: "--write-out"}
+	flagMaxRedirs           = flag{long: "--max-redirs"}
+	flagProxy               = flag{short: "-x", long: "--proxy"}
+	flagProxyUser           = flag{short: "-U", long: "--proxy-user"}
+	flagNoProxy             = flag{long: "--noproxy"}
+	flagSOCKS5              = flag{long: "--socks5"}
+	flagSOCKS5Hostname      = flag{long: "--socks5-hostname"}
+	flagCert                = flag{short: "-E", long: "--cert"}
+	flagKey                 = flag{long: "--key"}
+	flagCACert              = flag{long: "--cacert"}
+	flagConfig              = flag{short: "-K", long: "--config"}
+	flagCookie              = flag{short: "-b", long: "--cookie"}
+	flagUser                = flag{short: "-u", long: "--user"}
+	flagOAuth2Bearer        = flag{long: "--oauth2-bearer"}
+	flagDigest              = flag{long: "--digest"}
+	flagNTLM                = flag{long: "--ntlm"}
+	flagNegotiate           = flag{long: "--negotiate"}
+	flagAnyAuth             = flag{long: "--anyauth"}
+	flagCookieJar           = flag{short: "-c", long: "--cookie-jar"}
+	flagJunkSessionCookies  = flag{short: "-j", long: "--junk-session-cookies"}
+)
+
+// valueFlags lists the flags that take an argument.
+var valueFlags = []flag{
+	flagMaxTime, flagConnectTimeout, flagRetry, flagRetryDelay, flagRetryMaxTime,
+	flagRequest, flagHeader, flagUserAgent, flagReferer, flagRange, flagData, flagURLEncode, flagDataBinary, flagDataRaw, flagJSON,
+	flagOutput, flagOutputDir, flagWriteOut, flagMaxRedirs, flagProxy, flagProxyUser, flagNoProxy,
+	flagSOCKS5, flagSOCKS5Hostname, flagCert, flagKey, flagCACert, flagConfig, flagCookie, flagCookieJar,
+	flagUser, flagOAuth2Bearer,
+}
+
+// switchFlags lists the flags that take no argument.
+var switchFlags = []flag{
+	flagSilent, flagVerbose, flagInclude, flagHead, flagFail, flagFailWithBody, flagRetryAllErrors,
+	flagInsecure, flagCompressed, flagHTTP10, flagHTTP11, flagHTTP2, flagHTTP2PriorKnowledge, flagHTTP3,
+	flagTrEncoding, flagLocation, flagPathAsIs, flagGlobOff, flagRemoteName, flagCreateDirs,
+	flagJunkSessionCookies, flagDigest, flagNTLM, flagNegotiate, flagAnyAuth,
+}
+
+// option returns the form of f based on the useLongForm flag,
+// falling back to the long form when f has no short one.
+func (c *Command) option(f flag) string {
+	if f.short == "" {
+		return f.long
+	}
+
+	return c.optionForm(f.short, f.long)
+}
+
+// bareArgument matches the arguments that need no quoting in any shell.
+var bareArgument = regexp.MustCompile(`^[A-Za-z0-9_./:=@+,-]+$`)
+
+// extraFlags returns the arguments set with WithExtraFlags, quoted when needed.
+func (c *Command) extraFlags() []word {
+	s := make([]word, 0, len(c.extraArgs))
+	for _, arg := range c.extraArgs {
+		if bareArgument.MatchString(arg) {
+			s = append(s, literal(arg))
+			continue
+		}
+
+		s = append(s, value(arg))
+	}
+
+	return s
+}
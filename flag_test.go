@@ -0,0 +1,301 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// curlFlags lists every cURL flag the library can emit.
+var curlFlags = []flag{
+	flagSilent,
+	flagMaxTime,
+	flagInsecure,
+	flagCompressed,
+	flagLocation,
+	flagRequest,
+	flagHeader,
+	flagData,
+}
+
+// formEncodingFlags lists the cURL flags the library emits with form encoding.
+var formEncodingFlags = []flag{
+	flagURLEncode,
+}
+
+// binaryBodyFlags lists the cURL flags the library emits for binary bodies.
+var binaryBodyFlags = []flag{
+	flagDataBinary,
+}
+
+// jsonFlags lists the cURL flags the library emits with the JSON flag.
+var jsonFlags = []flag{
+	flagJSON,
+}
+
+// faithfulFlags lists the cURL flags the library emits in faithful mode.
+var faithfulFlags = []flag{
+	flagDataBinary,
+	flagPathAsIs,
+	flagGlobOff,
+}
+
+// smokeTestFlags lists the cURL flags the library emits in smoke test scripts.
+var smokeTestFlags = []flag{
+	flagOutput,
+	flagWriteOut,
+}
+
+// wgetFlags lists every wget flag the library can emit.
+var wgetFlags = []flag{
+	wgetFlagQuiet,
+	wgetFlagTimeout,
+	wgetFlagNoCheckCertificate,
+	wgetFlagCompression,
+	wgetFlagOutputDocument,
+	wgetFlagMethod,
+	wgetFlagHeader,
+	wgetFlagBodyData,
+}
+
+// allOptions returns an option set that makes the builders emit every flag.
+func allOptions() []Option {
+	return []Option{
+		WithFollowRedirects(),
+		WithCompression(),
+		WithInsecure(),
+		WithSilent(),
+		WithRequestTimeout(5),
+	}
+}
+
+// allFlagsRequest returns a request that makes the builders emit every flag.
+func allFlagsRequest() *http.Request {
+	return &http.Request{
+		Method: http.MethodPost,
+		URL: &url.URL{
+			Scheme: "https",
+			Host:   "localhost",
+			Path:   "test",
+		},
+		Header: http.Header{"X-Key": {"-k"}, "Content-Type": {formContentType}},
+		Body:   readCloser("-d"),
+	}
+}
+
+func Test_flag_forms(t *testing.T) {
+	shortForm := regexp.MustCompile(`^-[A-Za-z]$`)
+	longForm := regexp.MustCompile(`^--[a-z0-9-]+$`)
+
+	lists := [][]flag{curlFlags, formEncodingFlags, jsonFlags, binaryBodyFlags, faithfulFlags, smokeTestFlags, wgetFlags}
+
+	for _, flags := range lists {
+		for _, f := range flags {
+			if !longForm.MatchString(f.long) {
+				t.Errorf("flag %v has an invalid long form", f)
+			}
+			if f.short != "" && !shortForm.MatchString(f.short) {
+				t.Errorf("flag %v has an invalid short form", f)
+			}
+		}
+	}
+}
+
+func TestCommand_option(t *testing.T) {
+	tests := []struct {
+		name        string
+		useLongForm bool
+		f           flag
+		want        string
+	}{
+		{
+			name: "short form",
+			f:    flagHeader,
+			want: "-H",
+		},
+		{
+			name:        "long form",
+			useLongForm: true,
+			f:           flagHeader,
+			want:        "--header",
+		},
+		{
+			name: "short form without short variant",
+			f:    flagCompressed,
+			want: "--compressed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Command{
+				useLongForm: tt.useLongForm,
+			}
+			if got := c.option(tt.f); got != tt.want {
+				t.Errorf("option() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NewFromRequest_longFormEverything(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		contentType string
+		body        string
+		render      func(c *Command) string
+		flags       []flag
+	}{
+		{
+			name:   "curl",
+			render: (*Command).String,
+			flags:  curlFlags,
+		},
+		{
+			name:   "curl with form encoding",
+			opts:   []Option{WithFormEncoding()},
+			render: (*Command).String,
+			flags:  formEncodingFlags,
+		},
+		{
+			name:        "curl with json flag",
+			opts:        []Option{WithJSONFlag()},
+			contentType: "application/json",
+			render:      (*Command).String,
+			flags:       jsonFlags,
+		},
+		{
+			name:   "curl faithful",
+			opts:   []Option{WithFaithful()},
+			render: (*Command).String,
+			flags:  faithfulFlags,
+		},
+		{
+			name:   "curl with binary body",
+			opts:   []Option{WithOutputVersion(OutputVersion2)},
+			body:   "\x00",
+			render: (*Command).String,
+			flags:  binaryBodyFlags,
+		},
+		{
+			name: "smoke test",
+			render: func(c *Command) string {
+				return SmokeTestScript([]SmokeTest{{Command: c, Status: http.StatusOK}})
+			},
+			flags: smokeTestFlags,
+		},
+		{
+			name:   "wget",
+			render: (*Command).ToWget,
+			flags:  wgetFlags,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := allFlagsRequest()
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.body != "" {
+				r.Body = readCloser(tt.body)
+			}
+
+			c, err := NewFromRequest(r, append(append(allOptions(), WithLongForm()), tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			got := tt.render(c)
+			words := strings.Fields(got)
+
+			for _, f := range tt.flags {
+				if !strings.Contains(got, f.long) {
+					t.Errorf("%s: long flag %s is missing", got, f.long)
+				}
+				for _, w := range words {
+					if f.short != "" && w == f.short {
+						t.Errorf("%s: short flag %s is emitted in long form mode", got, f.short)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCommand_String_diagnosticFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "verbose",
+			opts: []Option{WithVerbose()},
+			want: "curl -v -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "include response headers",
+			opts: []Option{WithIncludeResponseHeaders()},
+			want: "curl -i -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "long form",
+			opts: []Option{WithLongForm(), WithSilent(), WithVerbose(), WithIncludeResponseHeaders(), WithRequestTimeout(5)},
+			want: "curl --silent --verbose --include --max-time 5 --request 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithFailFast(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "fail fast",
+			opts: []Option{WithFailFast()},
+			want: "curl -f -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "fail with body",
+			opts: []Option{WithFailWithBody()},
+			want: "curl --fail-with-body -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "long form",
+			opts: []Option{WithLongForm(), WithSilent(), WithFailFast(), WithRequestTimeout(5)},
+			want: "curl --silent --fail --max-time 5 --request 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "fail with body replaces fail fast",
+			opts: []Option{WithFailFast(), WithFailWithBody()},
+			want: "curl --fail-with-body -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "fail fast replaces fail with body",
+			opts: []Option{WithFailWithBody(), WithFailFast()},
+			want: "curl -f -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package curling
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// gatlingMethods maps the request methods to the Gatling Java DSL builders.
+var gatlingMethods = map[string]string{
+	http.MethodGet:     "get",
+	http.MethodPost:    "post",
+	http.MethodPut:     "put",
+	http.MethodPatch:   "patch",
+	http.MethodDelete:  "delete",
+	http.MethodHead:    "head",
+	http.MethodOptions: "options",
+}
+
+// ToGatling returns a Gatling simulation, written with the Java DSL in a class
+// named after className, whose scenario sends the requests in order with a
+// single user, for teams whose load testing tooling isn't shell-based.
+// Binary bodies are read from the body.bin file, as in the cURL command.
+func (s CommandSet) ToGatling(className string) string {
+	var b strings.Builder
+
+	b.WriteString("import static io.gatling.javaapi.core.CoreDsl.*;\n")
+	b.WriteString("import static io.gatling.javaapi.http.HttpDsl.*;\n\n")
+	b.WriteString("import io.gatling.javaapi.core.*;\n")
+	b.WriteString("import io.gatling.javaapi.http.*;\n\n")
+	fmt.Fprintf(&b, "public class %s extends Simulation {\n\n", className)
+	fmt.Fprintf(&b, "  ScenarioBuilder scn = scenario(%s)", jsonQuote(className))
+
+	for i, c := range s {
+		b.WriteString("\n    .exec(\n")
+		fmt.Fprintf(&b, "      http(%s)\n", jsonQuote(fmt.Sprintf("request_%d", i+1)))
+		b.WriteString(c.gatlingRequest())
+		b.WriteString("\n    )")
+	}
+
+	b.WriteString(";\n\n")
+	b.WriteString("  {\n")
+	b.WriteString("    setUp(scn.injectOpen(atOnceUsers(1))).protocols(http);\n")
+	b.WriteString("  }\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// gatlingRequest returns the Gatling Java DSL calls building the request.
+func (c *Command) gatlingRequest() string {
+	var lines []string
+
+	url := jsonQuote(c.request.url.String())
+	if method, ok := gatlingMethods[c.request.method]; ok {
+		lines = append(lines, fmt.Sprintf(".%s(%s)", method, url))
+	} else {
+		lines = append(lines, fmt.Sprintf(".httpRequest(%s, %s)", jsonQuote(c.request.method), url))
+	}
+
+	for _, field := range c.request.headerFields() {
+		lines = append(lines, fmt.Sprintf(".header(%s, %s)", jsonQuote(field.key), jsonQuote(field.value)))
+	}
+
+	if c.request.hasBody {
+		if isText(c.request.body) {
+			lines = append(lines, fmt.Sprintf(".body(StringBody(%s))", jsonQuote(string(c.request.body))))
+		} else {
+			lines = append(lines, fmt.Sprintf(".body(RawFileBody(%s))", jsonQuote(binaryBodyFileName)))
+		}
+	}
+
+	return "        " + strings.Join(lines, "\n        ")
+}
@@ -0,0 +1,50 @@
+package curling
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCommandSet_ToGatling(t *testing.T) {
+	set := CommandSet{
+		mustNewFromRequest(t, http.MethodGet, "https://localhost/a?q=1"),
+		mustNewWithBody(t, http.MethodPost, "https://localhost/b",
+			http.Header{"Content-Type": {"application/json"}}, `{"key":"value"}`),
+		mustNewWithBody(t, "PURGE", "https://localhost/c", nil, "\x00"),
+	}
+
+	want := `import static io.gatling.javaapi.core.CoreDsl.*;
+import static io.gatling.javaapi.http.HttpDsl.*;
+
+import io.gatling.javaapi.core.*;
+import io.gatling.javaapi.http.*;
+
+public class Session extends Simulation {
+
+  ScenarioBuilder scn = scenario("Session")
+    .exec(
+      http("request_1")
+        .get("https://localhost/a?q=1")
+    )
+    .exec(
+      http("request_2")
+        .post("https://localhost/b")
+        .header("Content-Type", "application/json")
+        .body(StringBody("{\"key\":\"value\"}"))
+    )
+    .exec(
+      http("request_3")
+        .httpRequest("PURGE", "https://localhost/c")
+        .body(RawFileBody("body.bin"))
+    );
+
+  {
+    setUp(scn.injectOpen(atOnceUsers(1))).protocols(http);
+  }
+}
+`
+
+	if got := set.ToGatling("Session"); got != want {
+		t.Errorf("ToGatling() = %v, want %v", got, want)
+	}
+}
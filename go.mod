@@ -2,4 +2,8 @@ module github.com/aoliveti/curling
 
 go 1.21
 
-require github.com/google/go-cmp v0.6.0
+require (
+	github.com/andybalholm/brotli v1.1.0
+	github.com/google/go-cmp v0.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,93 @@
+package curling
+
+import (
+	"fmt"
+	"go/format"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToGo returns the source of a Go program that reproduces the request with net/http.
+//
+// The program builds the request with [http.NewRequest], sets every header value
+// and sends it with an [http.Client]. The insecure and request timeout options
+// are reflected in the client configuration.
+// Note that the Go client follows redirects by default.
+func (c *Command) ToGo() string {
+	imports := []string{"log", "net/http"}
+
+	var b strings.Builder
+	b.WriteString("func main() {\n")
+
+	body := "nil"
+	if c.request.hasBody {
+		imports = append(imports, "strings")
+		body = fmt.Sprintf("strings.NewReader(%s)", strconv.Quote(string(c.request.body)))
+	}
+
+	fmt.Fprintf(&b, "req, err := http.NewRequest(%s, %s, %s)\n",
+		strconv.Quote(c.request.method), strconv.Quote(c.request.url.String()), body)
+	b.WriteString("if err != nil {\nlog.Fatal(err)\n}\n")
+
+	keys := make([]string, 0, len(c.request.header))
+	for key := range c.request.header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range c.request.header[key] {
+			fmt.Fprintf(&b, "req.Header.Add(%s, %s)\n", strconv.Quote(http.CanonicalHeaderKey(key)), strconv.Quote(value))
+		}
+	}
+
+	b.WriteString("\nclient := &http.Client{\n")
+
+	if c.requestTimeout > 0 {
+		imports = append(imports, "time")
+		switch {
+		case c.requestTimeout%time.Second == 0:
+			fmt.Fprintf(&b, "Timeout: %d * time.Second,\n", c.requestTimeout/time.Second)
+		case c.requestTimeout%time.Millisecond == 0:
+			fmt.Fprintf(&b, "Timeout: %d * time.Millisecond,\n", c.requestTimeout/time.Millisecond)
+		default:
+			// Shorter units would round the timeout down, possibly to zero, which disables it.
+			fmt.Fprintf(&b, "Timeout: time.Duration(%d),\n", int64(c.requestTimeout))
+		}
+	}
+
+	if c.insecure {
+		imports = append(imports, "crypto/tls")
+		b.WriteString("Transport: &http.Transport{\n")
+		b.WriteString("TLSClientConfig: &tls.Config{InsecureSkipVerify: true},\n")
+		b.WriteString("},\n")
+	}
+
+	b.WriteString("}\n\n")
+	b.WriteString("resp, err := client.Do(req)\n")
+	b.WriteString("if err != nil {\nlog.Fatal(err)\n}\n")
+	b.WriteString("defer resp.Body.Close()\n\n")
+	b.WriteString("log.Println(resp.Status)\n")
+	b.WriteString("}\n")
+
+	slices.Sort(imports)
+
+	var src strings.Builder
+	src.WriteString("package main\n\nimport (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&src, "%s\n", strconv.Quote(imp))
+	}
+	src.WriteString(")\n\n")
+	src.WriteString(b.String())
+
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		// The generated source is valid Go, fall back to the unformatted text anyway.
+		return src.String()
+	}
+
+	return string(formatted)
+}
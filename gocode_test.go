@@ -0,0 +1,162 @@
+package curling
+
+import (
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_ToGo(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	type args struct {
+		r    *http.Request
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty method",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+			},
+			want: `package main
+
+import (
+	"log"
+	"net/http"
+)
+
+func main() {
+	req, err := http.NewRequest("GET", "https://localhost/test", nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client := &http.Client{}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	log.Println(resp.Status)
+}
+`,
+		},
+		{
+			name: "headers body and options",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Header: http.Header{
+						"x-key":        {"1", "2"},
+						"Content-Type": {"text/plain"},
+					},
+					Body: readCloser("line\n\"quoted\""),
+				},
+				opts: []Option{WithRequestTimeout(5), WithInsecure()},
+			},
+			want: `package main
+
+import (
+	"crypto/tls"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func main() {
+	req, err := http.NewRequest("POST", "https://localhost/test", strings.NewReader("line\n\"quoted\""))
+	if err != nil {
+		log.Fatal(err)
+	}
+	req.Header.Add("Content-Type", "text/plain")
+	req.Header.Add("X-Key", "1")
+	req.Header.Add("X-Key", "2")
+
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	log.Println(resp.Status)
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.args.r, tt.args.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			got := c.ToGo()
+			if got != tt.want {
+				t.Errorf("ToGo() = %v, want %v", got, tt.want)
+			}
+
+			if _, err := parser.ParseFile(token.NewFileSet(), "main.go", got, 0); err != nil {
+				t.Errorf("ToGo() produced invalid source: %v", err)
+			}
+		})
+	}
+}
+
+func TestCommand_ToGo_timeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    string
+	}{
+		{
+			name:    "seconds",
+			timeout: 5 * time.Second,
+			want:    "Timeout: 5 * time.Second,",
+		},
+		{
+			name:    "milliseconds",
+			timeout: 1500 * time.Millisecond,
+			want:    "Timeout: 1500 * time.Millisecond,",
+		},
+		{
+			name:    "below a millisecond",
+			timeout: 500 * time.Microsecond,
+			want:    "Timeout: time.Duration(500000),",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", WithRequestTimeoutDuration(tt.timeout))
+
+			if got := c.ToGo(); !strings.Contains(got, tt.want) {
+				t.Errorf("ToGo() = %v, want it to contain %v", got, tt.want)
+			}
+		})
+	}
+}
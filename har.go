@@ -0,0 +1,212 @@
+package curling
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// A HARRequest is the request object of a HAR 1.2 entry.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// A HARNameValue is a HAR 1.2 header or query string parameter.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// A HARCookie is a HAR 1.2 cookie.
+type HARCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// A HARPostData is the posted data of a HAR 1.2 request.
+type HARPostData struct {
+	MimeType string         `json:"mimeType"`
+	Params   []HARNameValue `json:"params,omitempty"`
+	Text     string         `json:"text"`
+}
+
+// harFile is the part of a HAR 1.2 file read by [NewFromHAR].
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request HARRequest `json:"request"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// NewFromHAR returns a new [Command] for each entry of the HAR log read from r,
+// such as the ones exported by browser developer tools.
+// HTTP/2 pseudo-headers are left out, and cookies are sent with a Cookie header
+// when the entry doesn't have one. Posted params are url-encoded when the entry has no text.
+// If the log can't be decoded or an entry has an invalid URL, NewFromHAR returns an error.
+func NewFromHAR(r io.Reader, opts ...Option) ([]*Command, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("decoding har: %w", err)
+	}
+
+	commands := make([]*Command, 0, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		req, err := entry.Request.httpRequest()
+		if err != nil {
+			return nil, fmt.Errorf("har entry %d: %w", i, err)
+		}
+
+		c, err := NewFromRequest(req, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("har entry %d: %w", i, err)
+		}
+
+		commands = append(commands, c)
+	}
+
+	return commands, nil
+}
+
+// httpRequest returns the [http.Request] described by h.
+func (h HARRequest) httpRequest() (*http.Request, error) {
+	u, err := url.Parse(h.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing request url: %w", err)
+	}
+
+	r := &http.Request{
+		Method: h.Method,
+		URL:    u,
+		Proto:  h.HTTPVersion,
+		Header: http.Header{},
+	}
+
+	for _, header := range h.Headers {
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+		r.Header.Add(header.Name, header.Value)
+	}
+
+	if r.Header.Get("Cookie") == "" {
+		for _, cookie := range h.Cookies {
+			r.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+		}
+	}
+
+	if h.PostData == nil {
+		return r, nil
+	}
+
+	text := h.PostData.Text
+	if text == "" && len(h.PostData.Params) > 0 {
+		form := url.Values{}
+		for _, param := range h.PostData.Params {
+			form.Add(param.Name, param.Value)
+		}
+		text = form.Encode()
+	}
+
+	if r.Header.Get("Content-Type") == "" && h.PostData.MimeType != "" {
+		r.Header.Set("Content-Type", h.PostData.MimeType)
+	}
+
+	r.Body = io.NopCloser(strings.NewReader(text))
+
+	return r, nil
+}
+
+// ToHAR returns the request as the request object of a HAR 1.2 entry.
+// Headers are sorted by name, query string parameters keep the URL order.
+// The headers size is always reported as unknown.
+func (c *Command) ToHAR() HARRequest {
+	h := HARRequest{
+		Method:      c.request.method,
+		URL:         c.request.url.String(),
+		HTTPVersion: c.request.proto,
+		Cookies:     []HARCookie{},
+		Headers:     []HARNameValue{},
+		QueryString: harQueryString(c.request.url.RawQuery),
+		HeadersSize: -1,
+	}
+
+	keys := make([]string, 0, len(c.request.header))
+	for key := range c.request.header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range c.request.header[key] {
+			h.Headers = append(h.Headers, HARNameValue{Name: http.CanonicalHeaderKey(key), Value: value})
+		}
+	}
+
+	r := http.Request{Header: c.request.header}
+	for _, cookie := range r.Cookies() {
+		h.Cookies = append(h.Cookies, HARCookie{Name: cookie.Name, Value: cookie.Value})
+	}
+
+	if c.request.hasBody {
+		h.BodySize = len(c.request.body)
+		h.PostData = &HARPostData{
+			MimeType: c.request.header.Get("Content-Type"),
+			Text:     string(c.request.body),
+		}
+	}
+
+	return h
+}
+
+// MarshalHAR returns the JSON encoding of the HAR 1.2 request object returned by [Command.ToHAR].
+func (c *Command) MarshalHAR() ([]byte, error) {
+	return json.Marshal(c.ToHAR())
+}
+
+// harQueryString returns the parameters of the raw query in their original order.
+// Parameters that can't be unescaped are kept as they are.
+func harQueryString(rawQuery string) []HARNameValue {
+	params := []HARNameValue{}
+
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(pair, "=")
+		params = append(params, HARNameValue{
+			Name:  queryUnescape(name),
+			Value: queryUnescape(value),
+		})
+	}
+
+	return params
+}
+
+// queryUnescape returns the unescaped s, or s itself when it is not properly escaped.
+func queryUnescape(s string) string {
+	v, err := url.QueryUnescape(s)
+	if err != nil {
+		return s
+	}
+
+	return v
+}
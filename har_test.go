@@ -0,0 +1,176 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCommand_ToHAR(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme:   "https",
+		Host:     "localhost",
+		Path:     "test",
+		RawQuery: "b=2&a=1+%26&flag&bad=%zz",
+	}
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		want HARRequest
+	}{
+		{
+			name: "without body",
+			r: &http.Request{
+				URL: testUrl,
+			},
+			want: HARRequest{
+				Method:      http.MethodGet,
+				URL:         "https://localhost/test?b=2&a=1+%26&flag&bad=%zz",
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []HARCookie{},
+				Headers:     []HARNameValue{},
+				QueryString: []HARNameValue{
+					{Name: "b", Value: "2"},
+					{Name: "a", Value: "1 &"},
+					{Name: "flag", Value: ""},
+					{Name: "bad", Value: "%zz"},
+				},
+				HeadersSize: -1,
+			},
+		},
+		{
+			name: "with headers cookies and body",
+			r: &http.Request{
+				Method: http.MethodPost,
+				URL:    &url.URL{Scheme: "https", Host: "localhost"},
+				Proto:  "HTTP/2.0",
+				Header: http.Header{
+					"Content-Type": {"application/json"},
+					"Cookie":       {"a=1; b=2"},
+					"X-Key":        {"1", "2"},
+				},
+				Body: readCloser(`{"key":"value"}`),
+			},
+			want: HARRequest{
+				Method:      http.MethodPost,
+				URL:         "https://localhost",
+				HTTPVersion: "HTTP/2.0",
+				Cookies: []HARCookie{
+					{Name: "a", Value: "1"},
+					{Name: "b", Value: "2"},
+				},
+				Headers: []HARNameValue{
+					{Name: "Content-Type", Value: "application/json"},
+					{Name: "Cookie", Value: "a=1; b=2"},
+					{Name: "X-Key", Value: "1"},
+					{Name: "X-Key", Value: "2"},
+				},
+				QueryString: []HARNameValue{},
+				PostData: &HARPostData{
+					MimeType: "application/json",
+					Text:     `{"key":"value"}`,
+				},
+				HeadersSize: -1,
+				BodySize:    15,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.r)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.ToHAR(); !cmp.Equal(got, tt.want) {
+				t.Errorf("ToHAR() diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCommand_MarshalHAR(t *testing.T) {
+	c, err := NewFromRequest(&http.Request{URL: &url.URL{Scheme: "https", Host: "localhost"}})
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	want := `{"method":"GET","url":"https://localhost","httpVersion":"HTTP/1.1","cookies":[],"headers":[],"queryString":[],"headersSize":-1,"bodySize":0}`
+
+	got, err := c.MarshalHAR()
+	if err != nil {
+		t.Fatalf("MarshalHAR() error = %v", err)
+	}
+
+	if string(got) != want {
+		t.Errorf("MarshalHAR() = %s, want %s", got, want)
+	}
+}
+
+func Test_NewFromHAR(t *testing.T) {
+	tests := []struct {
+		name    string
+		har     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "invalid json",
+			har:     `{"log":`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid url",
+			har:     `{"log":{"entries":[{"request":{"method":"GET","url":"://localhost"}}]}}`,
+			wantErr: true,
+		},
+		{
+			name: "no entries",
+			har:  `{"log":{"entries":[]}}`,
+			want: []string{},
+		},
+		{
+			name: "entries",
+			har: `{"log":{"entries":[
+				{"request":{"method":"GET","url":"https://localhost/a","httpVersion":"HTTP/2",
+					"headers":[{"name":":authority","value":"localhost"},{"name":"accept","value":"*/*"}],
+					"cookies":[{"name":"session","value":"1"}]}},
+				{"request":{"method":"POST","url":"https://localhost/b",
+					"headers":[],
+					"postData":{"mimeType":"application/json","text":"{\"key\":\"value\"}"}}},
+				{"request":{"method":"POST","url":"https://localhost/c",
+					"headers":[],
+					"postData":{"mimeType":"application/x-www-form-urlencoded","params":[{"name":"key","value":"a b"}]}}}
+			]}}`,
+			want: []string{
+				"curl -X 'GET' 'https://localhost/a' -H 'Accept: */*' -H 'Cookie: session=1'",
+				"curl -X 'POST' 'https://localhost/b' -H 'Content-Type: application/json' -d '{\"key\":\"value\"}'",
+				"curl -X 'POST' 'https://localhost/c' -H 'Content-Type: application/x-www-form-urlencoded' -d 'key=a+b'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFromHAR(strings.NewReader(tt.har))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromHAR() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			commands := make([]string, 0, len(got))
+			for _, c := range got {
+				commands = append(commands, c.String())
+			}
+
+			if !cmp.Equal(commands, tt.want) {
+				t.Errorf("NewFromHAR() diff = %v", cmp.Diff(commands, tt.want))
+			}
+		})
+	}
+}
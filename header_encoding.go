@@ -0,0 +1,82 @@
+package curling
+
+import (
+	"fmt"
+	"mime"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// A HeaderEncoding sets how non-ASCII header values are rendered.
+type HeaderEncoding int
+
+const (
+	// HeaderEncodingRaw passes the header values through as they are.
+	HeaderEncodingRaw HeaderEncoding = iota
+
+	// HeaderEncodingPercent percent-encodes the non-ASCII bytes of header values,
+	// as in %C3%A9 for é.
+	HeaderEncodingPercent
+
+	// HeaderEncodingMIME encodes non-ASCII header values as RFC 2047 encoded-words,
+	// as in =?utf-8?q?caf=C3=A9?= for café.
+	HeaderEncodingMIME
+)
+
+// encodeHeaders reports the header values containing non-ASCII characters
+// and encodes them according to the configured [HeaderEncoding].
+func (c *Command) encodeHeaders() {
+	keys := make([]string, 0, len(c.request.header))
+	for key := range c.request.header {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		values := c.request.header[key]
+		for i, value := range values {
+			if isASCII(value) {
+				continue
+			}
+
+			c.warn(WarningNonASCIIHeader, "header %s contains non-ASCII characters, which servers may interpret differently", key)
+
+			switch c.headerEncoding {
+			case HeaderEncodingPercent:
+				values[i] = percentEncodeNonASCII(value)
+			case HeaderEncodingMIME:
+				if utf8.ValidString(value) {
+					values[i] = mime.QEncoding.Encode("utf-8", value)
+				}
+			}
+		}
+	}
+}
+
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// percentEncodeNonASCII replaces the non-ASCII bytes of s with their percent-encoded form.
+func percentEncodeNonASCII(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] < utf8.RuneSelf {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		fmt.Fprintf(&b, "%%%02X", s[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"testing"
+)
+
+func TestCommand_String_headerEncoding(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []Option
+		value        string
+		want         string
+		wantWarnings []Warning
+	}{
+		{
+			name:  "ascii",
+			value: "plain",
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'X-Name: plain'",
+		},
+		{
+			name:  "raw",
+			value: "café",
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'X-Name: café'",
+			wantWarnings: []Warning{
+				{Code: WarningNonASCIIHeader, Message: "header X-Name contains non-ASCII characters, which servers may interpret differently"},
+			},
+		},
+		{
+			name:  "percent",
+			opts:  []Option{WithHeaderEncoding(HeaderEncodingPercent)},
+			value: "café 100%",
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'X-Name: caf%C3%A9 100%'",
+			wantWarnings: []Warning{
+				{Code: WarningNonASCIIHeader, Message: "header X-Name contains non-ASCII characters, which servers may interpret differently"},
+			},
+		},
+		{
+			name:  "mime",
+			opts:  []Option{WithHeaderEncoding(HeaderEncodingMIME)},
+			value: "café",
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'X-Name: =?utf-8?q?caf=C3=A9?='",
+			wantWarnings: []Warning{
+				{Code: WarningNonASCIIHeader, Message: "header X-Name contains non-ASCII characters, which servers may interpret differently"},
+			},
+		},
+		{
+			name:  "mime with invalid utf-8",
+			opts:  []Option{WithHeaderEncoding(HeaderEncodingMIME)},
+			value: "caf\xe9",
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'X-Name: caf\xe9'",
+			wantWarnings: []Warning{
+				{Code: WarningNonASCIIHeader, Message: "header X-Name contains non-ASCII characters, which servers may interpret differently"},
+			},
+		},
+		{
+			name:  "encoded-word passed through",
+			opts:  []Option{WithHeaderEncoding(HeaderEncodingMIME)},
+			value: "=?utf-8?q?caf=C3=A9?=",
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'X-Name: =?utf-8?q?caf=C3=A9?='",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header.Set("X-Name", tt.value)
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+			if got := c.Validate(); !cmp.Equal(got, tt.wantWarnings) {
+				t.Errorf("Validate() diff = %v", cmp.Diff(got, tt.wantWarnings))
+			}
+		})
+	}
+}
+
+func Test_percentEncodeNonASCII(t *testing.T) {
+	if got, want := percentEncodeNonASCII("a é\xff"), "a %C3%A9%FF"; got != want {
+		t.Errorf("percentEncodeNonASCII() = %v, want %v", got, want)
+	}
+}
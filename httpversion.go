@@ -0,0 +1,96 @@
+package curling
+
+import "net/http"
+
+// An HTTPVersion sets the HTTP version cURL uses to send the request.
+type HTTPVersion int
+
+const (
+	// HTTPVersionDefault lets cURL negotiate the HTTP version, no flag is set.
+	HTTPVersionDefault HTTPVersion = iota
+
+	// HTTPVersion10 sets the flag -0, --http1.0.
+	HTTPVersion10
+
+	// HTTPVersion11 sets the flag --http1.1.
+	HTTPVersion11
+
+	// HTTPVersion2 sets the flag --http2, which negotiates HTTP/2 and falls back to HTTP/1.1.
+	HTTPVersion2
+
+	// HTTPVersion2PriorKnowledge sets the flag --http2-prior-knowledge,
+	// which sends HTTP/2 right away, without upgrade.
+	HTTPVersion2PriorKnowledge
+
+	// HTTPVersion3 sets the flag --http3.
+	HTTPVersion3
+)
+
+// httpVersionFlags maps the HTTP versions to their flags.
+var httpVersionFlags = map[HTTPVersion]flag{
+	HTTPVersion10:              flagHTTP10,
+	HTTPVersion11:              flagHTTP11,
+	HTTPVersion2:               flagHTTP2,
+	HTTPVersion2PriorKnowledge: flagHTTP2PriorKnowledge,
+	HTTPVersion3:               flagHTTP3,
+}
+
+// WithHTTPVersion sets the HTTP version cURL uses to send the request, such
+// as [HTTPVersion2], to debug protocol-specific behavior.
+// Unknown versions will be silently ignored.
+func WithHTTPVersion(version HTTPVersion) Option {
+	return func(curling *Command) {
+		if _, ok := httpVersionFlags[version]; !ok && version != HTTPVersionDefault {
+			return
+		}
+
+		curling.httpVersion = version
+		curling.inferHTTPVersion = false
+	}
+}
+
+// WithInferredHTTPVersion sets the HTTP version cURL uses to the protocol
+// version of the request: HTTP/1.0, HTTP/1.1, HTTP/2, with prior knowledge
+// for plain http URLs, or HTTP/3. Requests built by clients default to HTTP/1.1,
+// so it is most useful with server requests, such as in [Middleware].
+func WithInferredHTTPVersion() Option {
+	return func(curling *Command) {
+		curling.inferHTTPVersion = true
+	}
+}
+
+// inferredHTTPVersion returns the HTTP version matching the protocol of the request.
+func (c *Command) inferredHTTPVersion() HTTPVersion {
+	major, minor, ok := http.ParseHTTPVersion(c.request.proto)
+	switch {
+	case !ok:
+		return HTTPVersionDefault
+	case major == 1 && minor == 0:
+		return HTTPVersion10
+	case major == 1:
+		return HTTPVersion11
+	case major == 2 && c.request.url.Scheme == "http":
+		return HTTPVersion2PriorKnowledge
+	case major == 2:
+		return HTTPVersion2
+	case major == 3:
+		return HTTPVersion3
+	}
+
+	return HTTPVersionDefault
+}
+
+// httpVersionOptions returns the flag selecting the HTTP version, if any.
+func (c *Command) httpVersionOptions() []word {
+	version := c.httpVersion
+	if c.inferHTTPVersion {
+		version = c.inferredHTTPVersion()
+	}
+
+	f, ok := httpVersionFlags[version]
+	if !ok {
+		return nil
+	}
+
+	return []word{literal(c.option(f))}
+}
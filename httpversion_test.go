@@ -0,0 +1,125 @@
+package curling
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithHTTPVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "default",
+			opts: []Option{WithHTTPVersion(HTTPVersionDefault)},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "http/1.0",
+			opts: []Option{WithHTTPVersion(HTTPVersion10)},
+			want: "curl -0 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "http/1.0 long form",
+			opts: []Option{WithHTTPVersion(HTTPVersion10), WithLongForm()},
+			want: "curl --http1.0 --request 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "http/1.1",
+			opts: []Option{WithHTTPVersion(HTTPVersion11)},
+			want: "curl --http1.1 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "http/2",
+			opts: []Option{WithHTTPVersion(HTTPVersion2)},
+			want: "curl --http2 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "http/2 prior knowledge",
+			opts: []Option{WithHTTPVersion(HTTPVersion2PriorKnowledge)},
+			want: "curl --http2-prior-knowledge -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "http/3",
+			opts: []Option{WithHTTPVersion(HTTPVersion3)},
+			want: "curl --http3 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "unknown version",
+			opts: []Option{WithHTTPVersion(HTTPVersion2), WithHTTPVersion(HTTPVersion(42))},
+			want: "curl --http2 -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithInferredHTTPVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		proto string
+		want  string
+	}{
+		{
+			name:  "http/1.0",
+			url:   "https://localhost/test",
+			proto: "HTTP/1.0",
+			want:  "curl -0 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:  "http/1.1",
+			url:   "https://localhost/test",
+			proto: "HTTP/1.1",
+			want:  "curl --http1.1 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:  "http/2",
+			url:   "https://localhost/test",
+			proto: "HTTP/2.0",
+			want:  "curl --http2 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:  "h2c",
+			url:   "http://localhost/test",
+			proto: "HTTP/2.0",
+			want:  "curl --http2-prior-knowledge -X 'GET' 'http://localhost/test'",
+		},
+		{
+			name:  "http/3",
+			url:   "https://localhost/test",
+			proto: "HTTP/3.0",
+			want:  "curl --http3 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:  "malformed",
+			url:   "https://localhost/test",
+			proto: "SPDY",
+			want:  "curl -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			r.Proto = tt.proto
+
+			c, err := NewFromRequest(r, WithHTTPVersion(HTTPVersion3), WithInferredHTTPVersion())
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package curling
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToHurl returns a Hurl entry sending the same request as the cURL command,
+// without a response section, see [HurlFile] for assertions.
+//
+// JSON bodies are written as they are, other text bodies as multiline strings
+// and binary bodies as a reference to the body.bin file, as in the cURL command.
+// Placeholder values (see [WithPlaceholders]) are replaced with Hurl variables.
+// The URL fragment is left out, since it is not sent.
+func (c *Command) ToHurl() string {
+	var b strings.Builder
+
+	if c.inlineWarnings {
+		for _, w := range c.warnings {
+			fmt.Fprintf(&b, "# WARNING: %s\n", w.Message)
+		}
+	}
+
+	u := *c.request.url
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	fmt.Fprintf(&b, "%s %s\n", c.request.method, c.hurlTemplate(u.String()))
+
+	for _, field := range c.request.headerFields() {
+		fmt.Fprintf(&b, "%s: %s\n", field.key, hurlEscape(c.hurlTemplate(field.value)))
+	}
+
+	if c.request.hasBody {
+		b.WriteString(c.hurlBody())
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// hurlBody returns the request body in the Hurl syntax.
+func (c *Command) hurlBody() string {
+	body := c.request.body
+
+	switch {
+	case !isText(body):
+		return "file," + binaryBodyFileName + ";\n"
+	case c.bodyKind == BodyKindJSON && json.Valid(body):
+		return c.hurlTemplate(string(body)) + "\n"
+	default:
+		text := strings.TrimSuffix(c.hurlTemplate(string(body)), "\n")
+		return "```\n" + text + "\n```\n"
+	}
+}
+
+// hurlTemplate replaces the placeholder values found in s with Hurl variables.
+func (c *Command) hurlTemplate(s string) string {
+	for _, p := range c.placeholders {
+		s = strings.ReplaceAll(s, p.value, "{{"+p.name+"}}")
+	}
+
+	return s
+}
+
+// hurlEscape escapes the characters that Hurl interprets in header values.
+func hurlEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `#`, `\#`).Replace(s)
+}
+
+// HurlFile returns a Hurl file replaying each test, asserting the expected
+// response status code, so that captured traffic can be run as integration
+// tests by the Hurl runner. A zero status accepts any status code.
+func HurlFile(tests []SmokeTest) string {
+	entries := make([]string, 0, len(tests))
+
+	for _, test := range tests {
+		status := "*"
+		if test.Status != 0 {
+			status = fmt.Sprint(test.Status)
+		}
+
+		entries = append(entries, test.Command.ToHurl()+"\nHTTP "+status)
+	}
+
+	if len(entries) == 0 {
+		return ""
+	}
+
+	return strings.Join(entries, "\n\n") + "\n"
+}
@@ -0,0 +1,124 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_ToHurl(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme:   "https",
+		Host:     "localhost",
+		Path:     "test",
+		RawQuery: "q=1",
+		Fragment: "top",
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		header http.Header
+		body   string
+		opts   []Option
+		want   string
+	}{
+		{
+			name:   "without body",
+			method: http.MethodGet,
+			header: http.Header{"Accept": {"*/*"}, "X-Tag": {`#1 \ #2`}},
+			want: "GET https://localhost/test?q=1\n" +
+				"Accept: */*\n" +
+				`X-Tag: \#1 \\ \#2`,
+		},
+		{
+			name:   "json body",
+			method: http.MethodPost,
+			header: http.Header{"Content-Type": {"application/json"}},
+			body:   `{"key":"value"}`,
+			want: "POST https://localhost/test?q=1\n" +
+				"Content-Type: application/json\n" +
+				`{"key":"value"}`,
+		},
+		{
+			name:   "text body",
+			method: http.MethodPut,
+			header: http.Header{"Content-Type": {"text/plain"}},
+			body:   "line 1\nline 2\n",
+			want: "PUT https://localhost/test?q=1\n" +
+				"Content-Type: text/plain\n" +
+				"```\nline 1\nline 2\n```",
+		},
+		{
+			name:   "binary body",
+			method: http.MethodPost,
+			body:   "\x00\x01",
+			want: "POST https://localhost/test?q=1\n" +
+				"file,body.bin;",
+		},
+		{
+			name:   "placeholders and warnings",
+			method: http.MethodPost,
+			header: http.Header{"Authorization": {"Bearer s3cr3t"}},
+			body:   "\x00",
+			opts:   []Option{WithPlaceholders(map[string]string{"TOKEN": "s3cr3t"}), WithInlineWarnings()},
+			want: "# WARNING: body contains binary data that may be altered by the shell\n" +
+				"POST https://localhost/test?q=1\n" +
+				"Authorization: Bearer {{TOKEN}}\n" +
+				"file,body.bin;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{Method: tt.method, URL: testUrl, Header: tt.header}
+			if tt.body != "" {
+				r.Body = readCloser(tt.body)
+			}
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.ToHurl(); got != tt.want {
+				t.Errorf("ToHurl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHurlFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		tests func(t *testing.T) []SmokeTest
+		want  string
+	}{
+		{
+			name: "no tests",
+			tests: func(t *testing.T) []SmokeTest {
+				return nil
+			},
+			want: "",
+		},
+		{
+			name: "status assertions",
+			tests: func(t *testing.T) []SmokeTest {
+				return []SmokeTest{
+					{Command: mustNewFromRequest(t, http.MethodGet, "https://localhost/a"), Status: http.StatusOK},
+					{Command: mustNewFromRequest(t, http.MethodDelete, "https://localhost/b")},
+				}
+			},
+			want: "GET https://localhost/a\n" +
+				"HTTP 200\n\n" +
+				"DELETE https://localhost/b\n" +
+				"HTTP *\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HurlFile(tt.tests(t)); got != tt.want {
+				t.Errorf("HurlFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
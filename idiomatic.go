@@ -0,0 +1,131 @@
+package curling
+
+import (
+	"net/http"
+	"strings"
+)
+
+// idiomatic reports whether the idiomatic flags are enabled, which [WithFaithful] prevents.
+func (c *Command) idiomatic() bool {
+	return c.idiomaticFlags && !c.faithful
+}
+
+// applyIdiomaticFlags adapts the command flags to the request semantics.
+func (c *Command) applyIdiomaticFlags() {
+	if !c.idiomatic() {
+		return
+	}
+
+	if c.compressed {
+		if reason, ok := c.compressionConflict(); ok {
+			c.compressedSuppressed = true
+			c.warn(WarningCompressedSuppressed, "--compressed was left out, %s", reason)
+		}
+	}
+}
+
+// headerFlag returns the option rendering the header h instead of -H, --header,
+// with its argument, if any. Faithful commands keep every header as is.
+func (c *Command) headerFlag(h headerField) (flag, string, bool) {
+	if c.faithful {
+		return flag{}, "", false
+	}
+
+	switch {
+	case (c.userAgentFlag || c.idiomaticHeaderFlags()) && strings.EqualFold(h.key, "User-Agent"):
+		return flagUserAgent, h.value, true
+	case (c.refererFlag || c.idiomaticHeaderFlags()) && strings.EqualFold(h.key, "Referer"):
+		// With ;auto, cURL keeps the initial referer and sets it on redirects.
+		if c.location {
+			return flagReferer, h.value + ";auto", true
+		}
+
+		return flagReferer, h.value, true
+	case strings.EqualFold(h.key, "Authorization"):
+		if token, ok := c.bearerToken(h.value); ok {
+			return flagOAuth2Bearer, token, true
+		}
+	case c.idiomaticHeaderFlags() && strings.EqualFold(h.key, "Range"):
+		if r, ok := byteRange(h.value); ok {
+			return flagRange, r, true
+		}
+	}
+
+	return flag{}, "", false
+}
+
+// idiomaticHeaderFlags reports whether WithIdiomaticFlags renders headers with
+// their dedicated flags, which it does with OutputVersion2 only.
+func (c *Command) idiomaticHeaderFlags() bool {
+	return c.idiomaticFlags && c.version() >= OutputVersion2
+}
+
+// bearerToken returns the token of a bearer Authorization header value,
+// rendered with --oauth2-bearer when enabled with WithOAuth2BearerFlag.
+func (c *Command) bearerToken(authorization string) (string, bool) {
+	if !c.oauth2BearerFlag || c.faithful {
+		return "", false
+	}
+
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	token = strings.TrimSpace(token)
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" || strings.ContainsAny(token, " \t") {
+		return "", false
+	}
+
+	return token, true
+}
+
+// byteRange returns the ranges of a Range header value in bytes, such as
+// bytes=0-499, as the argument of -r, --range, reporting false for other units
+// and for values cURL can't express.
+func byteRange(value string) (string, bool) {
+	unit, ranges, found := strings.Cut(value, "=")
+	if !found || !strings.EqualFold(strings.TrimSpace(unit), "bytes") {
+		return "", false
+	}
+
+	ranges = strings.ReplaceAll(ranges, " ", "")
+	if ranges == "" || strings.Trim(ranges, "0123456789-,") != "" {
+		return "", false
+	}
+
+	return ranges, true
+}
+
+// compressionConflict returns why --compressed conflicts with the request, if it does.
+func (c *Command) compressionConflict() (string, bool) {
+	switch {
+	case c.request.method == http.MethodHead:
+		return "HEAD responses have no body to decompress", true
+	case c.request.header.Get("Range") != "":
+		return "decompressing a range response breaks its offsets", true
+	case acceptsIdentityOnly(c.request.header.Values("Accept-Encoding")):
+		return "the request accepts the identity encoding only", true
+	}
+
+	return "", false
+}
+
+// acceptsIdentityOnly reports whether the Accept-Encoding values list
+// the identity encoding and no other.
+func acceptsIdentityOnly(values []string) bool {
+	found := false
+
+	for _, value := range values {
+		for _, coding := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(coding, ";")
+			name = strings.TrimSpace(name)
+
+			switch {
+			case name == "":
+			case strings.EqualFold(name, "identity"):
+				found = true
+			default:
+				return false
+			}
+		}
+	}
+
+	return found
+}
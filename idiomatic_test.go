@@ -0,0 +1,367 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"testing"
+)
+
+func TestCommand_String_idiomaticCompressed(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		header       http.Header
+		opts         []Option
+		want         string
+		wantWarnings []Warning
+	}{
+		{
+			name:   "no conflict",
+			method: http.MethodGet,
+			header: http.Header{"Accept-Encoding": {"gzip, identity"}},
+			opts:   []Option{WithIdiomaticFlags()},
+			want:   "curl --compressed -X 'GET' 'https://localhost/test' -H 'Accept-Encoding: gzip, identity'",
+		},
+		{
+			name:   "head",
+			method: http.MethodHead,
+			opts:   []Option{WithIdiomaticFlags()},
+			want:   "curl -X 'HEAD' 'https://localhost/test'",
+			wantWarnings: []Warning{
+				{Code: WarningCompressedSuppressed, Message: "--compressed was left out, HEAD responses have no body to decompress"},
+			},
+		},
+		{
+			name:   "range",
+			method: http.MethodGet,
+			header: http.Header{"Range": {"bytes=100-199"}},
+			opts:   []Option{WithIdiomaticFlags()},
+			want:   "curl -X 'GET' 'https://localhost/test' -H 'Range: bytes=100-199'",
+			wantWarnings: []Warning{
+				{Code: WarningCompressedSuppressed, Message: "--compressed was left out, decompressing a range response breaks its offsets"},
+			},
+		},
+		{
+			name:   "identity only",
+			method: http.MethodGet,
+			header: http.Header{"Accept-Encoding": {"Identity;q=1"}},
+			opts:   []Option{WithIdiomaticFlags()},
+			want:   "curl -X 'GET' 'https://localhost/test' -H 'Accept-Encoding: Identity;q=1'",
+			wantWarnings: []Warning{
+				{Code: WarningCompressedSuppressed, Message: "--compressed was left out, the request accepts the identity encoding only"},
+			},
+		},
+		{
+			name:   "without idiomatic flags",
+			method: http.MethodHead,
+			want:   "curl --compressed -X 'HEAD' 'https://localhost/test'",
+		},
+		{
+			name:   "faithful",
+			method: http.MethodHead,
+			opts:   []Option{WithIdiomaticFlags(), WithFaithful()},
+			want:   "curl --compressed --path-as-is -g -X 'HEAD' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(tt.method, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header = tt.header
+
+			c, err := NewFromRequest(r, append([]Option{WithCompression()}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+			if got := c.Validate(); !cmp.Equal(got, tt.wantWarnings) {
+				t.Errorf("Validate() diff = %v", cmp.Diff(got, tt.wantWarnings))
+			}
+		})
+	}
+}
+
+func Test_acceptsIdentityOnly(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   bool
+	}{
+		{name: "none", want: false},
+		{name: "identity", values: []string{"identity"}, want: true},
+		{name: "identity with weight", values: []string{" identity ; q=0.5 ,"}, want: true},
+		{name: "other coding", values: []string{"identity", "gzip"}, want: false},
+		{name: "wildcard", values: []string{"*"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := acceptsIdentityOnly(tt.values); got != tt.want {
+				t.Errorf("acceptsIdentityOnly() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithUserAgentFlag(t *testing.T) {
+	header := http.Header{"Accept": {"*/*"}, "User-Agent": {"curling-test/1.0"}}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "header",
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -H 'User-Agent: curling-test/1.0'",
+		},
+		{
+			name: "user agent flag",
+			opts: []Option{WithUserAgentFlag()},
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -A 'curling-test/1.0'",
+		},
+		{
+			name: "long form",
+			opts: []Option{WithUserAgentFlag(), WithLongForm()},
+			want: "curl --request 'GET' 'https://localhost/test' --header 'Accept: */*' --user-agent 'curling-test/1.0'",
+		},
+		{
+			name: "idiomatic flags",
+			opts: []Option{WithIdiomaticFlags()},
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -H 'User-Agent: curling-test/1.0'",
+		},
+		{
+			name: "idiomatic flags with second version",
+			opts: []Option{WithIdiomaticFlags(), WithOutputVersion(OutputVersion2)},
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -A 'curling-test/1.0'",
+		},
+		{
+			name: "faithful",
+			opts: []Option{WithUserAgentFlag(), WithFaithful()},
+			want: "curl --path-as-is -g -X 'GET' 'https://localhost/test' -H 'Accept: */*' -H 'User-Agent: curling-test/1.0'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header = header
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRefererFlag(t *testing.T) {
+	header := http.Header{"Referer": {"https://localhost/home"}}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "header",
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Referer: https://localhost/home'",
+		},
+		{
+			name: "referer flag",
+			opts: []Option{WithRefererFlag()},
+			want: "curl -X 'GET' 'https://localhost/test' -e 'https://localhost/home'",
+		},
+		{
+			name: "follow redirects",
+			opts: []Option{WithRefererFlag(), WithFollowRedirects(), WithLongForm()},
+			want: "curl --location --request 'GET' 'https://localhost/test' --referer 'https://localhost/home;auto'",
+		},
+		{
+			name: "idiomatic flags",
+			opts: []Option{WithIdiomaticFlags()},
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Referer: https://localhost/home'",
+		},
+		{
+			name: "idiomatic flags with second version",
+			opts: []Option{WithIdiomaticFlags(), WithOutputVersion(OutputVersion2)},
+			want: "curl -X 'GET' 'https://localhost/test' -e 'https://localhost/home'",
+		},
+		{
+			name: "faithful",
+			opts: []Option{WithRefererFlag(), WithFaithful()},
+			want: "curl --path-as-is -g -X 'GET' 'https://localhost/test' -H 'Referer: https://localhost/home'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header = header
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithOAuth2BearerFlag(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		opts          []Option
+		want          string
+	}{
+		{
+			name:          "header",
+			authorization: "Bearer abc.def",
+			want:          "curl -X 'GET' 'https://localhost/test' -H 'Authorization: Bearer abc.def'",
+		},
+		{
+			name:          "oauth2 bearer flag",
+			authorization: "bearer abc.def",
+			opts:          []Option{WithOAuth2BearerFlag()},
+			want:          "curl -X 'GET' 'https://localhost/test' --oauth2-bearer 'abc.def'",
+		},
+		{
+			name:          "other scheme",
+			authorization: "Basic YWxpY2U6",
+			opts:          []Option{WithOAuth2BearerFlag()},
+			want:          "curl -X 'GET' 'https://localhost/test' -H 'Authorization: Basic YWxpY2U6'",
+		},
+		{
+			name:          "redacted header",
+			authorization: "Bearer abc.def",
+			opts:          []Option{WithOAuth2BearerFlag(), WithRedactedHeaders("Authorization")},
+			want:          "export BEARER_TOKEN='********'\ncurl -X 'GET' 'https://localhost/test' --oauth2-bearer \"${BEARER_TOKEN}\"",
+		},
+		{
+			name:          "minimal",
+			authorization: "Bearer abc.def",
+			opts:          []Option{WithOAuth2BearerFlag(), WithMinimal()},
+			want:          "export BEARER_TOKEN='********'\ncurl 'https://localhost/test' --oauth2-bearer \"${BEARER_TOKEN}\"",
+		},
+		{
+			name:          "faithful",
+			authorization: "Bearer abc.def",
+			opts:          []Option{WithOAuth2BearerFlag(), WithFaithful()},
+			want:          "curl --path-as-is -g -X 'GET' 'https://localhost/test' -H 'Authorization: Bearer abc.def'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header.Set("Authorization", tt.authorization)
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_String_idiomaticRange(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		opts  []Option
+		want  string
+	}{
+		{
+			name:  "single range",
+			value: "bytes=0-499",
+			opts:  []Option{WithIdiomaticFlags(), WithOutputVersion(OutputVersion2)},
+			want:  "curl -X 'GET' 'https://localhost/test' -r '0-499'",
+		},
+		{
+			name:  "multiple ranges",
+			value: "bytes=0-50, 100-150, -200",
+			opts:  []Option{WithIdiomaticFlags(), WithLongForm(), WithOutputVersion(OutputVersion2)},
+			want:  "curl --request 'GET' 'https://localhost/test' --range '0-50,100-150,-200'",
+		},
+		{
+			name:  "other unit",
+			value: "items=0-9",
+			opts:  []Option{WithIdiomaticFlags(), WithOutputVersion(OutputVersion2)},
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'Range: items=0-9'",
+		},
+		{
+			name:  "first version",
+			value: "bytes=0-499",
+			opts:  []Option{WithIdiomaticFlags()},
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'Range: bytes=0-499'",
+		},
+		{
+			name:  "without idiomatic flags",
+			value: "bytes=0-499",
+			want:  "curl -X 'GET' 'https://localhost/test' -H 'Range: bytes=0-499'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header.Set("Range", tt.value)
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_byteRange(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		wantOk bool
+	}{
+		{value: "bytes=0-499", want: "0-499", wantOk: true},
+		{value: "Bytes = 500-", want: "500-", wantOk: true},
+		{value: "bytes=0-1, 5-6", want: "0-1,5-6", wantOk: true},
+		{value: "bytes="},
+		{value: "bytes=a-b"},
+		{value: "0-499"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := byteRange(tt.value)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("byteRange() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
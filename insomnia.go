@@ -0,0 +1,159 @@
+package curling
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// insomniaExport is an Insomnia export file, in format 4.
+type insomniaExport struct {
+	Type      string             `json:"_type"`
+	Format    int                `json:"__export_format"`
+	Date      string             `json:"__export_date"`
+	Source    string             `json:"__export_source"`
+	Resources []insomniaResource `json:"resources"`
+}
+
+// insomniaResource is a workspace, environment, folder or request of an Insomnia export.
+type insomniaResource struct {
+	ID          string            `json:"_id"`
+	Type        string            `json:"_type"`
+	ParentID    *string           `json:"parentId"`
+	Name        string            `json:"name"`
+	Data        map[string]string `json:"data,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Method      string            `json:"method,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Headers     []HARNameValue    `json:"headers,omitempty"`
+	Body        *insomniaBody     `json:"body,omitempty"`
+}
+
+// insomniaBody is the body of an Insomnia request.
+type insomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// insomniaBaseURL is the folder environment variable holding the origin of its requests.
+const insomniaBaseURL = "base_url"
+
+// MarshalInsomnia returns the commands as an Insomnia export file, in format 4,
+// that can be imported in a workspace named after name.
+// Requests are grouped in a folder for each origin, whose environment holds the
+// origin as the base_url variable. The placeholder variables of the commands
+// (see [WithPlaceholders]) are declared in the base environment with empty values,
+// to be filled in Insomnia. The export date is the latest time the commands were built at.
+func (s CommandSet) MarshalInsomnia(name string) ([]byte, error) {
+	workspaceID := "wrk_curling"
+	environmentID := "env_curling"
+
+	export := insomniaExport{
+		Type:   "export",
+		Format: 4,
+		Source: "curling",
+		Resources: []insomniaResource{
+			{ID: workspaceID, Type: "workspace", Name: name},
+		},
+	}
+
+	variables := map[string]string{}
+	folders := map[string]string{}
+	var latest time.Time
+
+	var requests []insomniaResource
+	for i, c := range s {
+		if c.capturedAt.After(latest) {
+			latest = c.capturedAt
+		}
+
+		for _, p := range c.placeholders {
+			variables[p.name] = ""
+		}
+
+		origin := c.origin()
+		folderID, ok := folders[origin]
+		if !ok {
+			folderID = fmt.Sprintf("fld_%d", len(folders)+1)
+			folders[origin] = folderID
+			export.Resources = append(export.Resources, insomniaResource{
+				ID:          folderID,
+				Type:        "request_group",
+				ParentID:    &workspaceID,
+				Name:        c.request.url.Host,
+				Environment: map[string]string{insomniaBaseURL: c.insomniaTemplate(origin)},
+			})
+		}
+
+		requests = append(requests, c.insomniaRequest(fmt.Sprintf("req_%d", i+1), folderID))
+	}
+
+	export.Resources = slices.Insert(export.Resources, 1, insomniaResource{
+		ID:       environmentID,
+		Type:     "environment",
+		ParentID: &workspaceID,
+		Name:     "Base Environment",
+		Data:     variables,
+	})
+	export.Resources = append(export.Resources, requests...)
+
+	if !latest.IsZero() {
+		export.Date = latest.UTC().Format(time.RFC3339)
+	}
+
+	return json.Marshal(export)
+}
+
+// insomniaRequest returns the command as an Insomnia request in the folder parentID,
+// with the origin replaced by the base_url variable.
+func (c *Command) insomniaRequest(id, parentID string) insomniaResource {
+	u := *c.request.url
+	u.Scheme = ""
+	u.Host = ""
+	u.User = nil
+
+	r := insomniaResource{
+		ID:       id,
+		Type:     "request",
+		ParentID: &parentID,
+		Name:     c.request.method + " " + c.request.url.Path,
+		Method:   c.request.method,
+		URL:      "{{ _." + insomniaBaseURL + " }}" + c.insomniaTemplate(u.String()),
+	}
+
+	keys := make([]string, 0, len(c.request.header))
+	for key := range c.request.header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range c.request.header[key] {
+			r.Headers = append(r.Headers, HARNameValue{
+				Name:  http.CanonicalHeaderKey(key),
+				Value: c.insomniaTemplate(value),
+			})
+		}
+	}
+
+	if c.request.hasBody {
+		r.Body = &insomniaBody{
+			MimeType: c.request.header.Get("Content-Type"),
+			Text:     c.insomniaTemplate(string(c.request.body)),
+		}
+	}
+
+	return r
+}
+
+// insomniaTemplate replaces the placeholder values found in s with Insomnia template tags.
+func (c *Command) insomniaTemplate(s string) string {
+	for _, p := range c.placeholders {
+		s = strings.ReplaceAll(s, p.value, "{{ _."+p.name+" }}")
+	}
+
+	return s
+}
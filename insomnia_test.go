@@ -0,0 +1,90 @@
+package curling
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommandSet_MarshalInsomnia(t *testing.T) {
+	clock := WithClock(func() time.Time {
+		return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	})
+
+	u, err := url.Parse("https://api.example.com/users?page=1")
+	if err != nil {
+		t.Fatalf("parsing url: %v", err)
+	}
+
+	post, err := NewFromRequest(&http.Request{
+		Method: http.MethodPost,
+		URL:    u,
+		Header: http.Header{
+			"Content-Type":  {"application/json"},
+			"Authorization": {"Bearer s3cr3t"},
+		},
+		Body: readCloser(`{"token":"s3cr3t"}`),
+	}, clock, WithPlaceholders(map[string]string{"TOKEN": "s3cr3t"}))
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	set := CommandSet{
+		post,
+		mustNewFromRequest(t, http.MethodGet, "https://localhost:8080/health", clock),
+		mustNewFromRequest(t, http.MethodGet, "https://api.example.com/users/42", clock),
+	}
+
+	got, err := set.MarshalInsomnia("session")
+	if err != nil {
+		t.Fatalf("MarshalInsomnia() error = %v", err)
+	}
+
+	want := `{
+  "_type": "export",
+  "__export_format": 4,
+  "__export_date": "2026-01-02T03:04:05Z",
+  "__export_source": "curling",
+  "resources": [
+    {"_id": "wrk_curling", "_type": "workspace", "parentId": null, "name": "session"},
+    {"_id": "env_curling", "_type": "environment", "parentId": "wrk_curling", "name": "Base Environment", "data": {"TOKEN": ""}},
+    {"_id": "fld_1", "_type": "request_group", "parentId": "wrk_curling", "name": "api.example.com", "environment": {"base_url": "https://api.example.com"}},
+    {"_id": "fld_2", "_type": "request_group", "parentId": "wrk_curling", "name": "localhost:8080", "environment": {"base_url": "https://localhost:8080"}},
+    {
+      "_id": "req_1", "_type": "request", "parentId": "fld_1", "name": "POST /users", "method": "POST",
+      "url": "{{ _.base_url }}/users?page=1",
+      "headers": [{"name": "Authorization", "value": "Bearer {{ _.TOKEN }}"}, {"name": "Content-Type", "value": "application/json"}],
+      "body": {"mimeType": "application/json", "text": "{\"token\":\"{{ _.TOKEN }}\"}"}
+    },
+    {"_id": "req_2", "_type": "request", "parentId": "fld_2", "name": "GET /health", "method": "GET", "url": "{{ _.base_url }}/health"},
+    {"_id": "req_3", "_type": "request", "parentId": "fld_1", "name": "GET /users/42", "method": "GET", "url": "{{ _.base_url }}/users/42"}
+  ]
+}`
+
+	var gotJSON, wantJSON any
+	if err := json.Unmarshal(got, &gotJSON); err != nil {
+		t.Fatalf("unmarshaling export: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantJSON); err != nil {
+		t.Fatalf("unmarshaling want: %v", err)
+	}
+
+	if !cmp.Equal(gotJSON, wantJSON) {
+		t.Errorf("MarshalInsomnia() diff = %v", cmp.Diff(gotJSON, wantJSON))
+	}
+}
+
+func TestCommandSet_MarshalInsomnia_empty(t *testing.T) {
+	got, err := CommandSet(nil).MarshalInsomnia("empty")
+	if err != nil {
+		t.Fatalf("MarshalInsomnia() error = %v", err)
+	}
+
+	if !strings.Contains(string(got), `"__export_date":""`) {
+		t.Errorf("MarshalInsomnia() = %s, want an empty export date", got)
+	}
+}
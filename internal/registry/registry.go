@@ -0,0 +1,53 @@
+// Package registry holds the output formats registered by name, shared by
+// the curling package, which renders them, and the dialect package, which
+// publishes them, without an import cycle between the two.
+package registry
+
+import (
+	"slices"
+	"sync"
+)
+
+var (
+	mu      sync.RWMutex
+	entries = make(map[string]any)
+)
+
+// Register stores v under name.
+// It reports false when name is already taken, leaving the stored value unchanged.
+func Register(name string, v any) bool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := entries[name]; ok {
+		return false
+	}
+
+	entries[name] = v
+
+	return true
+}
+
+// Lookup returns the value stored under name and reports whether there is one.
+func Lookup(name string) (any, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	v, ok := entries[name]
+
+	return v, ok
+}
+
+// Names returns the sorted names of the stored values.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,79 @@
+package curling
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Issues returns the non-fatal errors met while building the command, such as
+// a body that can't be decoded, an invalid header or cookie or a proxy that can't be
+// resolved, so that callers can decide whether to trust the output. Use [errors.Join]
+// to handle them as a single error.
+// An empty result means the command was built without issues.
+func (c *Command) Issues() []error {
+	if len(c.issues) == 0 {
+		return nil
+	}
+
+	return slices.Clone(c.issues)
+}
+
+// issue records a non-fatal error met while building the command.
+func (c *Command) issue(err error) {
+	c.issues = append(c.issues, err)
+}
+
+// withIssue returns an option recording err, met before the command is built.
+func withIssue(err error) Option {
+	return func(curling *Command) {
+		curling.issue(err)
+	}
+}
+
+// checkHeaders records an issue for each header that HTTP clients refuse to
+// send, because its name is not a valid token or its value contains control
+// characters. The headers are kept, as the request carries them.
+func (c *Command) checkHeaders() {
+	keys := make([]string, 0, len(c.request.header))
+	for key := range c.request.header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if !isToken(key) {
+			c.issue(fmt.Errorf("header %q has an invalid field name", key))
+			continue
+		}
+
+		if slices.ContainsFunc(c.request.header[key], func(value string) bool {
+			return strings.ContainsFunc(value, isControl)
+		}) {
+			c.issue(fmt.Errorf("header %q has an invalid field value", key))
+		}
+	}
+}
+
+// isToken reports whether s is a valid header field name.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || strings.IndexByte("!#$%&'*+-.^_`|~", b) >= 0 {
+			continue
+		}
+		return false
+	}
+
+	return true
+}
+
+// isControl reports whether r is a control character that can't appear in a
+// header field value: every ASCII control character but the horizontal tab.
+func isControl(r rune) bool {
+	return r < ' ' && r != '\t' || r == 0x7f
+}
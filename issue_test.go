@@ -0,0 +1,85 @@
+package curling
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_Issues(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		body   string
+		opts   []Option
+		want   []string
+	}{
+		{
+			name:   "no issues",
+			header: http.Header{"Accept": {"*/*"}},
+		},
+		{
+			name: "invalid headers",
+			header: http.Header{
+				"Bad Name": {"value"},
+				"X-Note":   {"line1\nline2"},
+				"X-Tab":    {"a\tb"},
+			},
+			want: []string{
+				`header "Bad Name" has an invalid field name`,
+				`header "X-Note" has an invalid field value`,
+			},
+		},
+		{
+			name:   "invalid cookie",
+			header: http.Header{"Cookie": {"session=abc; bad name=1"}},
+			want:   []string{`cookie "bad name" has an invalid name`},
+		},
+		{
+			name:   "undecodable body",
+			header: http.Header{"Content-Encoding": {"gzip"}},
+			body:   "not gzip",
+			opts:   []Option{WithDecodedBody()},
+			want:   []string{"decoding gzip body: unexpected EOF"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewWithBody(t, http.MethodPost, "https://localhost/test", tt.header, tt.body)
+			c, err := c.With(tt.opts...)
+			if err != nil {
+				t.Fatalf("With() error = %v", err)
+			}
+
+			var got []string
+			for _, err := range c.Issues() {
+				got = append(got, err.Error())
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Issues() diff = %s", diff)
+			}
+		})
+	}
+}
+
+func TestNewFromRequestWithClient_proxyIssue(t *testing.T) {
+	errProxy := errors.New("no proxy")
+	client := &http.Client{Transport: &http.Transport{
+		Proxy: func(r *http.Request) (*url.URL, error) {
+			return nil, errProxy
+		},
+	}}
+
+	r, _ := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	c, err := NewFromRequestWithClient(r, client)
+	if err != nil {
+		t.Fatalf("NewFromRequestWithClient() error = %v", err)
+	}
+
+	if err := errors.Join(c.Issues()...); !errors.Is(err, errProxy) {
+		t.Errorf("Issues() = %v, want %v", err, errProxy)
+	}
+}
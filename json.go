@@ -0,0 +1,33 @@
+package curling
+
+import "mime"
+
+// jsonContentType is the content type set by the cURL --json option, along with the Accept header.
+const jsonContentType = "application/json"
+
+// usesJSONFlag reports whether the body is rendered with the --json option:
+// the option is enabled, the body is text and its declared media type is JSON.
+func (c *Command) usesJSONFlag() bool {
+	if !c.jsonFlag || c.faithful || c.bodyToFile || !c.request.hasBody || !isText(c.request.body) {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.request.header.Get("Content-Type"))
+
+	return err == nil && mediaType == jsonContentType
+}
+
+// jsonFlagHeaderFields returns fields without the Content-Type and Accept headers
+// that the --json option sets with the same value.
+// Headers with other values are kept, so they override the ones set by cURL.
+func jsonFlagHeaderFields(fields []headerField) []headerField {
+	kept := make([]headerField, 0, len(fields))
+	for _, field := range fields {
+		if (field.key == "Content-Type" || field.key == "Accept") && field.value == jsonContentType {
+			continue
+		}
+		kept = append(kept, field)
+	}
+
+	return kept
+}
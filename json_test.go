@@ -0,0 +1,62 @@
+package curling
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithJSONFlag(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		body   string
+		opts   []Option
+		want   string
+	}{
+		{
+			name: "json body",
+			header: http.Header{
+				"Accept":       {"application/json"},
+				"Content-Type": {"application/json"},
+				"X-Key":        {"1"},
+			},
+			body: `{"a":"b"}`,
+			want: `curl -X 'POST' 'https://localhost/test' -H 'X-Key: 1' --json '{"a":"b"}'`,
+		},
+		{
+			name: "json body with other header values",
+			header: http.Header{
+				"Accept":       {"*/*"},
+				"Content-Type": {"application/json; charset=utf-8"},
+			},
+			body: `{"a":"b"}`,
+			want: `curl -X 'POST' 'https://localhost/test' -H 'Accept: */*' -H 'Content-Type: application/json; charset=utf-8' --json '{"a":"b"}'`,
+		},
+		{
+			name:   "vendor json type",
+			header: http.Header{"Content-Type": {"application/vnd.api+json"}},
+			body:   `{"a":"b"}`,
+			want:   `curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/vnd.api+json' -d '{"a":"b"}'`,
+		},
+		{
+			name:   "faithful",
+			header: http.Header{"Content-Type": {"application/json"}},
+			body:   `{}`,
+			opts:   []Option{WithFaithful()},
+			want:   `curl --path-as-is -g -X 'POST' 'https://localhost/test' -H 'Content-Type: application/json' -H 'Content-Length: 2' --data-binary '{}'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromParts(http.MethodPost, "https://localhost/test", tt.header, strings.NewReader(tt.body), append(tt.opts, WithJSONFlag())...)
+			if err != nil {
+				t.Fatalf("NewFromParts() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
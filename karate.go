@@ -0,0 +1,60 @@
+package curling
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToKarate returns a Karate feature file with a single scenario, named after name,
+// sending the requests in order, for teams whose integration tooling isn't shell-based.
+// JSON bodies are written as they are, other text bodies as strings and binary
+// bodies are read from the body.bin file, as in the cURL command.
+func (s CommandSet) ToKarate(name string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Feature: %s\n\n", name)
+	fmt.Fprintf(&b, "  Scenario: %s\n", name)
+
+	for i, c := range s {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(c.karateSteps())
+	}
+
+	return b.String()
+}
+
+// karateSteps returns the Karate steps sending the request.
+func (c *Command) karateSteps() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "    Given url %s\n", karateQuote(c.request.url.String()))
+
+	for _, field := range c.request.headerFields() {
+		fmt.Fprintf(&b, "    And header %s = %s\n", field.key, karateQuote(field.value))
+	}
+
+	if c.request.hasBody {
+		body := c.request.body
+		switch {
+		case !isText(body):
+			fmt.Fprintf(&b, "    And request read(%s)\n", karateQuote(binaryBodyFileName))
+		case c.bodyKind == BodyKindJSON && json.Valid(body) && !strings.Contains(string(body), "\n"):
+			fmt.Fprintf(&b, "    And request %s\n", body)
+		default:
+			fmt.Fprintf(&b, "    And request %s\n", karateQuote(string(body)))
+		}
+	}
+
+	fmt.Fprintf(&b, "    When method %s\n", strings.ToLower(c.request.method))
+
+	return b.String()
+}
+
+// karateQuote returns s as a JavaScript single quoted string literal.
+func karateQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return "'" + r.Replace(s) + "'"
+}
@@ -0,0 +1,75 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// mustNewWithBody returns a new command sending body or stops the test on error.
+func mustNewWithBody(t *testing.T, method, rawurl string, header http.Header, body string) *Command {
+	t.Helper()
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		t.Fatalf("parsing url: %v", err)
+	}
+
+	c, err := NewFromRequest(&http.Request{Method: method, URL: u, Header: header, Body: readCloser(body)})
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	return c
+}
+
+func TestCommandSet_ToKarate(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(t *testing.T) CommandSet
+		want string
+	}{
+		{
+			name: "empty set",
+			set: func(t *testing.T) CommandSet {
+				return nil
+			},
+			want: "Feature: session\n\n" +
+				"  Scenario: session\n",
+		},
+		{
+			name: "requests",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{
+					mustNewFromRequest(t, http.MethodGet, "https://localhost/a?q=1"),
+					mustNewWithBody(t, http.MethodPost, "https://localhost/b",
+						http.Header{"Content-Type": {"application/json"}, "X-Quote": {"it's"}}, `{"key":"value"}`),
+					mustNewWithBody(t, http.MethodPut, "https://localhost/c", nil, "line 1\nline 2"),
+					mustNewWithBody(t, http.MethodPut, "https://localhost/d", nil, "\x00"),
+				}
+			},
+			want: "Feature: session\n\n" +
+				"  Scenario: session\n" +
+				"    Given url 'https://localhost/a?q=1'\n" +
+				"    When method get\n\n" +
+				"    Given url 'https://localhost/b'\n" +
+				"    And header Content-Type = 'application/json'\n" +
+				"    And header X-Quote = 'it\\'s'\n" +
+				"    And request {\"key\":\"value\"}\n" +
+				"    When method post\n\n" +
+				"    Given url 'https://localhost/c'\n" +
+				"    And request 'line 1\\nline 2'\n" +
+				"    When method put\n\n" +
+				"    Given url 'https://localhost/d'\n" +
+				"    And request read('body.bin')\n" +
+				"    When method put\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set(t).ToKarate("session"); got != tt.want {
+				t.Errorf("ToKarate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package curling
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// managedHeaders lists the headers cURL sets on its own from the request it sends,
+// left out by [WithStripManagedHeaders].
+var managedHeaders = []string{
+	"Connection",
+	"Content-Length",
+	"Expect",
+	"Keep-Alive",
+	"Proxy-Connection",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// WithStripManagedHeaders leaves out the headers cURL manages on its own, whose
+// recorded values can break the replay: Connection, Content-Length, Expect,
+// Keep-Alive, Proxy-Connection, Transfer-Encoding and Upgrade, as well as
+// Accept-Encoding when --compressed is rendered. The removed headers are listed
+// in a comment above the command and reported by [Command.Validate].
+// It is ignored by [WithFaithful].
+func WithStripManagedHeaders() Option {
+	return func(curling *Command) {
+		curling.stripManagedHeaders = true
+	}
+}
+
+// stripManaged leaves out the headers managed by cURL, when enabled with WithStripManagedHeaders.
+func (c *Command) stripManaged() {
+	if !c.stripManagedHeaders || c.faithful {
+		return
+	}
+
+	keys := managedHeaders
+	if c.compressed && !c.compressedSuppressed {
+		keys = append(slices.Clip(keys), "Accept-Encoding")
+	}
+
+	for _, key := range keys {
+		if len(c.request.header.Values(key)) == 0 {
+			continue
+		}
+
+		c.request.header.Del(key)
+		c.strippedHeaders = append(c.strippedHeaders, http.CanonicalHeaderKey(key))
+	}
+
+	if len(c.strippedHeaders) == 0 {
+		return
+	}
+
+	slices.Sort(c.strippedHeaders)
+	c.warn(WarningManagedHeaders, "headers %s were left out, cURL manages them", strings.Join(c.strippedHeaders, ", "))
+}
+
+// strippedHeadersComment returns the comment line listing the headers left out
+// by WithStripManagedHeaders, empty when there are none.
+func (c *Command) strippedHeadersComment() string {
+	if len(c.strippedHeaders) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s Removed headers managed by cURL: %s\n", c.commentPrefix(), strings.Join(c.strippedHeaders, ", "))
+}
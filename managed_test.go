@@ -0,0 +1,81 @@
+package curling
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithStripManagedHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      http.Header
+		opts        []Option
+		want        string
+		wantWarning bool
+	}{
+		{
+			name: "managed headers",
+			header: http.Header{
+				"Connection":     {"keep-alive"},
+				"Content-Length": {"4"},
+				"Expect":         {"100-continue"},
+				"X-Request-Id":   {"42"},
+			},
+			opts: []Option{WithStripManagedHeaders()},
+			want: "# Removed headers managed by cURL: Connection, Content-Length, Expect\n" +
+				"curl -X 'POST' 'https://localhost/test' -H 'X-Request-Id: 42' -d 'test'",
+			wantWarning: true,
+		},
+		{
+			name:        "accept encoding with compression",
+			header:      http.Header{"Accept-Encoding": {"gzip, br"}},
+			opts:        []Option{WithStripManagedHeaders(), WithCompression()},
+			want:        "# Removed headers managed by cURL: Accept-Encoding\ncurl --compressed -X 'POST' 'https://localhost/test' -d 'test'",
+			wantWarning: true,
+		},
+		{
+			name:   "accept encoding without compression",
+			header: http.Header{"Accept-Encoding": {"gzip, br"}},
+			opts:   []Option{WithStripManagedHeaders()},
+			want:   "curl -X 'POST' 'https://localhost/test' -H 'Accept-Encoding: gzip, br' -d 'test'",
+		},
+		{
+			name:   "without managed headers",
+			header: http.Header{"X-Request-Id": {"42"}},
+			opts:   []Option{WithStripManagedHeaders()},
+			want:   "curl -X 'POST' 'https://localhost/test' -H 'X-Request-Id: 42' -d 'test'",
+		},
+		{
+			name:   "disabled",
+			header: http.Header{"Connection": {"close"}},
+			want:   "curl -X 'POST' 'https://localhost/test' -H 'Connection: close' -d 'test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "https://localhost/test", strings.NewReader("test"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Header = tt.header
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			var got bool
+			for _, w := range c.Validate() {
+				got = got || w.Code == WarningManagedHeaders
+			}
+			if got != tt.wantWarning {
+				t.Errorf("Validate() has %s = %v, want %v", WarningManagedHeaders, got, tt.wantWarning)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package curling
+
+import "maps"
+
+// SetMeta attaches v to the command under key, so that integrations such as
+// middleware, recorders and exporters can carry correlation IDs, capture
+// timestamps or tenant info alongside the command.
+// A nil v removes the key. Metadata doesn't change the rendered command
+// and is copied to the variants returned by [Command.With].
+// SetMeta is safe for concurrent use.
+func (c *Command) SetMeta(key string, v any) {
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+
+	if v == nil {
+		delete(c.meta, key)
+		return
+	}
+
+	if c.meta == nil {
+		c.meta = make(map[string]any)
+	}
+
+	c.meta[key] = v
+}
+
+// Meta returns the value attached to the command under key with [Command.SetMeta],
+// or nil if there is none.
+// Meta is safe for concurrent use.
+func (c *Command) Meta(key string) any {
+	c.metaMu.RLock()
+	defer c.metaMu.RUnlock()
+
+	return c.meta[key]
+}
+
+// cloneMeta returns a copy of the metadata attached to the command.
+func (c *Command) cloneMeta() map[string]any {
+	c.metaMu.RLock()
+	defer c.metaMu.RUnlock()
+
+	return maps.Clone(c.meta)
+}
@@ -0,0 +1,72 @@
+package curling
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestCommand_Meta(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test")
+	want := c.String()
+
+	if got := c.Meta("request_id"); got != nil {
+		t.Errorf("Meta() = %v, want nil", got)
+	}
+
+	c.SetMeta("request_id", "abc")
+	c.SetMeta("tenant", 42)
+
+	if got := c.Meta("request_id"); got != "abc" {
+		t.Errorf("Meta() = %v, want %v", got, "abc")
+	}
+	if got := c.Meta("tenant"); got != 42 {
+		t.Errorf("Meta() = %v, want %v", got, 42)
+	}
+	if got := c.String(); got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+
+	c.SetMeta("tenant", nil)
+	if got := c.Meta("tenant"); got != nil {
+		t.Errorf("Meta() = %v, want nil", got)
+	}
+}
+
+func TestCommand_Meta_with(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test")
+	c.SetMeta("request_id", "abc")
+
+	variant, err := c.With(WithLongForm())
+	if err != nil {
+		t.Fatalf("With() error = %v", err)
+	}
+
+	if got := variant.Meta("request_id"); got != "abc" {
+		t.Errorf("Meta() = %v, want %v", got, "abc")
+	}
+
+	variant.SetMeta("request_id", "def")
+	if got := c.Meta("request_id"); got != "abc" {
+		t.Errorf("Meta() = %v, want %v", got, "abc")
+	}
+}
+
+func TestCommand_Meta_concurrent(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.SetMeta("key", i)
+			_ = c.Meta("key")
+		}(i)
+	}
+	wg.Wait()
+
+	if c.Meta("key") == nil {
+		t.Errorf("Meta() = nil, want a value")
+	}
+}
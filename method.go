@@ -0,0 +1,29 @@
+package curling
+
+import "net/http"
+
+// bodyWithoutPayloadSemantics reports whether the request carries a body with
+// a method whose body has no defined semantics, GET and HEAD, such as the
+// search requests of Elasticsearch. With OutputVersion2 the body is sent with
+// --data-raw. The method is always set with -X, since -d alone would switch cURL to POST.
+func (c *Command) bodyWithoutPayloadSemantics() bool {
+	if !c.request.hasBody {
+		return false
+	}
+
+	return c.request.method == http.MethodGet || c.request.method == http.MethodHead
+}
+
+// validateMethodBody reports the requests carrying a body with GET or HEAD.
+func (c *Command) validateMethodBody() {
+	if !c.bodyWithoutPayloadSemantics() {
+		return
+	}
+
+	if c.request.method == http.MethodHead {
+		c.warn(WarningMethodBody, "HEAD request carries a body, cURL may wait with -X HEAD for a response body that never comes")
+		return
+	}
+
+	c.warn(WarningMethodBody, "GET request carries a body, which some servers and proxies ignore or reject")
+}
@@ -0,0 +1,130 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"testing"
+)
+
+func TestCommand_String_methodBody(t *testing.T) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	body := `{"query":{"match_all":{}}}`
+
+	tests := []struct {
+		name         string
+		method       string
+		opts         []Option
+		want         string
+		wantWarnings []Warning
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			opts:   []Option{WithOutputVersion(OutputVersion2)},
+			want:   `curl -X 'GET' 'https://localhost/_search' -H 'Content-Type: application/json' --data-raw '{"query":{"match_all":{}}}'`,
+			wantWarnings: []Warning{
+				{Code: WarningMethodBody, Message: "GET request carries a body, which some servers and proxies ignore or reject"},
+			},
+		},
+		{
+			name:   "get with first version",
+			method: http.MethodGet,
+			opts:   []Option{WithOutputVersion(OutputVersion1)},
+			want:   `curl -X 'GET' 'https://localhost/_search' -H 'Content-Type: application/json' -d '{"query":{"match_all":{}}}'`,
+			wantWarnings: []Warning{
+				{Code: WarningMethodBody, Message: "GET request carries a body, which some servers and proxies ignore or reject"},
+			},
+		},
+		{
+			name:   "get minimal",
+			method: http.MethodGet,
+			opts:   []Option{WithMinimal(), WithOutputVersion(OutputVersion2)},
+			want:   `curl -X 'GET' 'https://localhost/_search' -H 'Content-Type: application/json' --data-raw '{"query":{"match_all":{}}}'`,
+			wantWarnings: []Warning{
+				{Code: WarningMethodBody, Message: "GET request carries a body, which some servers and proxies ignore or reject"},
+			},
+		},
+		{
+			name:   "get faithful",
+			method: http.MethodGet,
+			opts:   []Option{WithFaithful()},
+			want:   `curl --path-as-is -g -X 'GET' 'https://localhost/_search' -H 'Content-Type: application/json' -H 'Content-Length: 26' --data-binary '{"query":{"match_all":{}}}'`,
+			wantWarnings: []Warning{
+				{Code: WarningMethodBody, Message: "GET request carries a body, which some servers and proxies ignore or reject"},
+			},
+		},
+		{
+			name:   "head",
+			method: http.MethodHead,
+			opts:   []Option{WithOutputVersion(OutputVersion2)},
+			want:   `curl -X 'HEAD' 'https://localhost/_search' -H 'Content-Type: application/json' --data-raw '{"query":{"match_all":{}}}'`,
+			wantWarnings: []Warning{
+				{Code: WarningMethodBody, Message: "HEAD request carries a body, cURL may wait with -X HEAD for a response body that never comes"},
+			},
+		},
+		{
+			name:   "post",
+			method: http.MethodPost,
+			want:   `curl -X 'POST' 'https://localhost/_search' -H 'Content-Type: application/json' -d '{"query":{"match_all":{}}}'`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewWithBody(t, tt.method, "https://localhost/_search", header, body)
+			c, err := c.With(tt.opts...)
+			if err != nil {
+				t.Fatalf("With() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			if diff := cmp.Diff(tt.wantWarnings, c.Validate()); diff != "" {
+				t.Errorf("Validate() diff = %s", diff)
+			}
+		})
+	}
+}
+
+func TestCommand_String_head(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "first version",
+			opts: []Option{WithOutputVersion(OutputVersion1)},
+			want: "curl -X 'HEAD' 'https://localhost/test'",
+		},
+		{
+			name: "short form",
+			want: "curl -I 'https://localhost/test'",
+		},
+		{
+			name: "long form",
+			opts: []Option{WithLongForm()},
+			want: "curl --head 'https://localhost/test'",
+		},
+		{
+			name: "flag grouping",
+			opts: []Option{WithFlagGrouping(), WithSilent()},
+			want: "curl 'https://localhost/test' -I -s",
+		},
+		{
+			name: "minimal",
+			opts: []Option{WithMinimal()},
+			want: "curl -I 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodHead, "https://localhost/test", append([]Option{WithOutputVersion(OutputVersion2)}, tt.opts...)...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
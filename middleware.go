@@ -0,0 +1,137 @@
+package curling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// defaultMaxCaptureSize is the number of body bytes read by Middleware without WithMaxCaptureSize.
+const defaultMaxCaptureSize = 1 << 20
+
+// contextKey is the type of the keys of the values stored by this package in a context.
+type contextKey struct{}
+
+// commandKey is the context key of the command stored by [Middleware].
+var commandKey contextKey
+
+// Middleware returns a handler that builds the cURL command of each inbound request
+// and makes it available to next through [FromContext].
+//
+// Up to 1 MiB of the request body, or the size set with [WithMaxCaptureSize],
+// is read to build the command, then restored ahead of the unread rest,
+// so next reads the whole body, or the same read error.
+// Server requests don't carry a scheme and a host in their URL: the host is
+// taken from the Host header and the scheme is https for TLS connections,
+// http otherwise. The options of the routes set with [OnHost] and [OnPath]
+// are applied to the requests they match. Requests rejected by the predicate
+// set with [WithPredicate] and requests whose command can't be built, such as
+// those whose body can't be read, are passed to next without a command.
+func Middleware(next http.Handler, opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts, ok := requestOptions(r, opts)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		out, snapshot, err := captureRequest(r, maxCaptureSize(opts))
+		if err != nil {
+			next.ServeHTTP(w, out)
+			return
+		}
+
+		snapshot.URL.Host = r.Host
+		snapshot.URL.Scheme = "http"
+		if r.TLS != nil {
+			snapshot.URL.Scheme = "https"
+		}
+
+		if request, err := parseRequest(snapshot); err == nil {
+			// The original size of a body captured partially is its declared length, if any.
+			if request.hasBody {
+				request.bodySize = max(request.bodySize, int(snapshot.ContentLength))
+			}
+
+			var c Command
+			if err := c.buildParsed(request, opts...); err == nil {
+				out = out.WithContext(context.WithValue(out.Context(), commandKey, &c))
+			}
+		}
+
+		next.ServeHTTP(w, out)
+	})
+}
+
+// WithMaxCaptureSize sets the number of request body bytes [Middleware] reads
+// to build the command, 1 MiB by default, so that large uploads aren't held
+// in memory. The command of a longer body renders the bytes read only, as
+// reported by [Command.Validate]. Non-positive sizes will be silently ignored.
+func WithMaxCaptureSize(size int) Option {
+	return func(curling *Command) {
+		if size <= 0 {
+			return
+		}
+
+		curling.maxCaptureSize = size
+	}
+}
+
+// maxCaptureSize returns the number of body bytes read by Middleware with opts.
+func maxCaptureSize(opts []Option) int {
+	var c Command
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.maxCaptureSize > 0 {
+		return c.maxCaptureSize
+	}
+
+	return defaultMaxCaptureSize
+}
+
+// captureRequest returns two shallow copies of r: one to pass on, whose body
+// reads the bytes captured from the body of r again before the unread rest,
+// and one to build the command from, with the first limit bytes only.
+// When the body is longer than limit, the second one keeps its declared length.
+// If captureRequest can't read the body, it returns an error along with the
+// copy to pass on, whose body reads the bytes read before the error again.
+func captureRequest(r *http.Request, limit int) (out, snapshot *http.Request, err error) {
+	out = r.Clone(r.Context())
+	snapshot = r.Clone(r.Context())
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return out, snapshot, nil
+	}
+
+	// One byte beyond limit tells a body of limit bytes from a longer one.
+	buf, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+
+	out.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+
+	if err != nil {
+		return out, nil, fmt.Errorf("reading bytes from request body: %w", err)
+	}
+
+	if len(buf) > limit {
+		buf = buf[:limit]
+		snapshot.ContentLength = max(r.ContentLength, int64(limit)+1)
+	} else {
+		snapshot.ContentLength = int64(len(buf))
+	}
+	snapshot.Body = io.NopCloser(bytes.NewReader(buf))
+
+	return out, snapshot, nil
+}
+
+// FromContext returns the command stored in ctx by [Middleware], if any.
+func FromContext(ctx context.Context) (*Command, bool) {
+	c, ok := ctx.Value(commandKey).(*Command)
+	return c, ok
+}
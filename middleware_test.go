@@ -0,0 +1,204 @@
+package curling
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		r        *http.Request
+		opts     []Option
+		want     string
+		wantBody string
+	}{
+		{
+			name: "without body",
+			r:    httptest.NewRequest(http.MethodGet, "/test?a=1", nil),
+			want: "curl -X 'GET' 'http://example.com/test?a=1'",
+		},
+		{
+			name:     "with body",
+			r:        httptest.NewRequest(http.MethodPost, "https://localhost/test", strings.NewReader("key=value")),
+			opts:     []Option{WithLongForm()},
+			want:     "curl --request 'POST' 'https://localhost/test' --data 'key=value'",
+			wantBody: "key=value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, gotBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c, ok := FromContext(r.Context())
+				if !ok {
+					t.Fatalf("FromContext() ok = false")
+				}
+				got = c.String()
+
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatalf("reading body: %v", err)
+				}
+				gotBody = string(b)
+			})
+
+			Middleware(next, tt.opts...).ServeHTTP(httptest.NewRecorder(), tt.r)
+
+			if got != tt.want {
+				t.Errorf("command = %v, want %v", got, tt.want)
+			}
+
+			if gotBody != tt.wantBody {
+				t.Errorf("body = %q, want %q", gotBody, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestMiddleware_maxCaptureSize(t *testing.T) {
+	body := strings.Repeat("a", 10) + strings.Repeat("b", 20)
+
+	tests := []struct {
+		name          string
+		contentLength int64
+		want          string
+	}{
+		{
+			name:          "declared length",
+			contentLength: int64(len(body)),
+			want:          "body truncated to 10 of 30 bytes",
+		},
+		{
+			name:          "unknown length",
+			contentLength: -1,
+			want:          "body truncated to 10 of 11 bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "https://localhost/test", strings.NewReader(body))
+			r.ContentLength = tt.contentLength
+
+			var c *Command
+			var gotBody string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c, _ = FromContext(r.Context())
+
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatalf("reading body: %v", err)
+				}
+				gotBody = string(b)
+			})
+
+			Middleware(next, WithMaxCaptureSize(10)).ServeHTTP(httptest.NewRecorder(), r)
+
+			if gotBody != body {
+				t.Errorf("body = %q, want %q", gotBody, body)
+			}
+
+			if c == nil {
+				t.Fatalf("FromContext() ok = false")
+			}
+
+			want := "curl -X 'POST' 'https://localhost/test' -d 'aaaaaaaaaa'"
+			if got := c.String(); got != want {
+				t.Errorf("command = %v, want %v", got, want)
+			}
+
+			var got string
+			for _, w := range c.Validate() {
+				if w.Code == WarningBodyTruncated {
+					got = w.Message
+				}
+			}
+			if got != tt.want {
+				t.Errorf("Validate() %s = %q, want %q", WarningBodyTruncated, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware_readError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "https://localhost/test", strings.NewReader("key=value"))
+	r.Body = http.MaxBytesReader(rec, r.Body, 4)
+
+	var gotBody string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, ok := FromContext(r.Context()); ok {
+			t.Errorf("FromContext() = %v, want no command", c)
+		}
+
+		b, err := io.ReadAll(r.Body)
+		gotBody = string(b)
+
+		var maxBytesErr *http.MaxBytesError
+		if !errors.As(err, &maxBytesErr) {
+			t.Fatalf("reading body error = %v, want %T", err, maxBytesErr)
+		}
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+	})
+
+	Middleware(next).ServeHTTP(rec, r)
+
+	if gotBody != "key=" {
+		t.Errorf("body = %q, want %q", gotBody, "key=")
+	}
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	if c, ok := FromContext(r.Context()); ok || c != nil {
+		t.Errorf("FromContext() = %v, %v, want nil, false", c, ok)
+	}
+}
+
+func TestMiddleware_predicate(t *testing.T) {
+	debugOnly := WithPredicate(func(r *http.Request) bool {
+		return r.Header.Get("X-Debug") != ""
+	})
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{
+			name:   "accepted",
+			header: http.Header{"X-Debug": {"1"}},
+			want:   true,
+		},
+		{
+			name: "rejected",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/test", nil)
+			r.Header = tt.header
+
+			var got bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, got = FromContext(r.Context())
+			})
+
+			Middleware(next, debugOnly).ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("FromContext() ok = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
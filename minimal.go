@@ -0,0 +1,48 @@
+package curling
+
+import "net/http"
+
+// minimalHeaders lists the headers kept by [WithMinimal], which change how the server
+// handles the request.
+var minimalHeaders = []string{
+	"Authorization",
+	"Content-Encoding",
+	"Content-Type",
+}
+
+// minimalAuthorizationVariable is the variable replacing the Authorization value with [WithMinimal].
+const minimalAuthorizationVariable = "AUTHORIZATION"
+
+// minimize drops the headers that don't change how the server handles the request
+// and replaces the Authorization value, or the token rendered with --oauth2-bearer,
+// with a placeholder.
+func (c *Command) minimize() {
+	header := http.Header{}
+	for _, key := range minimalHeaders {
+		if values := c.request.header.Values(key); len(values) > 0 {
+			header[key] = values
+		}
+	}
+	c.request.header = header
+
+	if authorization := header.Get("Authorization"); authorization != "" {
+		vars := map[string]string{minimalAuthorizationVariable: authorization}
+		if token, ok := c.bearerToken(authorization); ok {
+			vars = map[string]string{bearerTokenVariable: token}
+		}
+		for _, p := range c.placeholders {
+			vars[p.name] = p.value
+		}
+		c.placeholders = newPlaceholders(vars)
+	}
+}
+
+// impliedMethod reports whether cURL uses the request method without -X:
+// GET for requests without body and POST for requests with one.
+func (c *Command) impliedMethod() bool {
+	if c.request.hasBody {
+		return c.request.method == http.MethodPost
+	}
+
+	return c.request.method == http.MethodGet
+}
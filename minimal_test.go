@@ -0,0 +1,67 @@
+package curling
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithMinimal(t *testing.T) {
+	header := http.Header{
+		"Accept":          {"*/*"},
+		"Accept-Language": {"en-US"},
+		"Authorization":   {"Bearer abc"},
+		"Content-Type":    {"application/json"},
+		"User-Agent":      {"Mozilla/5.0"},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		opts   []Option
+		want   string
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			opts:   []Option{WithSilent(), WithFollowRedirects(), WithRequestTimeout(5)},
+			want: "export AUTHORIZATION='********'\n" +
+				"curl 'https://localhost/test' -H 'Authorization: '\"${AUTHORIZATION}\" -H 'Content-Type: application/json'",
+		},
+		{
+			name:   "post",
+			method: http.MethodPost,
+			body:   `{"a":1}`,
+			want: "export AUTHORIZATION='********'\n" +
+				"curl 'https://localhost/test' -H 'Authorization: '\"${AUTHORIZATION}\" -H 'Content-Type: application/json' -d '{\"a\":1}'",
+		},
+		{
+			name:   "put",
+			method: http.MethodPut,
+			body:   `{"a":1}`,
+			opts:   []Option{WithPlaceholders(map[string]string{"HOST": "localhost"})},
+			want: "export AUTHORIZATION='********'\n" +
+				"export HOST='********'\n" +
+				"curl -X 'PUT' 'https://'\"${HOST}\"'/test' -H 'Authorization: '\"${AUTHORIZATION}\" -H 'Content-Type: application/json' -d '{\"a\":1}'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+
+			c, err := NewFromParts(tt.method, "https://localhost/test", header, body, append(tt.opts, WithMinimal())...)
+			if err != nil {
+				t.Fatalf("NewFromParts() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
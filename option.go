@@ -1,5 +1,10 @@
 package curling
 
+import (
+	"net/http"
+	"time"
+)
+
 const (
 	lineContinuationDefault    = "\\"
 	lineContinuationWindows    = "^"
@@ -15,6 +20,19 @@ func WithFollowRedirects() Option {
 	}
 }
 
+// WithMaxRedirects enables the option -L, --location with --max-redirs, so that
+// cURL follows up to n redirects. Non-positive counts will be silently ignored.
+func WithMaxRedirects(n int) Option {
+	return func(curling *Command) {
+		if n <= 0 {
+			return
+		}
+
+		curling.location = true
+		curling.maxRedirects = n
+	}
+}
+
 // WithCompression enables the option --compressed.
 func WithCompression() Option {
 	return func(curling *Command) {
@@ -44,12 +62,61 @@ func WithSilent() Option {
 	}
 }
 
+// WithVerbose enables the option -v, --verbose, so that cURL prints the
+// connection details and the request and response headers.
+func WithVerbose() Option {
+	return func(curling *Command) {
+		curling.verbose = true
+	}
+}
+
+// WithIncludeResponseHeaders enables the option -i, --include, so that cURL
+// prints the response headers before the response body.
+func WithIncludeResponseHeaders() Option {
+	return func(curling *Command) {
+		curling.include = true
+	}
+}
+
+// A failMode sets how cURL fails on HTTP errors.
+type failMode int
+
+const (
+	// failNever lets cURL exit successfully on HTTP errors, the default.
+	failNever failMode = iota
+
+	// failFast enables the option -f, --fail.
+	failFast
+
+	// failWithBody enables the option --fail-with-body.
+	failWithBody
+)
+
+// WithFailFast enables the option -f, --fail, so that cURL exits with
+// a non-zero status on HTTP errors, without printing the response body.
+// It replaces [WithFailWithBody].
+func WithFailFast() Option {
+	return func(curling *Command) {
+		curling.fail = failFast
+	}
+}
+
+// WithFailWithBody enables the option --fail-with-body, so that cURL exits with
+// a non-zero status on HTTP errors, after printing the response body.
+// It replaces [WithFailFast].
+func WithFailWithBody() Option {
+	return func(curling *Command) {
+		curling.fail = failWithBody
+	}
+}
+
 // WithMultiLine splits the command across multiple lines.
 // The default line continuation character is backslash.
 func WithMultiLine() Option {
 	return func(curling *Command) {
 		curling.useMultiLine = true
 		curling.lineContinuation = lineContinuationDefault
+		curling.separator = ""
 	}
 }
 
@@ -59,6 +126,7 @@ func WithWindowsMultiLine() Option {
 	return func(curling *Command) {
 		curling.useMultiLine = true
 		curling.lineContinuation = lineContinuationWindows
+		curling.separator = ""
 	}
 }
 
@@ -68,6 +136,24 @@ func WithPowerShellMultiLine() Option {
 	return func(curling *Command) {
 		curling.useMultiLine = true
 		curling.lineContinuation = lineContinuationPowerShell
+		curling.separator = ""
+	}
+}
+
+// WithLineContinuation splits the command across multiple lines, joining the
+// tokens with separator as is, so any continuation and indentation can be used:
+// for example " \\\n    " for indented POSIX lines. An empty separator joins
+// the tokens with a bare newline, as in YAML literal blocks.
+// The comments above the command use the POSIX syntax.
+func WithLineContinuation(separator string) Option {
+	return func(curling *Command) {
+		if separator == "" {
+			separator = "\n"
+		}
+
+		curling.useMultiLine = true
+		curling.lineContinuation = ""
+		curling.separator = separator
 	}
 }
 
@@ -88,6 +174,371 @@ func WithRequestTimeout(seconds int) Option {
 			seconds = 0
 		}
 
-		curling.requestTimeout = seconds
+		curling.requestTimeout = time.Duration(seconds) * time.Second
+	}
+}
+
+// WithExtraFlags passes arguments the library doesn't model, such as
+// "--http2" or "--retry", "3", through to the cURL command, after the transport
+// options and before the method. Arguments are quoted unless they are made of
+// characters that no shell interprets. Repeated calls add more arguments.
+func WithExtraFlags(args ...string) Option {
+	return func(curling *Command) {
+		curling.extraArgs = append(curling.extraArgs, args...)
+	}
+}
+
+// WithQuoter sets the [Quoter] used to quote every value of the command,
+// such as [ANSICQuoter] or a custom one for unusual targets.
+// It takes precedence over [WithDoubleQuotes]. The built-in quoters render the
+// placeholders, such as the ones of [WithPlaceholders], with the variable syntax
+// of their shell; custom ones render them as ${NAME} text.
+func WithQuoter(q Quoter) Option {
+	return func(curling *Command) {
+		curling.quoter = q
+	}
+}
+
+// WithFormEncoding renders url-encoded bodies as one --data-urlencode option
+// per field, with the decoded value, so that values with special characters
+// stay readable and survive copy and paste. cURL encodes the values again,
+// possibly with a different but equivalent escaping.
+// Bodies that can't be split into fields are rendered with -d as usual.
+func WithFormEncoding() Option {
+	return func(curling *Command) {
+		curling.formEncoding = true
+	}
+}
+
+// WithBinaryBodyDir writes binary bodies, such as protobuf messages or images,
+// to a file in dir referenced with --data-binary @file, instead of rendering them
+// inline or, with [OutputVersion2], referencing the body.bin file the caller
+// would have to provide. An empty dir means the default
+// temporary directory. The written files are returned by [Command.Files].
+func WithBinaryBodyDir(dir string) Option {
+	return func(curling *Command) {
+		curling.binaryBodyFile = true
+		curling.binaryBodyDir = dir
+	}
+}
+
+// WithIdiomaticFlags adapts the flags to the request semantics, the way a person
+// would write the command: --compressed is left out for HEAD requests, Range
+// requests, whose offsets decompression breaks, and requests accepting the
+// identity encoding only, and with [OutputVersion2], the User-Agent header is
+// rendered with -A, as with [WithUserAgentFlag], the Referer header with -e,
+// as with [WithRefererFlag], and byte Range headers with -r, such as -r 0-499
+// for bytes=0-499.
+// Each --compressed decision is reported by [Command.Validate].
+// It is ignored by [WithFaithful].
+func WithIdiomaticFlags() Option {
+	return func(curling *Command) {
+		curling.idiomaticFlags = true
+	}
+}
+
+// WithUserAgentFlag renders the User-Agent header with the option -A, --user-agent
+// instead of -H, --header, the way the command is written by hand.
+// It is ignored by [WithFaithful].
+func WithUserAgentFlag() Option {
+	return func(curling *Command) {
+		curling.userAgentFlag = true
+	}
+}
+
+// WithRefererFlag renders the Referer header with the option -e, --referer
+// instead of -H, --header. With [WithFollowRedirects], the referer ends with
+// ;auto, so that cURL also sets it when following redirects.
+// It is ignored by [WithFaithful].
+func WithRefererFlag() Option {
+	return func(curling *Command) {
+		curling.refererFlag = true
+	}
+}
+
+// WithOAuth2BearerFlag renders a bearer Authorization header with the option
+// --oauth2-bearer instead of -H, --header, passing the token alone. A redacted
+// Authorization header (see [WithRedactedHeaders] and [WithMinimal]) is then
+// replaced with the BEARER_TOKEN placeholder, which holds the token only.
+// It is ignored by [WithFaithful].
+func WithOAuth2BearerFlag() Option {
+	return func(curling *Command) {
+		curling.oauth2BearerFlag = true
+	}
+}
+
+// WithFaithful maximizes the wire fidelity of the command, for replaying requests
+// byte for byte: header keys keep their casing, repeated headers get an option
+// for each value, Content-Length is declared explicitly, the body is sent with
+// --data-binary and the URL is protected with --path-as-is and --globoff.
+// Since [http.Header] doesn't keep the order of the headers, they are sorted by key.
+// It disables [WithMinimal] and [WithFormEncoding].
+func WithFaithful() Option {
+	return func(curling *Command) {
+		curling.faithful = true
+		curling.minimal = false
+	}
+}
+
+// WithMinimal produces the shortest command that still reproduces the request,
+// handy for bug reports: the transport options are left out, as is the method
+// when cURL implies it, and only the Authorization, Content-Encoding and
+// Content-Type headers are kept, with the Authorization value replaced by
+// the AUTHORIZATION placeholder (see [WithPlaceholders]).
+// It disables [WithFaithful].
+func WithMinimal() Option {
+	return func(curling *Command) {
+		curling.minimal = true
+		curling.faithful = false
+	}
+}
+
+// WithBodyToFile writes every request body to the file at path, replacing its
+// content, and references it with --data-binary @file, keeping large payloads
+// out of the command line. An empty path means a new file in the default
+// temporary directory. The written file is returned by [Command.Files].
+// It takes precedence over [WithBinaryBodyDir] and [WithFormEncoding].
+func WithBodyToFile(path string) Option {
+	return func(curling *Command) {
+		curling.bodyToFile = true
+		curling.bodyFile = path
+	}
+}
+
+// WithJSONFlag renders text bodies declared as application/json with the
+// --json option (cURL 7.82.0 or later), which also sets the Content-Type and
+// Accept headers, so these are left out when their value is application/json.
+// Note that --json adds Accept: application/json to requests without Accept.
+// Without this option, JSON bodies are rendered with -d and the headers,
+// which older cURL versions understand. It is ignored by [WithFaithful].
+func WithJSONFlag() Option {
+	return func(curling *Command) {
+		curling.jsonFlag = true
+	}
+}
+
+// WithDecodedBody decompresses request bodies encoded with gzip, deflate or br,
+// according to Content-Encoding, so the command shows readable data.
+// The Content-Encoding and Content-Length headers are dropped, so the replayed
+// request sends the decoded body, which [Command.Stats] then accounts for.
+// Bodies that can't be decoded are left as they are and reported by [Command.Validate].
+func WithDecodedBody() Option {
+	return func(curling *Command) {
+		curling.decodeBody = true
+	}
+}
+
+// WithRedactedHeaders replaces the values of the given headers, such as
+// Authorization or X-Api-Key, with shell variables named after the keys,
+// like ${X_API_KEY}, declared by the placeholder export block with masked
+// values (see [WithPlaceholders]). The values are removed from the request
+// model, so no output format shows them, while the cURL command stays executable
+// once the variables are set. Keys are case-insensitive.
+func WithRedactedHeaders(keys ...string) Option {
+	return func(curling *Command) {
+		for _, key := range keys {
+			curling.redactedHeaders = append(curling.redactedHeaders, http.CanonicalHeaderKey(key))
+		}
+	}
+}
+
+// WithHeaderRedactor calls fn with the canonical key and the value of each
+// header not listed by [WithRedactedHeaders]: when fn reports true, the value is
+// replaced with the returned string in every output format.
+func WithHeaderRedactor(fn func(key, value string) (string, bool)) Option {
+	return func(curling *Command) {
+		curling.headerRedactor = fn
+	}
+}
+
+// WithHeaderEncoding sets how header values containing non-ASCII characters,
+// which servers may interpret differently, are rendered: passed through as they
+// are ([HeaderEncodingRaw], the default), percent-encoded ([HeaderEncodingPercent])
+// or MIME-encoded as RFC 2047 encoded-words ([HeaderEncodingMIME]).
+// Either way, such headers are reported by [Command.Validate].
+func WithHeaderEncoding(encoding HeaderEncoding) Option {
+	return func(curling *Command) {
+		curling.headerEncoding = encoding
+	}
+}
+
+// WithScrubber runs s over the header values, including cookies, the URL and
+// the body of the request before any output format is rendered, masking
+// secrets such as the ones matched by [JWTScrubber] and [AWSKeyScrubber].
+// Scrubbers run in the order they are added. A nil scrubber will be silently ignored.
+func WithScrubber(s Scrubber) Option {
+	return func(curling *Command) {
+		if s != nil {
+			curling.scrubbers = append(curling.scrubbers, s)
+		}
+	}
+}
+
+// WithInlineWarnings renders the findings returned by [Command.Validate]
+// as comment lines above the command, so whoever receives the command
+// knows it may not be a faithful replay of the request.
+func WithInlineWarnings() Option {
+	return func(curling *Command) {
+		curling.inlineWarnings = true
+	}
+}
+
+// WithPlaceholders replaces the configured literal values, such as tokens or hostnames,
+// with shell variables in the rendered command.
+// The map keys are the variable names and the values the literals to replace:
+// each occurrence is rendered as ${NAME} and an export stub block, with masked values,
+// is emitted above the command. Placeholders use the POSIX shell syntax.
+// Invalid variable names and empty values will be silently ignored.
+func WithPlaceholders(vars map[string]string) Option {
+	return func(curling *Command) {
+		curling.placeholderVars = vars
+		curling.placeholders = newPlaceholders(vars)
+	}
+}
+
+// WithFlagGrouping groups related tokens the way browser developer tools do
+// with "Copy as cURL": the URL first, then the method, each header on its own,
+// the data and finally all the transport options together.
+// It is meant to be combined with a multiline option, whose continuation
+// lines are then indented.
+func WithFlagGrouping() Option {
+	return func(curling *Command) {
+		curling.groupFlags = true
+	}
+}
+
+// WithRenderer sets the [Renderer] used by [Command.String].
+// The default renderer is [Curl].
+func WithRenderer(r Renderer) Option {
+	return func(curling *Command) {
+		curling.renderer = r
+	}
+}
+
+// WithOutputVersion sets the rendering rules of the cURL command to the given
+// output version, such as [OutputVersion2] to opt into the latest rendering, or
+// [OutputVersion1] to freeze its ordering, quoting and spacing. Without it,
+// commands are rendered with [DefaultOutputVersion].
+// Unsupported versions fall back to [DefaultOutputVersion] and are reported by [Command.Validate].
+func WithOutputVersion(version int) Option {
+	return func(curling *Command) {
+		curling.outputVersion = version
+	}
+}
+
+// WithBodyKindComment renders the body format returned by [Command.BodyKind]
+// as a comment line above the command.
+func WithBodyKindComment() Option {
+	return func(curling *Command) {
+		curling.bodyKindComment = true
+	}
+}
+
+// WithHeaderSpill keeps the command line usable when header values are huge,
+// such as large JWTs or encoded SAML assertions: headers whose value exceeds
+// maxValueSize bytes are written to a file in dir, referenced with -H @file
+// (cURL 7.55.0 or later). An empty dir means the default temporary directory.
+// The written files are returned by [Command.Files].
+// Non-positive sizes will be silently ignored.
+func WithHeaderSpill(maxValueSize int, dir string) Option {
+	return func(curling *Command) {
+		if maxValueSize < 0 {
+			maxValueSize = 0
+		}
+
+		curling.headerSpillSize = maxValueSize
+		curling.headerSpillDir = dir
+	}
+}
+
+// WithHeaderFile writes every request header to the file at path, replacing
+// its content, and references it with a single -H @file option (cURL 7.55.0
+// or later), shortening commands for requests with dozens of headers.
+// It takes precedence over [WithHeaderSpill]. The written file is returned
+// by [Command.Files]. An empty path will be silently ignored.
+func WithHeaderFile(path string) Option {
+	return func(curling *Command) {
+		curling.headerFile = path
+	}
+}
+
+// WithTrace renders the connection details collected by t, such as the remote
+// address, the connection reuse and the TLS version, as comment lines above
+// the command. Since t is read when the command is rendered, the command can be
+// built before the request is sent. See [NewTrace].
+func WithTrace(t *Trace) Option {
+	return func(curling *Command) {
+		curling.trace = t
+	}
+}
+
+// WithPredicate makes [Transport] and [Middleware] convert only the requests
+// for which fn returns true, such as specific routes or requests carrying a
+// debug header, avoiding the conversion cost on uninteresting traffic.
+// The other requests are passed through untouched.
+// [NewFromRequest] and the other constructors ignore it.
+func WithPredicate(fn func(r *http.Request) bool) Option {
+	return func(curling *Command) {
+		curling.predicate = fn
+	}
+}
+
+// WithMaxBodySize truncates the request body rendered in the command to the
+// first size bytes, keeping logs and terminals readable with large uploads.
+// Truncated bodies are reported by [Command.Validate].
+// Non-positive sizes will be silently ignored.
+func WithMaxBodySize(size int) Option {
+	return func(curling *Command) {
+		if size < 0 {
+			size = 0
+		}
+
+		curling.maxBodySize = size
+	}
+}
+
+// WithMaxOutputSize bounds the whole rendered command to size bytes, for log
+// systems with hard per-record limits. The body is cut first, ending with a
+// [truncated] marker, then the longest header values, ending with the same
+// marker or, with [OutputVersion2], one reporting their original length, such
+// as [truncated, 16 of 47 bytes], then the headers are left out, as reported
+// by a comment line. The command may still exceed size when its URL alone does. Truncations are reported by
+// [Command.Validate] and the cut header values by [Command.TruncatedHeaders].
+// Non-positive sizes will be silently ignored.
+func WithMaxOutputSize(size int) Option {
+	return func(curling *Command) {
+		if size < 0 {
+			size = 0
+		}
+
+		curling.maxOutputSize = size
+	}
+}
+
+// WithClock sets the source of the time the command is built at, as reported
+// by the outputs that include a timestamp, so that golden tests stay deterministic.
+// A nil clock will be silently ignored.
+func WithClock(now func() time.Time) Option {
+	return func(curling *Command) {
+		curling.now = now
+	}
+}
+
+// WithIDGenerator sets the source of the IDs naming the side files written
+// while building the command, such as header and body files, instead of the
+// random names of [os.CreateTemp], so that golden tests stay deterministic.
+// A nil generator will be silently ignored.
+func WithIDGenerator(newID func() string) Option {
+	return func(curling *Command) {
+		curling.newID = newID
+	}
+}
+
+// WithTempFS sets the [TempFS] creating the side files written while building
+// the command, such as an in-memory one in tests.
+// A nil TempFS will be silently ignored.
+func WithTempFS(fs TempFS) Option {
+	return func(curling *Command) {
+		curling.tempFS = fs
 	}
 }
@@ -0,0 +1,238 @@
+package curling
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// truncationMarker ends the body cut to fit the output size.
+const truncationMarker = "[truncated]"
+
+// A TruncatedHeader describes a header value cut to fit the size set with
+// [WithMaxOutputSize], which ends with a truncation marker.
+type TruncatedHeader struct {
+	// Key is the header key.
+	Key string
+
+	// Kept is the number of bytes of the value kept before the marker.
+	Kept int
+
+	// Size is the length of the original value.
+	Size int
+
+	// index is the index of the value among the values of the key.
+	index int
+}
+
+// headerTruncationMarker returns the marker ending a header value of size bytes
+// of which kept bytes are left, such as [truncated, 16 of 47 bytes].
+// OutputVersion1 ends it with the plain [truncated] marker of the body.
+func (c *Command) headerTruncationMarker(kept, size int) string {
+	if c.version() < OutputVersion2 {
+		return truncationMarker
+	}
+
+	return fmt.Sprintf("[truncated, %d of %d bytes]", kept, size)
+}
+
+// TruncatedHeaders returns the header values cut to fit the size set with
+// [WithMaxOutputSize], sorted by key. Headers left out entirely are not included.
+func (c *Command) TruncatedHeaders() []TruncatedHeader {
+	if len(c.truncatedHeaders) == 0 {
+		return nil
+	}
+
+	return slices.Clone(c.truncatedHeaders)
+}
+
+// fitOutput shrinks the request until the rendered command fits the size set
+// with WithMaxOutputSize, then constructs the command: the inline body is cut
+// first, then the longest inline header values, then the inline headers are left
+// out one by one. Values written to side files, which the command only references, are kept.
+// The command is measured with dry runs, so that the side files are written once.
+// The command may still exceed the size when nothing is left to shrink.
+// If fitOutput can't write a side file, it returns an error.
+func (c *Command) fitOutput() error {
+	c.dryRun = true
+	for {
+		if err := c.reconstruct(); err != nil {
+			return err
+		}
+
+		excess := len(c.String()) - c.maxOutputSize
+		if excess <= 0 || !c.shrinkOutput(excess) {
+			break
+		}
+	}
+	c.dryRun = false
+
+	if err := c.reconstruct(); err != nil {
+		return err
+	}
+
+	slices.SortStableFunc(c.truncatedHeaders, func(a, b TruncatedHeader) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	for _, h := range c.truncatedHeaders {
+		c.warn(WarningHeaderTruncated, "header %q truncated to %d of %d bytes", h.Key, h.Kept, h.Size)
+	}
+
+	return nil
+}
+
+// reconstruct produces the tokens of the command again.
+// If reconstruct can't write a side file, it returns an error.
+func (c *Command) reconstruct() error {
+	c.once = sync.Once{}
+	c.tokens, c.args = nil, nil
+
+	var err error
+	c.once.Do(func() {
+		err = c.construct()
+	})
+
+	return err
+}
+
+// shrinkOutput shrinks the request by at least excess rendered bytes, at its
+// least valuable part, and reports whether there was anything left to shrink.
+func (c *Command) shrinkOutput(excess int) bool {
+	if !c.outputTruncated {
+		c.outputTruncated = true
+		c.warn(WarningOutputTruncated, "output truncated to fit %d bytes", c.maxOutputSize)
+	}
+
+	// The body is shared with the parsed request, so it is copied rather than cut in place.
+	if body, marked, ok := c.truncatableBody(); ok {
+		body = cutAtRune(body, max(0, len(body)-excess-markerSize(marked)))
+		c.request.body = append(append(make([]byte, 0, len(body)+len(truncationMarker)), body...), truncationMarker...)
+		return true
+	}
+
+	if key, i, value, ok := c.longestHeaderValue(); ok {
+		c.truncateHeaderValue(key, i, value, excess)
+		return true
+	}
+
+	keys := make([]string, 0, len(c.request.header))
+	for key, values := range c.request.header {
+		if slices.ContainsFunc(values, func(v string) bool { return !c.spilled(key, v) }) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return false
+	}
+
+	slices.Sort(keys)
+	key := keys[len(keys)-1]
+	delete(c.request.header, key)
+	c.droppedHeaders++
+
+	c.truncatedHeaders = slices.DeleteFunc(c.truncatedHeaders, func(h TruncatedHeader) bool {
+		return h.Key == key
+	})
+
+	return true
+}
+
+// truncateHeaderValue cuts value, the value at index i of key without its
+// truncation marker, so that the rendered value shrinks by at least excess bytes,
+// and ends it with the header truncation marker.
+func (c *Command) truncateHeaderValue(key string, i int, value string, excess int) {
+	h := c.truncatedHeader(key, i)
+	if h == nil {
+		c.truncatedHeaders = append(c.truncatedHeaders, TruncatedHeader{Key: key, Size: len(value), index: i})
+		h = &c.truncatedHeaders[len(c.truncatedHeaders)-1]
+	}
+
+	// The rendered value is the kept bytes followed by the current marker, if any;
+	// the new marker is at most as long as the one with every byte kept.
+	rendered := len(c.request.header[key][i])
+	kept := cutAtRune([]byte(value), max(0, rendered-excess-len(c.headerTruncationMarker(h.Size, h.Size))))
+
+	h.Kept = len(kept)
+	c.request.header[key][i] = string(kept) + c.headerTruncationMarker(h.Kept, h.Size)
+}
+
+// truncatedHeader returns the truncation of the value at index i of key, nil when it wasn't cut.
+func (c *Command) truncatedHeader(key string, i int) *TruncatedHeader {
+	for j := range c.truncatedHeaders {
+		if h := &c.truncatedHeaders[j]; h.Key == key && h.index == i {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// markerSize returns the size the truncation marker adds to a value, none when
+// the value is already marked.
+func markerSize(marked bool) int {
+	if marked {
+		return 0
+	}
+
+	return len(truncationMarker)
+}
+
+// spilled reports whether the value of the header key is written to a header file
+// rather than inlined, as buildHeaders does.
+func (c *Command) spilled(key, value string) bool {
+	if _, _, ok := c.headerFlag(headerField{key: key, value: value}); ok {
+		return false
+	}
+
+	return c.headerFile != "" || c.headerSpillSize > 0 && len(value) > c.headerSpillSize
+}
+
+// truncatableBody returns the text body without its truncation marker and
+// whether it had one, reporting false when there is no body text left to cut.
+func (c *Command) truncatableBody() (body []byte, marked bool, ok bool) {
+	if !c.request.hasBody || !isText(c.request.body) || c.bodyToFile {
+		return nil, false, false
+	}
+
+	body, marked = bytes.CutSuffix(c.request.body, []byte(truncationMarker))
+
+	return body, marked, len(body) > 0
+}
+
+// longestHeaderValue returns the longest inline header value without its truncation
+// marker, with its key and its index, reporting false when every value is empty.
+func (c *Command) longestHeaderValue() (key string, index int, value string, ok bool) {
+	keys := make([]string, 0, len(c.request.header))
+	for k := range c.request.header {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		for i, v := range c.request.header[k] {
+			if c.spilled(k, v) {
+				continue
+			}
+			if h := c.truncatedHeader(k, i); h != nil {
+				v = v[:h.Kept]
+			}
+			if len(v) > len(value) {
+				key, index, value, ok = k, i, v, true
+			}
+		}
+	}
+
+	return key, index, value, ok
+}
+
+// droppedHeadersComment returns the comment line reporting the headers left
+// out to fit the output size, if any.
+func (c *Command) droppedHeadersComment() string {
+	if c.droppedHeaders == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s %d headers left out %s\n", c.commentPrefix(), c.droppedHeaders, truncationMarker)
+}
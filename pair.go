@@ -0,0 +1,150 @@
+package curling
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// pairBodySize is the number of response body bytes kept by a [Pair].
+const pairBodySize = 1024
+
+// pairHeaders lists the response headers kept by a [Pair].
+var pairHeaders = []string{
+	"Content-Encoding",
+	"Content-Length",
+	"Content-Type",
+	"Location",
+	"Retry-After",
+	"Www-Authenticate",
+	"X-Request-Id",
+}
+
+// A Pair combines a command with a summary of the response it got,
+// so a single artifact shows both what was sent and what came back.
+type Pair struct {
+	// Command is the request.
+	Command *Command
+
+	// Proto is the response protocol, such as HTTP/1.1.
+	Proto string
+
+	// Status is the response status, such as 200 OK.
+	Status string
+
+	// Header holds the response headers that matter most when troubleshooting,
+	// such as Content-Type and Location.
+	Header http.Header
+
+	// Body holds the first bytes of the response body.
+	Body []byte
+
+	// BodySize is the size of the whole response body. When the body is longer
+	// than the bytes kept and its length is not declared, it is the number of
+	// bytes read, one more than the bytes kept.
+	BodySize int
+}
+
+// NewPair returns a [Pair] summarizing resp as the response of c.
+// Up to 1 KiB of the response body is read, then restored ahead of the unread
+// rest, so resp can still be used afterward.
+// If resp is nil or NewPair can't read the response body, it returns an error.
+func NewPair(c *Command, resp *http.Response) (Pair, error) {
+	if resp == nil {
+		return Pair{}, fmt.Errorf("response is nil")
+	}
+
+	p := Pair{
+		Command: c,
+		Proto:   resp.Proto,
+		Status:  resp.Status,
+		Header:  http.Header{},
+	}
+
+	if p.Proto == "" {
+		p.Proto = "HTTP/1.1"
+	}
+
+	if p.Status == "" {
+		p.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	for _, key := range pairHeaders {
+		if values := resp.Header.Values(key); len(values) > 0 {
+			p.Header[key] = values
+		}
+	}
+
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return p, nil
+	}
+
+	// One more byte than kept tells whether the body is longer.
+	b, err := io.ReadAll(io.LimitReader(resp.Body, pairBodySize+1))
+
+	body := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b), body), body}
+
+	if err != nil {
+		return Pair{}, fmt.Errorf("reading bytes from response body: %w", err)
+	}
+
+	p.BodySize = max(len(b), int(resp.ContentLength))
+	p.Body = cutAtRune(b, pairBodySize)
+
+	return p, nil
+}
+
+// cutAtRune returns the first size bytes of b at most, cut at a rune boundary,
+// so a text body doesn't end with a partial character.
+func cutAtRune(b []byte, size int) []byte {
+	size = min(len(b), size)
+	for size > 0 && size < len(b) && !utf8.RuneStart(b[size]) {
+		size--
+	}
+
+	return b[:size]
+}
+
+// String returns the command followed by the response summary as comment lines:
+// the status line, the kept headers and the first lines of the body.
+func (p Pair) String() string {
+	c := p.Command
+	prefix := c.commentPrefix()
+
+	var b strings.Builder
+	b.WriteString(c.String())
+
+	fmt.Fprintf(&b, "\n%s %s %s", prefix, p.Proto, p.Status)
+
+	for _, field := range (&parsedRequest{header: p.Header}).headerFields() {
+		fmt.Fprintf(&b, "\n%s %s", prefix, field)
+	}
+
+	if p.BodySize == 0 {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "\n%s", prefix)
+
+	if !isText(p.Body) {
+		fmt.Fprintf(&b, "\n%s [binary body, %d bytes]", prefix, p.BodySize)
+		return b.String()
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(string(p.Body), "\r\n"), "\n") {
+		b.WriteString(strings.TrimRight(fmt.Sprintf("\n%s %s", prefix, line), " \r"))
+	}
+
+	if len(p.Body) < p.BodySize {
+		fmt.Fprintf(&b, "\n%s [truncated, %d of %d bytes]", prefix, len(p.Body), p.BodySize)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,184 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewPair(t *testing.T) {
+	c := mustNewFromRequest(t, "GET", "https://localhost/test")
+
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+			"Date":         {"Mon, 02 Jan 2006 15:04:05 GMT"},
+		},
+		Body: readCloser(`{"error":"not found"}`),
+	}
+
+	got, err := NewPair(c, resp)
+	if err != nil {
+		t.Fatalf("NewPair() error = %v", err)
+	}
+
+	want := Pair{
+		Command:  c,
+		Proto:    "HTTP/1.1",
+		Status:   "404 Not Found",
+		Header:   http.Header{"Content-Type": {"application/json"}},
+		Body:     []byte(`{"error":"not found"}`),
+		BodySize: 21,
+	}
+	if !cmp.Equal(got, want, cmpCommand) {
+		t.Errorf("NewPair() diff = %v", cmp.Diff(got, want, cmpCommand))
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil || string(b) != `{"error":"not found"}` {
+		t.Errorf("restored body = %q, %v", b, err)
+	}
+}
+
+func TestNewPair_error(t *testing.T) {
+	c := mustNewFromRequest(t, "GET", "https://localhost/test")
+
+	_, err := NewPair(c, &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(readerWithError{})})
+	if err == nil {
+		t.Errorf("NewPair() error = nil, want error")
+	}
+}
+
+func TestNewPair_nilResponse(t *testing.T) {
+	c := mustNewFromRequest(t, "GET", "https://localhost/test")
+
+	if _, err := NewPair(c, nil); err == nil {
+		t.Errorf("NewPair() error = nil, want error")
+	}
+}
+
+func TestNewPair_largeBody(t *testing.T) {
+	c := mustNewFromRequest(t, "GET", "https://localhost/test")
+
+	body := strings.Repeat("a", 3*pairBodySize)
+	tests := []struct {
+		name          string
+		contentLength int64
+		wantBodySize  int
+	}{
+		{
+			name:          "declared length",
+			contentLength: int64(len(body)),
+			wantBodySize:  len(body),
+		},
+		{
+			name:          "unknown length",
+			contentLength: -1,
+			wantBodySize:  pairBodySize + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest := &failingBody{Reader: strings.NewReader(body), err: io.EOF}
+			resp := &http.Response{StatusCode: http.StatusOK, ContentLength: tt.contentLength, Body: rest}
+
+			p, err := NewPair(c, resp)
+			if err != nil {
+				t.Fatalf("NewPair() error = %v", err)
+			}
+
+			if len(p.Body) != pairBodySize || p.BodySize != tt.wantBodySize {
+				t.Errorf("NewPair() body = %d bytes of %d, want %d of %d", len(p.Body), p.BodySize, pairBodySize, tt.wantBodySize)
+			}
+
+			b, err := io.ReadAll(resp.Body)
+			if err != nil || string(b) != body {
+				t.Errorf("restored body = %d bytes, %v, want %d bytes", len(b), err, len(body))
+			}
+
+			if err := resp.Body.Close(); err != nil || !rest.closed {
+				t.Errorf("closing restored body didn't close the original body, error = %v", err)
+			}
+		})
+	}
+}
+
+func TestPair_String(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		resp *http.Response
+		want string
+	}{
+		{
+			name: "without body",
+			resp: &http.Response{
+				Proto:      "HTTP/2.0",
+				Status:     "204 No Content",
+				StatusCode: http.StatusNoContent,
+			},
+			want: "curl -X 'GET' 'https://localhost/test'\n" +
+				"# HTTP/2.0 204 No Content",
+		},
+		{
+			name: "text body",
+			resp: &http.Response{
+				StatusCode: http.StatusFound,
+				Header: http.Header{
+					"Location":     {"/login"},
+					"Content-Type": {"text/plain"},
+				},
+				Body: readCloser("redirecting\n\nto login\n"),
+			},
+			want: "curl -X 'GET' 'https://localhost/test'\n" +
+				"# HTTP/1.1 302 Found\n" +
+				"# Content-Type: text/plain\n" +
+				"# Location: /login\n" +
+				"#\n" +
+				"# redirecting\n" +
+				"#\n" +
+				"# to login",
+		},
+		{
+			name: "truncated body on windows",
+			opts: []Option{WithWindowsMultiLine(), WithDoubleQuotes()},
+			resp: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       readCloser(strings.Repeat("a", pairBodySize-1) + "é"),
+			},
+			want: "curl -X \"GET\" \"https://localhost/test\"\n" +
+				"REM HTTP/1.1 200 OK\n" +
+				"REM\n" +
+				"REM " + strings.Repeat("a", pairBodySize-1) + "\n" +
+				"REM [truncated, 1023 of 1025 bytes]",
+		},
+		{
+			name: "binary body",
+			resp: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       readCloser("\x00\x01\x02"),
+			},
+			want: "curl -X 'GET' 'https://localhost/test'\n" +
+				"# HTTP/1.1 200 OK\n" +
+				"#\n" +
+				"# [binary body, 3 bytes]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, "GET", "https://localhost/test", tt.opts...)
+
+			p, err := NewPair(c, tt.resp)
+			if err != nil {
+				t.Fatalf("NewPair() error = %v", err)
+			}
+
+			if got := p.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
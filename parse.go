@@ -0,0 +1,441 @@
+package curling
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// The flags read by [Parse] besides the ones the library emits.
+var (
+	flagShowError = flag{short: "-S", long: "--show-error"}
+	flagGet       = flag{short: "-G", long: "--get"}
+	flagBasic     = flag{long: "--basic"}
+	flagURL       = flag{long: "--url"}
+	flagDataASCII = flag{long: "--data-ascii"}
+	flagForm      = flag{short: "-F", long: "--form"}
+)
+
+// parseValueFlags lists the flags with an argument accepted by [Parse]:
+// the ones the library emits and the ones it only reads.
+var parseValueFlags = append(slices.Clone(valueFlags), flagURL, flagDataASCII, flagForm)
+
+// parseSwitchFlags lists the flags without argument accepted by [Parse].
+var parseSwitchFlags = append(slices.Clone(switchFlags), flagShowError, flagGet, flagBasic)
+
+// parseState accumulates the parts of the request while reading the arguments.
+type parseState struct {
+	method     string
+	rawurl     string
+	header     http.Header
+	data       []string
+	hasData    bool
+	form       []string
+	json       bool
+	get        bool
+	user       string
+	userFlag   string
+	authScheme string
+}
+
+// Parse returns the [http.Request] described by a cURL command, such as
+// the ones produced by this package or copied from documentation.
+//
+// Parse understands POSIX shell quoting, line continuations and the options
+// -X, -H, -u, -b, -A, -e, -r, -d, --data-raw, --data-binary, --data-urlencode,
+// --json, --oauth2-bearer, -F, -G, -I and --url. The other options the package
+// emits, such as -s or -L, don't change the request and are ignored.
+// Lines preceding the curl invocation, like comments and exports, are skipped.
+// If the command can't be tokenized, uses an unsupported option, reads data
+// or options from files, or authenticates with a scheme other than Basic,
+// Parse returns an error.
+func Parse(cmd string) (*http.Request, error) {
+	words, err := shellWords(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	start := -1
+	for i, w := range words {
+		if name := path.Base(w); name == "curl" || name == "curl.exe" {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		return nil, fmt.Errorf("curl invocation not found")
+	}
+
+	s := parseState{header: http.Header{}}
+	args := words[start+1:]
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if s.rawurl != "" {
+				return nil, fmt.Errorf("multiple urls are not supported")
+			}
+			s.rawurl = arg
+			continue
+		}
+
+		name, value, hasValue := splitFlag(arg)
+
+		if isSwitch(name) && !hasValue {
+			s.applySwitch(name)
+			continue
+		}
+
+		if isBoolCluster(name) && !hasValue {
+			for _, r := range name[1:] {
+				s.applySwitch("-" + string(r))
+			}
+			continue
+		}
+
+		if !hasFlag(parseValueFlags, name) {
+			return nil, fmt.Errorf("option %s is not supported", name)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("option %s: missing argument", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		if err := s.apply(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.request()
+}
+
+// splitFlag splits an argument into the option name and its attached value,
+// as in -XPOST or --request=POST.
+func splitFlag(arg string) (name, value string, hasValue bool) {
+	if strings.HasPrefix(arg, "--") {
+		return strings.Cut(arg, "=")
+	}
+
+	if len(arg) > 2 {
+		name := arg[:2]
+		if !isSwitch(name) || !isBoolCluster(arg) {
+			return name, arg[2:], true
+		}
+	}
+
+	return arg, "", false
+}
+
+// isBoolCluster reports whether arg groups several short options without argument, as in -sSL.
+func isBoolCluster(arg string) bool {
+	if len(arg) < 2 || strings.HasPrefix(arg, "--") {
+		return false
+	}
+
+	for _, r := range arg[1:] {
+		if !isSwitch("-" + string(r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSwitch reports whether name is an option without argument.
+func isSwitch(name string) bool {
+	return hasFlag(parseSwitchFlags, name)
+}
+
+// hasFlag reports whether name is the short or long form of one of flags.
+func hasFlag(flags []flag, name string) bool {
+	return slices.ContainsFunc(flags, func(f flag) bool {
+		return isFlag(name, f)
+	})
+}
+
+// isFlag reports whether name is the short or long form of f.
+func isFlag(name string, f flag) bool {
+	return name == f.short || name == f.long
+}
+
+// applySwitch records the option without argument name.
+// The switches that don't change the request are ignored.
+func (s *parseState) applySwitch(name string) {
+	switch {
+	case isFlag(name, flagGet):
+		s.get = true
+	case isFlag(name, flagHead):
+		s.method = http.MethodHead
+	case isFlag(name, flagDigest), isFlag(name, flagNTLM), isFlag(name, flagNegotiate), isFlag(name, flagAnyAuth):
+		s.authScheme = name
+	case isFlag(name, flagBasic):
+		s.authScheme = ""
+	}
+}
+
+// apply records the option name with its value.
+// The options that don't change the request are ignored.
+func (s *parseState) apply(name, value string) error {
+	switch {
+	case isFlag(name, flagRequest):
+		s.method = value
+	case isFlag(name, flagURL):
+		s.rawurl = value
+	case isFlag(name, flagHeader):
+		key, v, ok := strings.Cut(value, ":")
+		if !ok {
+			return fmt.Errorf("option %s: invalid header %q", name, value)
+		}
+		s.header.Add(strings.TrimSpace(key), strings.TrimSpace(v))
+	case isFlag(name, flagUserAgent):
+		s.header.Set("User-Agent", value)
+	case isFlag(name, flagReferer):
+		s.header.Set("Referer", strings.TrimSuffix(value, ";auto"))
+	case isFlag(name, flagRange):
+		s.header.Set("Range", "bytes="+value)
+	case isFlag(name, flagCookie):
+		if !strings.Contains(value, "=") {
+			return fmt.Errorf("option %s: reading cookies from files is not supported", name)
+		}
+		s.header.Add("Cookie", value)
+	case isFlag(name, flagOAuth2Bearer):
+		s.header.Set("Authorization", "Bearer "+value)
+	case isFlag(name, flagUser):
+		s.user = value
+		s.userFlag = name
+	case isFlag(name, flagData), isFlag(name, flagDataASCII), isFlag(name, flagDataBinary):
+		if strings.HasPrefix(value, "@") {
+			return fmt.Errorf("option %s: reading data from files is not supported", name)
+		}
+		s.addData(value)
+	case isFlag(name, flagDataRaw):
+		s.addData(value)
+	case isFlag(name, flagJSON):
+		if strings.HasPrefix(value, "@") {
+			return fmt.Errorf("option %s: reading data from files is not supported", name)
+		}
+		s.addData(value)
+		s.json = true
+	case isFlag(name, flagURLEncode):
+		encoded, err := dataURLEncode(value)
+		if err != nil {
+			return fmt.Errorf("option %s: %w", name, err)
+		}
+		s.addData(encoded)
+	case isFlag(name, flagForm):
+		if _, v, _ := strings.Cut(value, "="); strings.HasPrefix(v, "@") || strings.HasPrefix(v, "<") {
+			return fmt.Errorf("option %s: reading form files is not supported", name)
+		}
+		s.form = append(s.form, value)
+	case isFlag(name, flagConfig):
+		return fmt.Errorf("option %s: reading options from files is not supported", name)
+	}
+
+	return nil
+}
+
+// addData appends a data part, joined to the others with an ampersand as cURL does.
+func (s *parseState) addData(value string) {
+	s.data = append(s.data, value)
+	s.hasData = true
+}
+
+// request returns the [http.Request] described by the state.
+func (s *parseState) request() (*http.Request, error) {
+	if s.rawurl == "" {
+		return nil, fmt.Errorf("url not found")
+	}
+
+	if s.userFlag != "" {
+		if s.authScheme != "" {
+			return nil, fmt.Errorf("option %s: %s authentication is not supported", s.userFlag, s.authScheme)
+		}
+
+		username, password, _ := strings.Cut(s.user, ":")
+		s.header.Set("Authorization", basicAuthHeader(username, password))
+	}
+
+	rawurl := s.rawurl
+	if !strings.Contains(rawurl, "://") {
+		rawurl = "http://" + rawurl
+	}
+
+	method := s.method
+	var body io.Reader
+
+	switch {
+	case s.get:
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			return nil, fmt.Errorf("parsing request url: %w", err)
+		}
+		if s.hasData {
+			if u.RawQuery != "" {
+				u.RawQuery += "&"
+			}
+			u.RawQuery += strings.Join(s.data, "&")
+		}
+		rawurl = u.String()
+	case len(s.form) > 0:
+		b, contentType, err := multipartBody(s.form)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+		s.header.Set("Content-Type", contentType)
+	case s.hasData:
+		body = strings.NewReader(strings.Join(s.data, "&"))
+		if s.header.Get("Content-Type") == "" {
+			contentType := formContentType
+			if s.json {
+				contentType = "application/json"
+			}
+			s.header.Set("Content-Type", contentType)
+		}
+		if s.json && s.header.Get("Accept") == "" {
+			s.header.Set("Accept", "application/json")
+		}
+	}
+
+	if method == "" {
+		method = http.MethodGet
+		if body != nil {
+			method = http.MethodPost
+		}
+	}
+
+	r, err := http.NewRequest(method, rawurl, body)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+
+	r.Header = s.header
+
+	return r, nil
+}
+
+// dataURLEncode encodes the argument of --data-urlencode as cURL does.
+func dataURLEncode(value string) (string, error) {
+	name, content, found := strings.Cut(value, "=")
+	if !found {
+		if strings.Contains(value, "@") {
+			return "", fmt.Errorf("reading data from files is not supported")
+		}
+		return url.QueryEscape(value), nil
+	}
+
+	if name == "" {
+		return url.QueryEscape(content), nil
+	}
+
+	return name + "=" + url.QueryEscape(content), nil
+}
+
+// multipartBody returns the multipart body of the -F fields and its content type.
+func multipartBody(fields []string) (io.Reader, string, error) {
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+
+	for _, field := range fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, "", fmt.Errorf("invalid form field %q", field)
+		}
+
+		if err := w.WriteField(name, value); err != nil {
+			return nil, "", fmt.Errorf("writing form field: %w", err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("writing form: %w", err)
+	}
+
+	return &b, w.FormDataContentType(), nil
+}
+
+// shellWords splits s into words following the POSIX shell quoting rules.
+// Comments are skipped and the Windows and PowerShell line continuations
+// are accepted besides the backslash one.
+func shellWords(s string) ([]string, error) {
+	s = strings.NewReplacer(
+		" "+lineContinuationWindows+"\r\n", " ",
+		" "+lineContinuationWindows+"\n", " ",
+		" "+lineContinuationPowerShell+"\r\n", " ",
+		" "+lineContinuationPowerShell+"\n", " ",
+	).Replace(s)
+
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case ch == '#' && !inWord:
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case ch == '\\':
+			if i+1 < len(s) {
+				i++
+				if s[i] == '\n' {
+					continue
+				}
+				word.WriteByte(s[i])
+				inWord = true
+			}
+		case ch == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case ch == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		default:
+			word.WriteByte(ch)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
@@ -0,0 +1,149 @@
+package curling
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// placeholderMask replaces the values in the export stub block.
+const placeholderMask = "********"
+
+// placeholderName matches the names accepted as shell variables.
+var placeholderName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// placeholder is a literal value replaced by a shell variable in the rendered command.
+type placeholder struct {
+	name  string
+	value string
+}
+
+// newPlaceholders returns the valid placeholders of vars, keyed by variable name,
+// ordered so that longer values are replaced first.
+func newPlaceholders(vars map[string]string) []placeholder {
+	placeholders := make([]placeholder, 0, len(vars))
+	for name, value := range vars {
+		if value == "" || !placeholderName.MatchString(name) {
+			continue
+		}
+		placeholders = append(placeholders, placeholder{name: name, value: value})
+	}
+
+	slices.SortFunc(placeholders, func(a, b placeholder) int {
+		if n := len(b.value) - len(a.value); n != 0 {
+			return n
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	return placeholders
+}
+
+// replacePlaceholders replaces the placeholder values found in the quoted string s
+// with the related variable expansions. Values are matched in their escaped form,
+// in a single pass, so that no expansion is replaced again.
+func (c *Command) replacePlaceholders(s string) string {
+	if len(c.placeholders) == 0 {
+		return s
+	}
+
+	// Single quoted strings don't expand variables, so the quoting is
+	// interrupted around each expansion.
+	expansion := "'\"${%s}\"'"
+	if c.useDoubleQuotes {
+		expansion = "${%s}"
+	}
+
+	// The placeholders are ordered longest value first, which the replacer
+	// matches first at the same position.
+	oldnew := make([]string, 0, 2*len(c.placeholders))
+	for _, p := range c.placeholders {
+		oldnew = append(oldnew, c.escapeQuotes(p.value), fmt.Sprintf(expansion, p.name))
+	}
+	s = strings.NewReplacer(oldnew...).Replace(s)
+
+	// Drop the empty quotes left when the string starts or ends with an expansion.
+	if !c.useDoubleQuotes {
+		if strings.HasPrefix(s, "''\"${") {
+			s = s[2:]
+		}
+		if strings.HasSuffix(s, "}\"''") {
+			s = s[:len(s)-2]
+		}
+	}
+
+	return s
+}
+
+// quotePlaceholders quotes s with the configured [Quoter], rendering the placeholder
+// values as variable expansions of its shell. Custom quoters can't expand them,
+// so the values are replaced with ${NAME} text, which shows no secret.
+func (c *Command) quotePlaceholders(s string) string {
+	parts := splitPlaceholders(s, c.placeholders)
+	if len(parts) == 1 {
+		return c.quoter.Quote(s)
+	}
+
+	if q, ok := c.quoter.(*shellQuoter); ok {
+		return q.expand(parts)
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			part = "${" + part + "}"
+		}
+		b.WriteString(part)
+	}
+
+	return c.quoter.Quote(b.String())
+}
+
+// splitPlaceholders splits s around the placeholder values, in a single pass
+// matching the longest value first. The parts alternate literals and variable
+// names, starting and ending with a literal, possibly empty.
+func splitPlaceholders(s string, placeholders []placeholder) []string {
+	var parts []string
+
+	start := 0
+	for i := 0; i < len(s); {
+		j := slices.IndexFunc(placeholders, func(p placeholder) bool {
+			return strings.HasPrefix(s[i:], p.value)
+		})
+		if j < 0 {
+			i++
+			continue
+		}
+
+		parts = append(parts, s[start:i], placeholders[j].name)
+		i += len(placeholders[j].value)
+		start = i
+	}
+
+	return append(parts, s[start:])
+}
+
+// exportBlock returns the stub block that declares the placeholder variables,
+// with masked values.
+func (c *Command) exportBlock() string {
+	names := make([]string, 0, len(c.placeholders))
+	for _, p := range c.placeholders {
+		names = append(names, p.name)
+	}
+
+	slices.Sort(names)
+
+	q, _ := c.quoter.(*shellQuoter)
+
+	var b strings.Builder
+	for _, name := range names {
+		if q != nil {
+			b.WriteString(q.export(name) + "\n")
+			continue
+		}
+		fmt.Fprintf(&b, "export %s=%s\n", name, c.escape(placeholderMask))
+	}
+
+	return b.String()
+}
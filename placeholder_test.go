@@ -0,0 +1,124 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func Test_newPlaceholders(t *testing.T) {
+	vars := map[string]string{
+		"HOST":     "localhost",
+		"TOKEN":    "s3cr3t-t0ken",
+		"EMPTY":    "",
+		"1INVALID": "value",
+	}
+
+	want := []placeholder{
+		{name: "TOKEN", value: "s3cr3t-t0ken"},
+		{name: "HOST", value: "localhost"},
+	}
+
+	got := newPlaceholders(vars)
+	optUnexported := cmp.AllowUnexported(placeholder{})
+	if !cmp.Equal(got, want, optUnexported) {
+		t.Errorf("newPlaceholders() diff = %v", cmp.Diff(got, want, optUnexported))
+	}
+}
+
+func TestCommand_String_placeholders(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "api.example.com",
+		Path:   "test",
+	}
+
+	vars := map[string]string{
+		"HOST":  "api.example.com",
+		"TOKEN": "s3cr3t",
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "single quotes",
+			opts: []Option{WithPlaceholders(vars)},
+			want: "export HOST='********'\n" +
+				"export TOKEN='********'\n" +
+				"curl -X 'GET' 'https://'\"${HOST}\"'/test' -H 'Authorization: Bearer '\"${TOKEN}\"",
+		},
+		{
+			name: "double quotes",
+			opts: []Option{WithPlaceholders(vars), WithDoubleQuotes()},
+			want: "export HOST=\"********\"\n" +
+				"export TOKEN=\"********\"\n" +
+				"curl -X \"GET\" \"https://${HOST}/test\" -H \"Authorization: Bearer ${TOKEN}\"",
+		},
+		{
+			name: "no placeholders",
+			opts: []Option{WithPlaceholders(nil)},
+			want: "curl -X 'GET' 'https://api.example.com/test' -H 'Authorization: Bearer s3cr3t'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Request{
+				URL:    testUrl,
+				Header: http.Header{"Authorization": {"Bearer s3cr3t"}},
+			}
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_escape_placeholders(t *testing.T) {
+	c := &Command{
+		placeholders: []placeholder{{name: "TOKEN", value: "it's"}, {name: "NAME", value: "TOKEN"}},
+	}
+
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "whole value",
+			s:    "it's",
+			want: "\"${TOKEN}\"",
+		},
+		{
+			name: "inner value",
+			s:    "a it's b",
+			want: "'a '\"${TOKEN}\"' b'",
+		},
+		{
+			name: "value within an expansion",
+			s:    "it's TOKEN",
+			want: "\"${TOKEN}\"' '\"${NAME}\"",
+		},
+		{
+			name: "value not found",
+			s:    "its",
+			want: "'its'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.escape(tt.s); got != tt.want {
+				t.Errorf("escape() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
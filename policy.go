@@ -0,0 +1,115 @@
+package curling
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// policy is the logging policy read by [LoadPolicy].
+type policy struct {
+	// RedactHeaders lists the headers redacted with [WithRedactedHeaders].
+	RedactHeaders []string `yaml:"redactHeaders"`
+
+	// AllowHeaders lists the only headers whose values are shown, the others are masked.
+	AllowHeaders []string `yaml:"allowHeaders"`
+
+	// MaxBodySize is the value of [WithMaxBodySize].
+	MaxBodySize int `yaml:"maxBodySize"`
+
+	// Shell is the shell the command is quoted for: posix, cmd or powershell.
+	Shell string `yaml:"shell"`
+
+	// MultiLine splits the command across multiple lines, for the shell.
+	MultiLine bool `yaml:"multiLine"`
+
+	// Presets lists the named presets: minimal, faithful, idiomatic and long.
+	Presets []string `yaml:"presets"`
+}
+
+// policyPresets maps the preset names to their options.
+var policyPresets = map[string]Option{
+	"minimal":   WithMinimal(),
+	"faithful":  WithFaithful(),
+	"idiomatic": WithIdiomaticFlags(),
+	"long":      WithLongForm(),
+}
+
+// LoadPolicy reads a logging policy, written in YAML or JSON, and returns its options,
+// so that security teams can manage the logging policy centrally. For example:
+//
+//	redactHeaders: [Authorization, X-Api-Key]
+//	allowHeaders: [Accept, Authorization, Content-Type, X-Api-Key]
+//	maxBodySize: 1024
+//	shell: powershell
+//	multiLine: true
+//	presets: [idiomatic, long]
+//
+// The values of the headers missing from allowHeaders are masked, unless
+// they are redacted. The shell, posix by default, sets the quoting and
+// the line continuation of multiline commands.
+// If the policy can't be decoded or has unknown fields, shells or presets,
+// LoadPolicy returns an error.
+func LoadPolicy(r io.Reader) ([]Option, error) {
+	var p policy
+
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decoding policy: %w", err)
+	}
+
+	var opts []Option
+
+	for _, name := range p.Presets {
+		opt, ok := policyPresets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown policy preset %q", name)
+		}
+		opts = append(opts, opt)
+	}
+
+	switch p.Shell {
+	case "", ShellPOSIX:
+		if p.MultiLine {
+			opts = append(opts, WithMultiLine())
+		}
+	case ShellWindows:
+		opts = append(opts, WithQuoter(CmdQuoter))
+		if p.MultiLine {
+			opts = append(opts, WithWindowsMultiLine())
+		}
+	case ShellPowerShell:
+		opts = append(opts, WithQuoter(PowerShellQuoter))
+		if p.MultiLine {
+			opts = append(opts, WithPowerShellMultiLine())
+		}
+	default:
+		return nil, fmt.Errorf("unknown policy shell %q", p.Shell)
+	}
+
+	if p.MaxBodySize > 0 {
+		opts = append(opts, WithMaxBodySize(p.MaxBodySize))
+	}
+
+	if len(p.RedactHeaders) > 0 {
+		opts = append(opts, WithRedactedHeaders(p.RedactHeaders...))
+	}
+
+	if len(p.AllowHeaders) > 0 {
+		allowed := make([]string, 0, len(p.AllowHeaders))
+		for _, key := range p.AllowHeaders {
+			allowed = append(allowed, http.CanonicalHeaderKey(key))
+		}
+
+		opts = append(opts, WithHeaderRedactor(func(key, value string) (string, bool) {
+			return placeholderMask, !slices.Contains(allowed, key)
+		}))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,103 @@
+package curling
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "empty",
+			policy: "",
+			want:   "curl -X 'POST' 'https://localhost/test' -H 'Authorization: Bearer s3cr3t' -H 'Content-Type: text/plain' -H 'X-Request-Id: 42' -d 'hello world'",
+		},
+		{
+			name: "yaml",
+			policy: "redactHeaders: [authorization]\n" +
+				"allowHeaders: [Content-Type]\n" +
+				"maxBodySize: 5\n" +
+				"presets: [long]\n",
+			want: "export AUTHORIZATION='********'\n" +
+				"curl --request 'POST' 'https://localhost/test' --header 'Authorization: '\"${AUTHORIZATION}\" --header 'Content-Type: text/plain' --header 'X-Request-Id: ********' --data 'hello'",
+		},
+		{
+			name:   "json",
+			policy: `{"multiLine": true, "presets": ["minimal"]}`,
+			want: "export AUTHORIZATION='********'\n" +
+				"curl 'https://localhost/test' \\\n-H 'Authorization: '\"${AUTHORIZATION}\" \\\n-H 'Content-Type: text/plain' \\\n-d 'hello world'",
+		},
+		{
+			name:   "shell",
+			policy: "shell: cmd\n",
+			want:   `curl -X "POST" "https://localhost/test" -H "Authorization: Bearer s3cr3t" -H "Content-Type: text/plain" -H "X-Request-Id: 42" -d "hello world"`,
+		},
+		{
+			name:    "unknown field",
+			policy:  "redact: [Authorization]\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown shell",
+			policy:  "shell: fish\n",
+			wantErr: true,
+		},
+		{
+			name:   "redaction with cmd",
+			policy: "shell: cmd\nredactHeaders: [Authorization]\n",
+			want: "set \"AUTHORIZATION=********\"\n" +
+				"curl -X \"POST\" \"https://localhost/test\" -H \"Authorization: %AUTHORIZATION%\" -H \"Content-Type: text/plain\" -H \"X-Request-Id: 42\" -d \"hello world\"",
+		},
+		{
+			name:   "redaction with powershell",
+			policy: "shell: powershell\nredactHeaders: [Authorization]\n",
+			want: "$env:AUTHORIZATION = '********'\n" +
+				"curl -X 'POST' 'https://localhost/test' -H \"Authorization: ${env:AUTHORIZATION}\" -H 'Content-Type: text/plain' -H 'X-Request-Id: 42' -d 'hello world'",
+		},
+		{
+			name:    "unknown preset",
+			policy:  "presets: [verbose]\n",
+			wantErr: true,
+		},
+		{
+			name:    "malformed",
+			policy:  "maxBodySize: [\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := LoadPolicy(strings.NewReader(tt.policy))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			header := http.Header{}
+			header.Set("Authorization", "Bearer s3cr3t")
+			header.Set("Content-Type", "text/plain")
+			header.Set("X-Request-Id", "42")
+			c := mustNewWithBody(t, http.MethodPost, "https://localhost/test", header, "hello world")
+			c, err = c.With(opts...)
+			if err != nil {
+				t.Fatalf("With() error = %v", err)
+			}
+
+			got := c.String()
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if strings.Contains(tt.policy, "redactHeaders") && strings.Contains(got, "s3cr3t") {
+				t.Errorf("String() = %q, shows the redacted token", got)
+			}
+		})
+	}
+}
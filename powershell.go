@@ -0,0 +1,74 @@
+package curling
+
+import (
+	"fmt"
+	"strings"
+)
+
+// powerShellQuoteReplacer doubles every character that PowerShell treats as a single quote.
+var powerShellQuoteReplacer = strings.NewReplacer(
+	"'", "''",
+	"‘", "‘‘",
+	"’", "’’",
+	"‚", "‚‚",
+	"‛", "‛‛",
+)
+
+// ToInvokeWebRequest returns a PowerShell Invoke-WebRequest command equivalent to the cURL command.
+//
+// Headers are passed as a hashtable, except Content-Type which is passed with -ContentType.
+// Credentials embedded in the URL are moved to a basic Authorization header,
+// since Invoke-WebRequest doesn't accept them as part of -Uri.
+// The insecure and request timeout options are rendered as -SkipCertificateCheck and -TimeoutSec.
+// Any multiline option splits the command using the PowerShell backtick.
+func (c *Command) ToInvokeWebRequest() string {
+	u, header := c.request.withoutUserinfo()
+
+	contentType := header.Get("Content-Type")
+	header.Del("Content-Type")
+
+	s := []string{
+		"Invoke-WebRequest",
+		"-Method", powerShellQuote(c.request.method),
+		"-Uri", powerShellQuote(u.String()),
+	}
+
+	if c.requestTimeout > 0 {
+		s = append(s, "-TimeoutSec", wholeSeconds(c.requestTimeout))
+	}
+
+	if c.insecure {
+		s = append(s, "-SkipCertificateCheck")
+	}
+
+	tokens := []string{strings.Join(s, " ")}
+
+	if contentType != "" {
+		tokens = append(tokens, "-ContentType "+powerShellQuote(contentType))
+	}
+
+	fields := (&parsedRequest{header: header}).headerFields()
+	if len(fields) > 0 {
+		entries := make([]string, 0, len(fields))
+		for _, field := range fields {
+			entries = append(entries, fmt.Sprintf("%s = %s", powerShellQuote(field.key), powerShellQuote(field.value)))
+		}
+		tokens = append(tokens, fmt.Sprintf("-Headers @{ %s }", strings.Join(entries, "; ")))
+	}
+
+	if c.request.hasBody {
+		tokens = append(tokens, "-Body "+powerShellQuote(string(c.request.body)))
+	}
+
+	separator := " "
+	if c.useMultiLine {
+		separator = fmt.Sprintf(" %s\n", lineContinuationPowerShell)
+	}
+
+	return strings.Join(tokens, separator)
+}
+
+// powerShellQuote returns s as a PowerShell single quoted string.
+func powerShellQuote(s string) string {
+	return "'" + powerShellQuoteReplacer.Replace(s) + "'"
+}
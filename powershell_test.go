@@ -0,0 +1,136 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_ToInvokeWebRequest(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	userUrl := &url.URL{
+		Scheme: "https",
+		User:   url.UserPassword("user", "pass"),
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("X-Key", "it's")
+
+	type args struct {
+		r    *http.Request
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty method",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+			},
+			want: "Invoke-WebRequest -Method 'GET' -Uri 'https://localhost/test'",
+		},
+		{
+			name: "headers and body",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Header: header,
+					Body:   readCloser("key=value"),
+				},
+			},
+			want: "Invoke-WebRequest -Method 'POST' -Uri 'https://localhost/test' " +
+				"-ContentType 'application/x-www-form-urlencoded' " +
+				"-Headers @{ 'X-Key' = 'it''s' } " +
+				"-Body 'key=value'",
+		},
+		{
+			name: "url credentials",
+			args: args{
+				r: &http.Request{
+					URL: userUrl,
+				},
+			},
+			want: "Invoke-WebRequest -Method 'GET' -Uri 'https://localhost/test' " +
+				"-Headers @{ 'Authorization' = 'Basic dXNlcjpwYXNz' }",
+		},
+		{
+			name: "timeout and insecure options",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+				opts: []Option{WithRequestTimeout(5), WithInsecure()},
+			},
+			want: "Invoke-WebRequest -Method 'GET' -Uri 'https://localhost/test' -TimeoutSec 5 -SkipCertificateCheck",
+		},
+		{
+			name: "multiline option",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Body:   readCloser("key=value"),
+				},
+				opts: []Option{WithMultiLine()},
+			},
+			want: "Invoke-WebRequest -Method 'POST' -Uri 'https://localhost/test' `\n-Body 'key=value'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.args.r, tt.args.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.ToInvokeWebRequest(); got != tt.want {
+				t.Errorf("ToInvokeWebRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_powerShellQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "empty string",
+			s:    "",
+			want: "''",
+		},
+		{
+			name: "single quote",
+			s:    "'",
+			want: "''''",
+		},
+		{
+			name: "typographic quote",
+			s:    "it’s",
+			want: "'it’’s'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := powerShellQuote(tt.s); got != tt.want {
+				t.Errorf("powerShellQuote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package curling
+
+import (
+	"os"
+	"strings"
+)
+
+// proxyPasswordVariable is the shell variable replacing the proxy password.
+const proxyPasswordVariable = "PROXY_PASSWORD"
+
+// WithProxy sets the flag -x, --proxy, so that the command is sent through
+// the proxy at proxyURL, such as http://proxy.internal:3128.
+// An empty URL will be silently ignored.
+func WithProxy(proxyURL string) Option {
+	return func(curling *Command) {
+		if proxyURL == "" {
+			return
+		}
+
+		curling.proxy = proxyURL
+	}
+}
+
+// WithProxyUser sets the flag -U, --proxy-user, so that the command
+// authenticates to the proxy as user. The password is rendered as the
+// PROXY_PASSWORD placeholder (see [WithPlaceholders]); without one, cURL
+// prompts for it.
+// An empty user will be silently ignored.
+func WithProxyUser(user, password string) Option {
+	return func(curling *Command) {
+		if user == "" {
+			return
+		}
+
+		curling.proxyUser = user
+		curling.proxyPassword = password
+	}
+}
+
+// WithNoProxy sets the flag --noproxy, so that the command reaches the listed
+// hosts directly, as NO_PROXY does. A single * disables the proxy for every host.
+// Empty hosts will be silently ignored.
+func WithNoProxy(hosts ...string) Option {
+	return func(curling *Command) {
+		for _, host := range hosts {
+			if host != "" {
+				curling.noProxy = append(curling.noProxy, host)
+			}
+		}
+	}
+}
+
+// WithSOCKS5 sets the flag --socks5, so that the command is sent through
+// the SOCKS5 proxy at address, host[:port], resolving the host names locally.
+// An empty address will be silently ignored.
+func WithSOCKS5(address string) Option {
+	return func(curling *Command) {
+		if address == "" {
+			return
+		}
+
+		curling.socksProxy = address
+		curling.socksRemoteDNS = false
+	}
+}
+
+// WithSOCKS5Hostname sets the flag --socks5-hostname, so that the command is
+// sent through the SOCKS5 proxy at address, host[:port], which resolves the host names.
+// An empty address will be silently ignored.
+func WithSOCKS5Hostname(address string) Option {
+	return func(curling *Command) {
+		if address == "" {
+			return
+		}
+
+		curling.socksProxy = address
+		curling.socksRemoteDNS = true
+	}
+}
+
+// WithProxyFromEnvironment sets the flags -x, --proxy and --noproxy from the
+// environment the command is built in: HTTPS_PROXY or HTTP_PROXY, depending
+// on the request scheme, and NO_PROXY, or their lowercase versions. The command
+// can then be replayed where these variables are not set.
+// The proxy set with [WithProxy] and the hosts set with [WithNoProxy] take precedence.
+func WithProxyFromEnvironment() Option {
+	return func(curling *Command) {
+		curling.proxyFromEnv = true
+	}
+}
+
+// applyProxyEnvironment sets the proxy and the hosts reached directly from
+// the environment, when enabled with WithProxyFromEnvironment.
+func (c *Command) applyProxyEnvironment() {
+	if !c.proxyFromEnv {
+		return
+	}
+
+	if c.proxy == "" {
+		name := "HTTP_PROXY"
+		if c.request.url.Scheme == "https" {
+			name = "HTTPS_PROXY"
+		}
+		c.proxy = getenv(name)
+	}
+
+	if len(c.noProxy) == 0 {
+		if hosts := getenv("NO_PROXY"); hosts != "" {
+			c.noProxy = strings.Split(hosts, ",")
+		}
+	}
+}
+
+// getenv returns the value of the environment variable name,
+// falling back to its lowercase version.
+func getenv(name string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+
+	return os.Getenv(strings.ToLower(name))
+}
+
+// proxyUserArgument returns the argument of --proxy-user: the user,
+// followed by the password when set.
+func (c *Command) proxyUserArgument() string {
+	if c.proxyPassword == "" {
+		return c.proxyUser
+	}
+
+	return c.proxyUser + ":" + c.proxyPassword
+}
+
+// proxyOptions returns the options selecting the proxy.
+func (c *Command) proxyOptions() []word {
+	var s []word
+
+	if c.proxy != "" {
+		s = append(s, literal(c.option(flagProxy)), value(c.proxy))
+	}
+
+	if c.socksProxy != "" {
+		f := flagSOCKS5
+		if c.socksRemoteDNS {
+			f = flagSOCKS5Hostname
+		}
+		s = append(s, literal(c.option(f)), value(c.socksProxy))
+	}
+
+	if c.proxyUser != "" {
+		s = append(s, literal(c.option(flagProxyUser)), value(c.proxyUserArgument()))
+	}
+
+	if len(c.noProxy) > 0 {
+		s = append(s, literal(c.option(flagNoProxy)), value(strings.Join(c.noProxy, ",")))
+	}
+
+	return s
+}
+
+// redactProxyPassword replaces the proxy password with the PROXY_PASSWORD placeholder.
+func (c *Command) redactProxyPassword() {
+	if c.proxyUser == "" || c.proxyPassword == "" {
+		return
+	}
+
+	c.redactSecret(proxyPasswordVariable, &c.proxyPassword)
+}
@@ -0,0 +1,155 @@
+package curling
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithProxy(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		opts []Option
+		want string
+	}{
+		{
+			name: "proxy",
+			url:  "https://localhost/test",
+			opts: []Option{WithProxy("http://proxy.internal:3128")},
+			want: "curl -x 'http://proxy.internal:3128' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "proxy user",
+			url:  "https://localhost/test",
+			opts: []Option{WithProxy("http://proxy.internal:3128"), WithProxyUser("alice", "s3cr3t")},
+			want: "export PROXY_PASSWORD='********'\n" +
+				"curl -x 'http://proxy.internal:3128' -U 'alice:'\"${PROXY_PASSWORD}\" -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "proxy user without password",
+			url:  "https://localhost/test",
+			opts: []Option{WithProxy("http://proxy.internal:3128"), WithProxyUser("alice", ""), WithLongForm()},
+			want: "curl --proxy 'http://proxy.internal:3128' --proxy-user 'alice' --request 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "no proxy",
+			url:  "https://localhost/test",
+			opts: []Option{WithProxy("http://proxy.internal:3128"), WithNoProxy("localhost", "", ".internal")},
+			want: "curl -x 'http://proxy.internal:3128' --noproxy 'localhost,.internal' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "socks5",
+			url:  "https://localhost/test",
+			opts: []Option{WithSOCKS5("127.0.0.1:1080")},
+			want: "curl --socks5 '127.0.0.1:1080' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "socks5 hostname",
+			url:  "https://localhost/test",
+			opts: []Option{WithSOCKS5("127.0.0.1:1080"), WithSOCKS5Hostname("127.0.0.1:1080")},
+			want: "curl --socks5-hostname '127.0.0.1:1080' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "empty values",
+			url:  "https://localhost/test",
+			opts: []Option{WithProxy(""), WithProxyUser("", "s3cr3t"), WithSOCKS5("")},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, tt.url, tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithProxyUser_quoter(t *testing.T) {
+	tests := []struct {
+		name   string
+		quoter Quoter
+		want   string
+	}{
+		{
+			name:   "cmd",
+			quoter: CmdQuoter,
+			want:   "set \"PROXY_PASSWORD=********\"\ncurl -x \"http://proxy:3128\" -U \"pu:%PROXY_PASSWORD%\" -X \"GET\" \"https://localhost/test\"",
+		},
+		{
+			name:   "custom",
+			quoter: QuoterFunc(func(s string) string { return "<" + s + ">" }),
+			want:   "export PROXY_PASSWORD=<********>\ncurl -x <http://proxy:3128> -U <pu:${PROXY_PASSWORD}> -X <GET> <https://localhost/test>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test",
+				WithProxy("http://proxy:3128"), WithProxyUser("pu", "proxysecret"), WithQuoter(tt.quoter))
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			if got := c.Args(); got[4] != "pu:proxysecret" {
+				t.Errorf("Args()[4] = %q, want %q", got[4], "pu:proxysecret")
+			}
+		})
+	}
+}
+
+func TestWithProxyFromEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		env  map[string]string
+		opts []Option
+		want string
+	}{
+		{
+			name: "https",
+			url:  "https://localhost/test",
+			env:  map[string]string{"HTTPS_PROXY": "http://secure.internal:3128", "HTTP_PROXY": "http://plain.internal:3128"},
+			want: "curl -x 'http://secure.internal:3128' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "http",
+			url:  "http://localhost/test",
+			env:  map[string]string{"HTTPS_PROXY": "http://secure.internal:3128", "HTTP_PROXY": "http://plain.internal:3128"},
+			want: "curl -x 'http://plain.internal:3128' -X 'GET' 'http://localhost/test'",
+		},
+		{
+			name: "lowercase",
+			url:  "https://localhost/test",
+			env:  map[string]string{"https_proxy": "http://secure.internal:3128", "no_proxy": "localhost,.internal"},
+			want: "curl -x 'http://secure.internal:3128' --noproxy 'localhost,.internal' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "options take precedence",
+			url:  "https://localhost/test",
+			env:  map[string]string{"HTTPS_PROXY": "http://secure.internal:3128", "NO_PROXY": "*"},
+			opts: []Option{WithProxy("http://proxy.internal:8080"), WithNoProxy("localhost")},
+			want: "curl -x 'http://proxy.internal:8080' --noproxy 'localhost' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "no environment",
+			url:  "https://localhost/test",
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy", "NO_PROXY", "no_proxy"} {
+				t.Setenv(name, tt.env[name])
+			}
+
+			c := mustNewFromRequest(t, http.MethodGet, tt.url, append(tt.opts, WithProxyFromEnvironment())...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package curling
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ToPythonRequests returns a Python snippet that sends the request with the requests library.
+//
+// JSON bodies declared as application/json are passed with the json argument,
+// any other body with the data argument. Basic credentials, either embedded in the URL
+// or set by the Authorization header, are passed as the auth tuple.
+// The insecure and request timeout options are rendered as the verify and timeout arguments.
+// Note that requests follows redirects by default.
+func (c *Command) ToPythonRequests() string {
+	u := *c.request.url
+	header := c.request.header.Clone()
+
+	var username, password string
+	var hasAuth bool
+
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+		hasAuth = true
+		u.User = nil
+	}
+
+	if user, pass, ok := basicAuth(header.Get("Authorization")); ok {
+		username, password, hasAuth = user, pass, true
+		header.Del("Authorization")
+	}
+
+	var jsonBody string
+	if c.request.hasBody && isJSONContentType(header.Get("Content-Type")) {
+		if literal, err := pythonLiteral(c.request.body); err == nil {
+			jsonBody = literal
+
+			// The json argument already sets the standard content type.
+			if header.Get("Content-Type") == "application/json" {
+				header.Del("Content-Type")
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("import requests\n\n")
+	b.WriteString("response = requests.request(\n")
+	fmt.Fprintf(&b, "    %s,\n", jsonQuote(c.request.method))
+	fmt.Fprintf(&b, "    %s,\n", jsonQuote(u.String()))
+
+	if fields := (&parsedRequest{header: header}).headerFields(); len(fields) > 0 {
+		b.WriteString("    headers={\n")
+		for _, field := range fields {
+			fmt.Fprintf(&b, "        %s: %s,\n", jsonQuote(field.key), jsonQuote(field.value))
+		}
+		b.WriteString("    },\n")
+	}
+
+	switch {
+	case jsonBody != "":
+		fmt.Fprintf(&b, "    json=%s,\n", jsonBody)
+	case c.request.hasBody:
+		fmt.Fprintf(&b, "    data=%s,\n", jsonQuote(string(c.request.body)))
+	}
+
+	if hasAuth {
+		fmt.Fprintf(&b, "    auth=(%s, %s),\n", jsonQuote(username), jsonQuote(password))
+	}
+
+	if c.requestTimeout > 0 {
+		fmt.Fprintf(&b, "    timeout=%s,\n", formatSeconds(c.requestTimeout))
+	}
+
+	if c.insecure {
+		b.WriteString("    verify=False,\n")
+	}
+
+	b.WriteString(")")
+
+	return b.String()
+}
+
+// basicAuth returns the credentials of a basic Authorization header value.
+func basicAuth(authorization string) (username, password string, ok bool) {
+	r := http.Request{Header: http.Header{"Authorization": {authorization}}}
+	return r.BasicAuth()
+}
+
+// isJSONContentType reports whether contentType declares a JSON body,
+// either the standard type or a structured syntax suffix of it.
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// pythonLiteral converts the JSON document b into a Python literal,
+// preserving the order of object keys.
+// If b is not a single valid JSON document, pythonLiteral returns an error.
+func pythonLiteral(b []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var s strings.Builder
+	if err := writePythonValue(&s, dec); err != nil {
+		return "", err
+	}
+
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("unexpected data after json document")
+	}
+
+	return s.String(), nil
+}
+
+// writePythonValue writes the next JSON value read from dec as a Python literal.
+func writePythonValue(s *strings.Builder, dec *json.Decoder) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch v := token.(type) {
+	case json.Delim:
+		open, closing := string(v), "}"
+		if v == '[' {
+			closing = "]"
+		}
+
+		s.WriteString(open)
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				s.WriteString(", ")
+			}
+
+			if v == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(s, "%s: ", jsonQuote(key.(string)))
+			}
+
+			if err := writePythonValue(s, dec); err != nil {
+				return err
+			}
+		}
+
+		// Consume the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		s.WriteString(closing)
+	case string:
+		s.WriteString(jsonQuote(v))
+	case json.Number:
+		s.WriteString(v.String())
+	case bool:
+		if v {
+			s.WriteString("True")
+		} else {
+			s.WriteString("False")
+		}
+	case nil:
+		s.WriteString("None")
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_ToPythonRequests(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	type args struct {
+		r    *http.Request
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty method",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+			},
+			want: "import requests\n\n" +
+				"response = requests.request(\n" +
+				"    \"GET\",\n" +
+				"    \"https://localhost/test\",\n" +
+				")",
+		},
+		{
+			name: "json body",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Header: http.Header{"Content-Type": {"application/json"}},
+					Body:   readCloser(`{"z": [1, 2.5, true, null], "a": {"b": "c"}}`),
+				},
+			},
+			want: "import requests\n\n" +
+				"response = requests.request(\n" +
+				"    \"POST\",\n" +
+				"    \"https://localhost/test\",\n" +
+				"    json={\"z\": [1, 2.5, True, None], \"a\": {\"b\": \"c\"}},\n" +
+				")",
+		},
+		{
+			name: "invalid json body",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Header: http.Header{"Content-Type": {"application/json"}},
+					Body:   readCloser(`{"z": `),
+				},
+			},
+			want: "import requests\n\n" +
+				"response = requests.request(\n" +
+				"    \"POST\",\n" +
+				"    \"https://localhost/test\",\n" +
+				"    headers={\n" +
+				"        \"Content-Type\": \"application/json\",\n" +
+				"    },\n" +
+				"    data=\"{\\\"z\\\": \",\n" +
+				")",
+		},
+		{
+			name: "basic auth and options",
+			args: args{
+				r: &http.Request{
+					URL:    testUrl,
+					Header: http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+				},
+				opts: []Option{WithRequestTimeout(5), WithInsecure()},
+			},
+			want: "import requests\n\n" +
+				"response = requests.request(\n" +
+				"    \"GET\",\n" +
+				"    \"https://localhost/test\",\n" +
+				"    auth=(\"user\", \"pass\"),\n" +
+				"    timeout=5,\n" +
+				"    verify=False,\n" +
+				")",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.args.r, tt.args.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.ToPythonRequests(); got != tt.want {
+				t.Errorf("ToPythonRequests() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_pythonLiteral(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "scalar",
+			b:    `"a"`,
+			want: `"a"`,
+		},
+		{
+			name: "empty containers",
+			b:    `[{}, []]`,
+			want: `[{}, []]`,
+		},
+		{
+			name:    "trailing data",
+			b:       `{} {}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty document",
+			b:       ``,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pythonLiteral([]byte(tt.b))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pythonLiteral() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("pythonLiteral() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package curling
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Quoter quotes a value so that the target shell passes it to the program
+// as a single argument, unchanged.
+type Quoter interface {
+	Quote(s string) string
+}
+
+// The QuoterFunc type is an adapter to allow the use of ordinary functions as quoters.
+type QuoterFunc func(s string) string
+
+// Quote calls f(s).
+func (f QuoterFunc) Quote(s string) string {
+	return f(s)
+}
+
+// The built-in quoters. Their shells expand the placeholders set with
+// [WithPlaceholders] and the redaction options, which custom quoters render
+// as ${NAME} text instead.
+var (
+	// SingleQuoter quotes with POSIX single quotes, it is the default quoter.
+	SingleQuoter Quoter = &shellQuoter{quote: singleQuote, expand: posixExpand(singleQuote), export: posixExport(singleQuote)}
+
+	// DoubleQuoter quotes with double quotes, escaping only the double quotes,
+	// as [WithDoubleQuotes] does.
+	DoubleQuoter Quoter = &shellQuoter{quote: doubleQuote, expand: doubleExpand, export: posixExport(doubleQuote)}
+
+	// CmdQuoter quotes for programs started from cmd.exe, following the argument
+	// parsing rules of the Microsoft C runtime. Percent signs are not escaped.
+	CmdQuoter Quoter = &shellQuoter{quote: cmdQuote, expand: cmdExpand, export: cmdExport}
+
+	// PowerShellQuoter quotes with PowerShell single quotes.
+	PowerShellQuoter Quoter = &shellQuoter{quote: powerShellQuote, expand: powerShellExpand, export: powerShellExport}
+
+	// ANSICQuoter quotes with the $'...' strings of bash, zsh and ksh,
+	// which keep control characters readable.
+	ANSICQuoter Quoter = &shellQuoter{quote: ansiCQuote, expand: posixExpand(ansiCQuote), export: posixExport(ansiCQuote)}
+)
+
+// A shellQuoter is a built-in [Quoter], whose shell expands the placeholder variables.
+type shellQuoter struct {
+	// quote quotes a value.
+	quote func(s string) string
+
+	// expand quotes a value split by splitPlaceholders, alternating
+	// literals and variable names, with the variables expanded.
+	expand func(parts []string) string
+
+	// export returns the line declaring the variable name with a masked value.
+	export func(name string) string
+}
+
+// Quote implements the [Quoter] interface.
+func (q *shellQuoter) Quote(s string) string {
+	return q.quote(s)
+}
+
+// posixExpand returns the expansion of the POSIX shells, where quote
+// quotes the literals and the variables are double quoted.
+func posixExpand(quote func(s string) string) func(parts []string) string {
+	return func(parts []string) string {
+		var b strings.Builder
+		for i, part := range parts {
+			switch {
+			case i%2 == 1:
+				b.WriteString("\"${" + part + "}\"")
+			case part != "":
+				b.WriteString(quote(part))
+			}
+		}
+
+		return b.String()
+	}
+}
+
+// posixExport returns the export lines of the POSIX shells, quoted with quote.
+func posixExport(quote func(s string) string) func(name string) string {
+	return func(name string) string {
+		return "export " + name + "=" + quote(placeholderMask)
+	}
+}
+
+// doubleExpand expands the variables within a single double quoted string.
+func doubleExpand(parts []string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			part = "${" + part + "}"
+		}
+		b.WriteString(part)
+	}
+
+	return doubleQuote(b.String())
+}
+
+// cmdExpand expands the variables as %NAME%, which cmd.exe expands within double quotes too.
+func cmdExpand(parts []string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			part = "%" + part + "%"
+		}
+		b.WriteString(part)
+	}
+
+	return cmdQuote(b.String())
+}
+
+// cmdExport returns the set line of cmd.exe.
+func cmdExport(name string) string {
+	return "set \"" + name + "=" + placeholderMask + "\""
+}
+
+// powerShellExpand expands the variables as ${env:NAME} within a PowerShell
+// double quoted string, whose backticks, double quotes and dollar signs are escaped.
+func powerShellExpand(parts []string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString("${env:" + part + "}")
+			continue
+		}
+
+		for _, r := range part {
+			if r == '`' || r == '"' || r == '$' {
+				b.WriteByte('`')
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// powerShellExport returns the environment variable assignment of PowerShell.
+func powerShellExport(name string) string {
+	return "$env:" + name + " = " + powerShellQuote(placeholderMask)
+}
+
+// singleQuote returns s as a POSIX single quoted string.
+func singleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
+// doubleQuote returns s as a double quoted string with escaped double quotes.
+func doubleQuote(s string) string {
+	return "\"" + strings.ReplaceAll(s, "\"", "\\\"") + "\""
+}
+
+// cmdQuote returns s as an argument of the Microsoft C runtime command line:
+// double quotes are escaped with a backslash and the backslashes preceding
+// them, or the closing quote, are doubled.
+func cmdQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+
+	backslashes := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			backslashes++
+		case '"':
+			b.WriteString(strings.Repeat("\\", backslashes+1))
+			backslashes = 0
+		default:
+			backslashes = 0
+		}
+		b.WriteByte(s[i])
+	}
+
+	b.WriteString(strings.Repeat("\\", backslashes))
+	b.WriteByte('"')
+
+	return b.String()
+}
+
+// ansiCQuote returns s as an ANSI-C quoted string.
+func ansiCQuote(s string) string {
+	var b strings.Builder
+	b.WriteString("$'")
+
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; ch {
+		case '\\', '\'':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		case '\n':
+			b.WriteString("\\n")
+		case '\r':
+			b.WriteString("\\r")
+		case '\t':
+			b.WriteString("\\t")
+		default:
+			if ch < 0x20 || ch == 0x7f {
+				fmt.Fprintf(&b, "\\x%02x", ch)
+				continue
+			}
+			b.WriteByte(ch)
+		}
+	}
+
+	b.WriteByte('\'')
+
+	return b.String()
+}
@@ -0,0 +1,249 @@
+package curling
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestQuoters(t *testing.T) {
+	tests := []struct {
+		name   string
+		quoter Quoter
+		s      string
+		want   string
+	}{
+		{
+			name:   "single quotes",
+			quoter: SingleQuoter,
+			s:      `it's "$HOME"`,
+			want:   `'it'\''s "$HOME"'`,
+		},
+		{
+			name:   "double quotes",
+			quoter: DoubleQuoter,
+			s:      `it's "$HOME"`,
+			want:   `"it's \"$HOME\""`,
+		},
+		{
+			name:   "cmd quotes",
+			quoter: CmdQuoter,
+			s:      `C:\dir\ say "hi\" end\`,
+			want:   `"C:\dir\ say \"hi\\\" end\\"`,
+		},
+		{
+			name:   "powershell quotes",
+			quoter: PowerShellQuoter,
+			s:      `it's $HOME`,
+			want:   `'it''s $HOME'`,
+		},
+		{
+			name:   "ansi-c quotes",
+			quoter: ANSICQuoter,
+			s:      "it's\ta\\b\n\x00\x7f",
+			want:   `$'it\'s\ta\\b\n\x00\x7f'`,
+		},
+		{
+			name:   "quoter func",
+			quoter: QuoterFunc(func(s string) string { return "<" + s + ">" }),
+			s:      "value",
+			want:   "<value>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quoter.Quote(tt.s); got != tt.want {
+				t.Errorf("Quote() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithQuoter(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	r.Header.Set("X-Note", "line1\nline2")
+
+	c, err := NewFromRequest(r, WithQuoter(ANSICQuoter), WithDoubleQuotes(), WithWindowsMultiLine())
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	want := "curl -X $'GET' $'https://localhost/test' ^\n-H $'X-Note: line1\\nline2'"
+	if got := c.String(); got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+
+	if got := c.Validate(); got != nil {
+		t.Errorf("Validate() = %v, want nil", got)
+	}
+}
+
+func TestWithQuoter_redaction(t *testing.T) {
+	quoters := []struct {
+		name      string
+		quoter    Quoter
+		expansion func(name string) string
+		export    func(name string) string
+	}{
+		{
+			name:      "single",
+			quoter:    SingleQuoter,
+			expansion: func(name string) string { return "\"${" + name + "}\"" },
+			export:    func(name string) string { return "export " + name + "='********'" },
+		},
+		{
+			name:      "double",
+			quoter:    DoubleQuoter,
+			expansion: func(name string) string { return "${" + name + "}" },
+			export:    func(name string) string { return "export " + name + "=\"********\"" },
+		},
+		{
+			name:      "cmd",
+			quoter:    CmdQuoter,
+			expansion: func(name string) string { return "%" + name + "%" },
+			export:    func(name string) string { return "set \"" + name + "=********\"" },
+		},
+		{
+			name:      "powershell",
+			quoter:    PowerShellQuoter,
+			expansion: func(name string) string { return "${env:" + name + "}" },
+			export:    func(name string) string { return "$env:" + name + " = '********'" },
+		},
+		{
+			name:      "ansi-c",
+			quoter:    ANSICQuoter,
+			expansion: func(name string) string { return "\"${" + name + "}\"" },
+			export:    func(name string) string { return "export " + name + "=$'********'" },
+		},
+		{
+			name:      "custom",
+			quoter:    QuoterFunc(strconv.Quote),
+			expansion: func(name string) string { return "${" + name + "}" },
+			export:    func(name string) string { return "export " + name + "=\"********\"" },
+		},
+	}
+
+	redactions := []struct {
+		name     string
+		header   http.Header
+		opts     []Option
+		secret   string
+		variable string
+	}{
+		{
+			name:     "redacted headers",
+			header:   http.Header{"X-Api-Key": {"k3y-s3cret"}},
+			opts:     []Option{WithRedactedHeaders("X-Api-Key")},
+			secret:   "k3y-s3cret",
+			variable: "X_API_KEY",
+		},
+		{
+			name:     "placeholders",
+			header:   http.Header{"Authorization": {"Bearer t0k3n-s3cret"}},
+			opts:     []Option{WithPlaceholders(map[string]string{"TOKEN": "t0k3n-s3cret"})},
+			secret:   "t0k3n-s3cret",
+			variable: "TOKEN",
+		},
+		{
+			name:     "minimal",
+			header:   http.Header{"Authorization": {"Bearer m1n-s3cret"}},
+			opts:     []Option{WithMinimal()},
+			secret:   "m1n-s3cret",
+			variable: "AUTHORIZATION",
+		},
+		{
+			name:     "proxy user",
+			opts:     []Option{WithProxy("http://proxy:3128"), WithProxyUser("pu", "proxysecret")},
+			secret:   "proxysecret",
+			variable: "PROXY_PASSWORD",
+		},
+		{
+			name:     "auth user",
+			opts:     []Option{WithAuthScheme(AuthDigest), WithAuthUser("alice", "hunter2")},
+			secret:   "hunter2",
+			variable: "AUTH_PASSWORD",
+		},
+		{
+			name:     "cert password",
+			opts:     []Option{WithClientCert("c.pem", ""), WithCertPassword("certsecret")},
+			secret:   "certsecret",
+			variable: "CERT_PASSWORD",
+		},
+	}
+
+	for _, q := range quoters {
+		for _, rd := range redactions {
+			t.Run(q.name+"/"+rd.name, func(t *testing.T) {
+				r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+				if err != nil {
+					t.Fatalf("NewRequest() error = %v", err)
+				}
+				if rd.header != nil {
+					r.Header = rd.header
+				}
+
+				c, err := NewFromRequest(r, append(rd.opts, WithQuoter(q.quoter))...)
+				if err != nil {
+					t.Fatalf("NewFromRequest() error = %v", err)
+				}
+
+				got := c.String()
+				if strings.Contains(got, rd.secret) {
+					t.Errorf("String() = %v, shows the secret %q", got, rd.secret)
+				}
+				if want := q.expansion(rd.variable); !strings.Contains(got, want) {
+					t.Errorf("String() = %v, want the expansion %v", got, want)
+				}
+				if want := q.export(rd.variable) + "\n"; !strings.HasPrefix(got, want) {
+					t.Errorf("String() = %v, want the export line %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestWithQuoter_placeholders(t *testing.T) {
+	tests := []struct {
+		name   string
+		quoter Quoter
+		want   string
+	}{
+		{
+			name:   "cmd",
+			quoter: CmdQuoter,
+			want:   "set \"TOKEN=********\"\ncurl -X \"GET\" \"https://localhost/test\" -H \"Authorization: Bearer %TOKEN%\"",
+		},
+		{
+			name:   "powershell",
+			quoter: PowerShellQuoter,
+			want:   "$env:TOKEN = '********'\ncurl -X 'GET' 'https://localhost/test' -H \"Authorization: Bearer ${env:TOKEN}\"",
+		},
+		{
+			name:   "single",
+			quoter: SingleQuoter,
+			want:   "export TOKEN='********'\ncurl -X 'GET' 'https://localhost/test' -H 'Authorization: Bearer '\"${TOKEN}\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header.Set("Authorization", "Bearer s3cr3t")
+
+			c, err := NewFromRequest(r, WithQuoter(tt.quoter), WithPlaceholders(map[string]string{"TOKEN": "s3cr3t"}))
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package curling
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// NewFromRawHTTP returns a new [Command] from a raw HTTP/1.x request read from r,
+// as produced by [httputil.DumpRequest] or captured by proxies and sniffers.
+//
+// Requests in origin form, whose target is just a path, don't carry a scheme:
+// it is taken from the X-Forwarded-Proto header when present, otherwise it is
+// https when the Host port is 443 and http otherwise, even when no port is given.
+// If the request can't be read or has no host, NewFromRawHTTP returns an error.
+func NewFromRawHTTP(r io.Reader, opts ...Option) (*Command, error) {
+	return newFromReader(bufio.NewReader(r), opts...)
+}
+
+// NewFromConnection reads one HTTP/1.x request from br, such as the reader of a
+// connection accepted by a custom server or a sniffer, and returns the related [Command].
+// The request body is read entirely, so br is left positioned at the next request.
+// The scheme is inferred as in [NewFromRawHTTP].
+// When br holds no more requests, the returned error wraps [io.EOF].
+func NewFromConnection(br *bufio.Reader, opts ...Option) (*Command, error) {
+	return newFromReader(br, opts...)
+}
+
+// newFromReader reads one request from br and returns the related [Command].
+func newFromReader(br *bufio.Reader, opts ...Option) (*Command, error) {
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading raw request: %w", err)
+	}
+
+	if req.URL.Host == "" {
+		if req.Host == "" {
+			return nil, fmt.Errorf("raw request has no host")
+		}
+
+		req.URL.Host = req.Host
+		req.URL.Scheme = inferScheme(req)
+	}
+
+	return NewFromRequest(req, opts...)
+}
+
+// inferScheme returns the scheme of a request read in origin form.
+func inferScheme(req *http.Request) string {
+	if proto := req.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		return proto
+	}
+
+	if u := (url.URL{Host: req.Host}); u.Port() == "443" {
+		return "https"
+	}
+
+	return "http"
+}
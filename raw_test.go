@@ -0,0 +1,124 @@
+package curling
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_NewFromRawHTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "invalid request",
+			raw:     "NOT HTTP\r\n\r\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing host",
+			raw:     "GET /test HTTP/1.0\r\n\r\n",
+			wantErr: true,
+		},
+		{
+			name: "host without port",
+			raw:  "GET /test?a=1 HTTP/1.1\r\nHost: example.com\r\nAccept: */*\r\n\r\n",
+			want: "curl -X 'GET' 'http://example.com/test?a=1' -H 'Accept: */*'",
+		},
+		{
+			name: "host with tls port",
+			raw:  "GET /test HTTP/1.1\r\nHost: example.com:443\r\n\r\n",
+			want: "curl -X 'GET' 'https://example.com:443/test'",
+		},
+		{
+			name: "host with other port",
+			raw:  "GET /test HTTP/1.1\r\nHost: [::1]:8080\r\n\r\n",
+			want: "curl -X 'GET' 'http://[::1]:8080/test'",
+		},
+		{
+			name: "forwarded proto",
+			raw:  "GET /test HTTP/1.1\r\nHost: example.com\r\nX-Forwarded-Proto: http\r\n\r\n",
+			want: "curl -X 'GET' 'http://example.com/test' -H 'X-Forwarded-Proto: http'",
+		},
+		{
+			name: "forwarded tls proto without port",
+			raw:  "GET /test HTTP/1.1\r\nHost: example.com\r\nX-Forwarded-Proto: https\r\n\r\n",
+			want: "curl -X 'GET' 'https://example.com/test' -H 'X-Forwarded-Proto: https'",
+		},
+		{
+			name: "absolute form",
+			raw:  "GET http://example.com/test HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			want: "curl -X 'GET' 'http://example.com/test'",
+		},
+		{
+			name: "body",
+			raw:  "POST /test HTTP/1.1\r\nHost: example.com\r\nContent-Length: 9\r\n\r\nkey=value",
+			want: "curl -X 'POST' 'http://example.com/test' -H 'Content-Length: 9' -d 'key=value'",
+		},
+		{
+			name: "chunked body",
+			raw:  "POST /test HTTP/1.1\r\nHost: example.com\r\nTransfer-Encoding: chunked\r\n\r\n3\r\nkey\r\n6\r\n=value\r\n0\r\n\r\n",
+			want: "curl -X 'POST' 'http://example.com/test' -d 'key=value'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFromRawHTTP(strings.NewReader(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromRawHTTP() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("NewFromRawHTTP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_NewFromConnection(t *testing.T) {
+	stream := "POST /a HTTP/1.1\r\n" +
+		"Host: localhost:8080\r\n" +
+		"Content-Length: 9\r\n" +
+		"\r\n" +
+		"key=value" +
+		"PUT /b HTTP/1.1\r\n" +
+		"Host: localhost:8080\r\n" +
+		"Transfer-Encoding: chunked\r\n" +
+		"\r\n" +
+		"2\r\n{}\r\n0\r\n\r\n" +
+		"GET /c HTTP/1.1\r\n" +
+		"Host: localhost:8080\r\n" +
+		"\r\n"
+
+	br := bufio.NewReader(strings.NewReader(stream))
+
+	want := []string{
+		"curl -X 'POST' 'http://localhost:8080/a' -H 'Content-Length: 9' -d 'key=value'",
+		"curl -X 'PUT' 'http://localhost:8080/b' -d '{}'",
+		"curl -X 'GET' 'http://localhost:8080/c'",
+	}
+
+	for i, w := range want {
+		c, err := NewFromConnection(br)
+		if err != nil {
+			t.Fatalf("NewFromConnection() request %d error = %v", i, err)
+		}
+
+		if got := c.String(); got != w {
+			t.Errorf("String() request %d = %v, want %v", i, got, w)
+		}
+	}
+
+	if _, err := NewFromConnection(br); !errors.Is(err, io.EOF) {
+		t.Errorf("NewFromConnection() error = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,61 @@
+package curling
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// recordBodySize is the number of body bytes kept by a [Record].
+const recordBodySize = 256
+
+// A Record holds the structured fields describing a command, for log
+// pipelines that want them alongside the rendered command.
+type Record struct {
+	// Method is the request method.
+	Method string `json:"method"`
+
+	// URL is the request URL.
+	URL string `json:"url"`
+
+	// Header holds the request headers.
+	Header http.Header `json:"headers"`
+
+	// Flags lists the transport options of the cURL command, such as -s or -L.
+	Flags []string `json:"flags"`
+
+	// BodyPreview holds the first bytes of a text body, empty for binary bodies.
+	BodyPreview string `json:"bodyPreview,omitempty"`
+
+	// Truncated reports whether the body was truncated by [WithMaxBodySize].
+	Truncated bool `json:"truncated"`
+
+	// Curl is the command returned by [Command.String].
+	Curl string `json:"curl"`
+}
+
+// Record returns the structured fields describing the command.
+func (c *Command) Record() Record {
+	r := Record{
+		Method:    c.request.method,
+		URL:       c.request.url.String(),
+		Header:    c.request.header.Clone(),
+		Flags:     []string{},
+		Truncated: len(c.request.body) < c.request.bodySize,
+		Curl:      c.String(),
+	}
+
+	for _, w := range c.transportOptions() {
+		r.Flags = append(r.Flags, w.s)
+	}
+
+	if isText(c.request.body) {
+		r.BodyPreview = string(cutAtRune(c.request.body, recordBodySize))
+	}
+
+	return r
+}
+
+// MarshalJSON implements the [json.Marshaler] interface, encoding the [Record] of the command.
+func (c *Command) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Record())
+}
@@ -0,0 +1,85 @@
+package curling
+
+import (
+	"encoding/json"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCommand_Record(t *testing.T) {
+	long := strings.Repeat("a", recordBodySize-1) + "é"
+
+	tests := []struct {
+		name string
+		c    func(t *testing.T) *Command
+		want Record
+	}{
+		{
+			name: "without body",
+			c: func(t *testing.T) *Command {
+				return mustNewFromRequest(t, http.MethodGet, "https://localhost/test", WithSilent(), WithRequestTimeout(5))
+			},
+			want: Record{
+				Method: http.MethodGet,
+				URL:    "https://localhost/test",
+				Header: http.Header{},
+				Flags:  []string{"-s", "-m", "5"},
+				Curl:   "curl -s -m 5 -X 'GET' 'https://localhost/test'",
+			},
+		},
+		{
+			name: "truncated body cut at a rune boundary",
+			c: func(t *testing.T) *Command {
+				c := mustNewWithBody(t, http.MethodPost, "https://localhost/test", http.Header{"X-Key": {"1"}}, long+"b")
+				c.request.bodySize++
+				return c
+			},
+			want: Record{
+				Method:      http.MethodPost,
+				URL:         "https://localhost/test",
+				Header:      http.Header{"X-Key": {"1"}},
+				Flags:       []string{},
+				BodyPreview: strings.Repeat("a", recordBodySize-1),
+				Truncated:   true,
+				Curl:        "curl -X 'POST' 'https://localhost/test' -H 'X-Key: 1' -d '" + long + "b'",
+			},
+		},
+		{
+			name: "binary body",
+			c: func(t *testing.T) *Command {
+				return mustNewWithBody(t, http.MethodPost, "https://localhost/test", nil, "\x00")
+			},
+			want: Record{
+				Method: http.MethodPost,
+				URL:    "https://localhost/test",
+				Header: http.Header{},
+				Flags:  []string{},
+				Curl:   "curl -X 'POST' 'https://localhost/test' -d '\x00'",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c(t).Record(); !cmp.Equal(got, tt.want) {
+				t.Errorf("Record() diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCommand_MarshalJSON(t *testing.T) {
+	c := mustNewWithBody(t, http.MethodPost, "https://localhost/test", http.Header{"X-Key": {"1"}}, "key=value")
+
+	got, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"method":"POST","url":"https://localhost/test","headers":{"X-Key":["1"]},"flags":[],` +
+		`"bodyPreview":"key=value","truncated":false,"curl":"curl -X 'POST' 'https://localhost/test' -H 'X-Key: 1' -d 'key=value'"}`
+	if string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
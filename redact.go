@@ -0,0 +1,145 @@
+package curling
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// bearerTokenVariable is the variable replacing the token of a redacted bearer
+// Authorization header with [WithOAuth2BearerFlag].
+const bearerTokenVariable = "BEARER_TOKEN"
+
+// redactHeaders replaces the values of the sensitive headers in the request model,
+// so that no output format shows them. The headers listed with WithRedactedHeaders
+// get a shell variable named after the key, numbered for each distinct value,
+// declared by the placeholder export block, and the others are passed to the
+// header redactor. A bearer token rendered with
+// --oauth2-bearer gets the BEARER_TOKEN variable instead.
+func (c *Command) redactHeaders() {
+	vars := map[string]string{}
+
+	// The keys are sorted, so that the variables of distinct values are numbered
+	// the same way each time.
+	keys := make([]string, 0, len(c.request.header))
+	for key := range c.request.header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		values := c.request.header[key]
+		canonicalKey := http.CanonicalHeaderKey(key)
+
+		if c.isRedactedHeader(canonicalKey) {
+			for i, value := range values {
+				name, secret := redactionVariable(canonicalKey), value
+				token, bearer := c.bearerToken(value)
+				if bearer && canonicalKey == "Authorization" {
+					name, secret = bearerTokenVariable, token
+				}
+
+				name = c.secretVariable(name, secret)
+				c.setSecretValue(name, secret)
+
+				expansion := "${" + name + "}"
+				vars[name] = expansion
+
+				if bearer && canonicalKey == "Authorization" {
+					values[i] = "Bearer " + expansion
+				} else {
+					values[i] = expansion
+				}
+			}
+			continue
+		}
+
+		if c.headerRedactor == nil {
+			continue
+		}
+
+		for i, value := range values {
+			if redacted, ok := c.headerRedactor(canonicalKey, value); ok {
+				values[i] = redacted
+			}
+		}
+	}
+
+	if len(vars) == 0 {
+		return
+	}
+
+	for _, p := range c.placeholders {
+		vars[p.name] = p.value
+	}
+	c.placeholders = newPlaceholders(vars)
+}
+
+// redactSecret replaces the password of an option held by value in the command
+// model with the expansion of the variable name, declared by the placeholder export
+// block, so that no output shows it, whatever the quoting. The password is kept
+// for WithEnvFile and for Args, whose arguments are passed to cURL as they are.
+func (c *Command) redactSecret(name string, value *string) {
+	name = c.secretVariable(name, *value)
+	c.setSecretValue(name, *value)
+	c.optionSecrets = append(c.optionSecrets, name)
+
+	expansion := "${" + name + "}"
+	*value = expansion
+
+	vars := map[string]string{name: expansion}
+	for _, p := range c.placeholders {
+		vars[p.name] = p.value
+	}
+	c.placeholders = newPlaceholders(vars)
+}
+
+// setSecretValue records value as the secret replaced by the variable name,
+// written by WithEnvFile.
+func (c *Command) setSecretValue(name, value string) {
+	if c.secretValues == nil {
+		c.secretValues = make(map[string]string)
+	}
+
+	c.secretValues[name] = value
+}
+
+// secretVariable returns the variable replacing the secret value: name, unless it
+// already replaces another value, otherwise name followed by the first free
+// number, such as X_API_KEY_2, so that each variable holds a single value.
+func (c *Command) secretVariable(name, value string) string {
+	variable := name
+	for n := 2; ; n++ {
+		if secret, ok := c.secretValues[variable]; !ok || secret == value {
+			return variable
+		}
+		variable = fmt.Sprintf("%s_%d", name, n)
+	}
+}
+
+// isRedactedHeader reports whether key is listed with WithRedactedHeaders.
+func (c *Command) isRedactedHeader(key string) bool {
+	for _, redacted := range c.redactedHeaders {
+		if redacted == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactionVariable returns the shell variable name replacing the value of the header key,
+// such as X_API_KEY for X-Api-Key.
+func redactionVariable(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
@@ -0,0 +1,125 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_redactionVariable(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "Authorization", want: "AUTHORIZATION"},
+		{key: "X-Api-Key", want: "X_API_KEY"},
+		{key: "X-Token.v2", want: "X_TOKEN_V2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := redactionVariable(tt.key); got != tt.want {
+				t.Errorf("redactionVariable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_String_redactedHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "redacted headers",
+			opts: []Option{WithRedactedHeaders("authorization", "X-API-KEY")},
+			want: "export AUTHORIZATION='********'\n" +
+				"export X_API_KEY='********'\n" +
+				"curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -H 'Authorization: '\"${AUTHORIZATION}\" -H 'X-Api-Key: '\"${X_API_KEY}\"",
+		},
+		{
+			name: "redacted headers with placeholders",
+			opts: []Option{
+				WithPlaceholders(map[string]string{"HOST": "localhost"}),
+				WithRedactedHeaders("X-Api-Key"),
+			},
+			want: "export HOST='********'\n" +
+				"export X_API_KEY='********'\n" +
+				"curl -X 'GET' 'https://'\"${HOST}\"'/test' -H 'Accept: */*' -H 'Authorization: Bearer s3cr3t' -H 'X-Api-Key: '\"${X_API_KEY}\"",
+		},
+		{
+			name: "header redactor",
+			opts: []Option{WithHeaderRedactor(func(key, value string) (string, bool) {
+				if strings.HasPrefix(value, "Bearer ") {
+					return "Bearer REDACTED", true
+				}
+				return "", false
+			})},
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -H 'Authorization: Bearer REDACTED' -H 'X-Api-Key: k3y'",
+		},
+		{
+			name: "redacted headers take precedence over the redactor",
+			opts: []Option{
+				WithRedactedHeaders("X-Api-Key"),
+				WithHeaderRedactor(func(key, value string) (string, bool) {
+					return "REDACTED", key != "Accept"
+				}),
+			},
+			want: "export X_API_KEY='********'\n" +
+				"curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -H 'Authorization: REDACTED' -H 'X-Api-Key: '\"${X_API_KEY}\"",
+		},
+		{
+			name: "missing headers",
+			opts: []Option{WithRedactedHeaders("Cookie")},
+			want: "curl -X 'GET' 'https://localhost/test' -H 'Accept: */*' -H 'Authorization: Bearer s3cr3t' -H 'X-Api-Key: k3y'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			r.Header.Set("Accept", "*/*")
+			r.Header.Set("Authorization", "Bearer s3cr3t")
+			r.Header.Set("X-Api-Key", "k3y")
+
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+			if got := r.Header.Get("Authorization"); got != "Bearer s3cr3t" {
+				t.Errorf("request header Authorization = %v, want it untouched", got)
+			}
+		})
+	}
+}
+
+func TestCommand_redactedHeaders_otherFormats(t *testing.T) {
+	r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	r.Header.Set("Authorization", "Bearer s3cr3t")
+
+	c, err := NewFromRequest(r, WithRedactedHeaders("Authorization"))
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	for _, got := range []string{c.ToWget(), c.ToFetch(), c.ToPythonRequests(), c.ToInvokeWebRequest()} {
+		if strings.Contains(got, "s3cr3t") {
+			t.Errorf("%s: the redacted value is leaked", got)
+		}
+	}
+
+	want := []string{"Authorization"}
+	if got := c.Options().RedactedHeaders; !cmp.Equal(got, want) {
+		t.Errorf("RedactedHeaders diff = %v", cmp.Diff(got, want))
+	}
+}
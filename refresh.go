@@ -0,0 +1,96 @@
+package curling
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WithTokenRefresh replaces the expiring bearer tokens of the script, JWTs
+// carrying an exp claim, with expression, a shell expansion such as
+// $(get-token) or ${ACCESS_TOKEN}, so that the script doesn't ship credentials
+// that silently fail once expired. Each replacement is explained by a comment.
+// Commands using another renderer or a custom [Quoter] are left untouched.
+// An empty expression will be silently ignored.
+func WithTokenRefresh(expression string) ScriptOption {
+	return func(config *scriptConfig) {
+		config.tokenRefresh = expression
+	}
+}
+
+// expiringToken is a bearer token found in a command, with its expiration time.
+type expiringToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// expiringToken returns the bearer token of the Authorization header when it expires.
+func (c *Command) expiringToken() (expiringToken, bool) {
+	scheme, token, ok := strings.Cut(c.request.header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return expiringToken{}, false
+	}
+
+	token = strings.TrimSpace(token)
+
+	expiresAt, ok := jwtExpiration(token)
+	if !ok {
+		return expiringToken{}, false
+	}
+
+	return expiringToken{token: token, expiresAt: expiresAt}, true
+}
+
+// jwtExpiration returns the time set by the exp claim of the JWT token.
+func jwtExpiration(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(exp), 0).UTC(), true
+}
+
+// refreshToken replaces the expiring bearer token of c found in s, a piece of
+// the rendered command, with the configured expression. The comment explaining
+// the replacement is returned along with the result, empty when nothing is replaced.
+func (config scriptConfig) refreshToken(c *Command, s string) (string, string) {
+	if config.tokenRefresh == "" || !c.rendersCurl() || c.quoter != nil {
+		return s, ""
+	}
+
+	t, ok := c.expiringToken()
+	if !ok || !strings.Contains(s, t.token) {
+		return s, ""
+	}
+
+	// Single quoted strings don't expand, so the quoting is interrupted around the expression.
+	expansion := "'\"" + config.tokenRefresh + "\"'"
+	if c.useDoubleQuotes {
+		expansion = config.tokenRefresh
+	}
+
+	comment := fmt.Sprintf("# Bearer token expiring at %s replaced with %s",
+		t.expiresAt.Format(time.RFC3339), config.tokenRefresh)
+
+	return strings.ReplaceAll(s, t.token, expansion), comment
+}
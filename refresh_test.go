@@ -0,0 +1,153 @@
+package curling
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// jwt returns an unsigned JWT carrying the given payload.
+func jwt(payload string) string {
+	return "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+}
+
+// mustNewWithToken returns a new command sending the given bearer token or stops the test on error.
+func mustNewWithToken(t *testing.T, rawurl, token string, opts ...Option) *Command {
+	t.Helper()
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		t.Fatalf("parsing url: %v", err)
+	}
+
+	r := &http.Request{
+		Method: http.MethodGet,
+		URL:    u,
+		Header: http.Header{"Authorization": {"Bearer " + token}},
+	}
+
+	c, err := NewFromRequest(r, opts...)
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	return c
+}
+
+func TestCommandSet_Script_tokenRefresh(t *testing.T) {
+	expiring := jwt(`{"sub":"42","exp":1767225600}`)
+	lasting := jwt(`{"sub":"42"}`)
+
+	tests := []struct {
+		name string
+		set  func(t *testing.T) CommandSet
+		opts []ScriptOption
+		want string
+	}{
+		{
+			name: "command substitution",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{mustNewWithToken(t, "https://localhost/a", expiring)}
+			},
+			opts: []ScriptOption{WithTokenRefresh("$(get-token)")},
+			want: "#!/bin/sh\n\n" +
+				"# Bearer token expiring at 2026-01-01T00:00:00Z replaced with $(get-token)\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Authorization: Bearer '\"$(get-token)\"''\n",
+		},
+		{
+			name: "variable with double quotes",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{mustNewWithToken(t, "https://localhost/a", expiring, WithDoubleQuotes())}
+			},
+			opts: []ScriptOption{WithTokenRefresh("${ACCESS_TOKEN}")},
+			want: "#!/bin/sh\n\n" +
+				"# Bearer token expiring at 2026-01-01T00:00:00Z replaced with ${ACCESS_TOKEN}\n" +
+				"curl -X \"GET\" \"https://localhost/a\" -H \"Authorization: Bearer ${ACCESS_TOKEN}\"\n",
+		},
+		{
+			name: "token without expiration",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{mustNewWithToken(t, "https://localhost/a", lasting)}
+			},
+			opts: []ScriptOption{WithTokenRefresh("$(get-token)")},
+			want: "#!/bin/sh\n\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Authorization: Bearer " + lasting + "'\n",
+		},
+		{
+			name: "with connection reuse",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{
+					mustNewWithToken(t, "https://localhost/a", expiring),
+					mustNewWithToken(t, "https://localhost/b", expiring),
+				}
+			},
+			opts: []ScriptOption{WithConnectionReuse(), WithTokenRefresh("${ACCESS_TOKEN}")},
+			want: "#!/bin/sh\n\n" +
+				"# Bearer token expiring at 2026-01-01T00:00:00Z replaced with ${ACCESS_TOKEN}\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Authorization: Bearer '\"${ACCESS_TOKEN}\"'' " +
+				"--next -X 'GET' 'https://localhost/b' -H 'Authorization: Bearer '\"${ACCESS_TOKEN}\"''\n",
+		},
+		{
+			name: "other renderer",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{mustNewWithToken(t, "https://localhost/a", expiring, WithRenderer(Wget))}
+			},
+			opts: []ScriptOption{WithTokenRefresh("$(get-token)")},
+			want: "#!/bin/sh\n\n" +
+				"wget -O - --method 'GET' 'https://localhost/a' --header 'Authorization: Bearer " + expiring + "'\n",
+		},
+		{
+			name: "without token refresh",
+			set: func(t *testing.T) CommandSet {
+				return CommandSet{mustNewWithToken(t, "https://localhost/a", expiring)}
+			},
+			want: "#!/bin/sh\n\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Authorization: Bearer " + expiring + "'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set(t).Script(tt.opts...); got != tt.want {
+				t.Errorf("Script() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_jwtExpiration(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		want   time.Time
+		wantOk bool
+	}{
+		{
+			name:   "exp claim",
+			token:  jwt(`{"exp":1767225600}`),
+			want:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:  "no exp claim",
+			token: jwt(`{"sub":"42"}`),
+		},
+		{
+			name:  "opaque token",
+			token: "s3cr3t",
+		},
+		{
+			name:  "invalid payload",
+			token: "a.!!!.c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := jwtExpiration(tt.token)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("jwtExpiration() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
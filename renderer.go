@@ -0,0 +1,137 @@
+package curling
+
+import (
+	"fmt"
+	"github.com/aoliveti/curling/internal/registry"
+	"net/http"
+	"net/url"
+)
+
+// A Renderer renders a [Command] in an output format.
+//
+// Implementations read the request through [Command.Request]. A Renderer
+// passed to [WithRenderer] must not call [Command.String], which delegates to it.
+type Renderer interface {
+	Render(c *Command) string
+}
+
+// The RendererFunc type is an adapter to allow the use of ordinary functions as renderers.
+type RendererFunc func(c *Command) string
+
+// Render calls f(c).
+func (f RendererFunc) Render(c *Command) string {
+	return f(c)
+}
+
+// The built-in renderers.
+var (
+	// Curl renders the cURL command, it is the default renderer.
+	Curl Renderer = curlRenderer{}
+
+	// PowerShell renders a PowerShell Invoke-WebRequest command, see [Command.ToInvokeWebRequest].
+	PowerShell Renderer = RendererFunc((*Command).ToInvokeWebRequest)
+
+	// Wget renders a wget command, see [Command.ToWget].
+	Wget Renderer = RendererFunc((*Command).ToWget)
+
+	// Fetch renders a JavaScript fetch() call, see [Command.ToFetch].
+	Fetch Renderer = RendererFunc((*Command).ToFetch)
+
+	// Python renders a Python requests snippet, see [Command.ToPythonRequests].
+	Python Renderer = RendererFunc((*Command).ToPythonRequests)
+
+	// Go renders a Go net/http program, see [Command.ToGo].
+	Go Renderer = RendererFunc((*Command).ToGo)
+
+	// Hurl renders a Hurl entry, see [Command.ToHurl].
+	Hurl Renderer = RendererFunc((*Command).ToHurl)
+)
+
+// init registers the built-in renderers as dialects, see the dialect package.
+func init() {
+	for name, r := range map[string]Renderer{
+		"curl":       Curl,
+		"powershell": PowerShell,
+		"wget":       Wget,
+		"fetch":      Fetch,
+		"python":     Python,
+		"go":         Go,
+		"hurl":       Hurl,
+	} {
+		registry.Register(name, r)
+	}
+}
+
+// Render returns the command rendered in the dialect registered under name
+// with the dialect package, such as "wget", regardless of the configured [Renderer].
+// If no dialect is registered under name, Render returns an error.
+func (c *Command) Render(name string) (string, error) {
+	v, ok := registry.Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("unknown dialect %q", name)
+	}
+
+	return v.(Renderer).Render(c), nil
+}
+
+// A Request is the read-only, target-independent model of the HTTP request
+// a [Command] is built from.
+type Request struct {
+	// Method is the request method, GET when the request does not set one.
+	Method string
+
+	// URL is the request URL.
+	URL *url.URL
+
+	// Proto is the protocol version, HTTP/1.1 when the request does not set one.
+	Proto string
+
+	// Header holds the request headers.
+	Header http.Header
+
+	// Body holds the bytes read from the request body.
+	Body []byte
+
+	// HasBody reports whether the request carries a body, even an empty one.
+	HasBody bool
+}
+
+// Request returns a copy of the request model the command is built from.
+func (c *Command) Request() Request {
+	u := *c.request.url
+
+	var body []byte
+	if c.request.hasBody {
+		body = append([]byte{}, c.request.body...)
+	}
+
+	return Request{
+		Method:  c.request.method,
+		URL:     &u,
+		Proto:   c.request.proto,
+		Header:  c.request.header.Clone(),
+		Body:    body,
+		HasBody: c.request.hasBody,
+	}
+}
+
+// curlRenderer is the renderer of cURL commands.
+type curlRenderer struct{}
+
+// Render returns the cURL command.
+func (curlRenderer) Render(c *Command) string {
+	return c.curl()
+}
+
+// curl renders the cURL command.
+func (c *Command) curl() string {
+	c.ensureConstructed()
+
+	return c.decorate(c.join(c.tokens))
+}
+
+// rendersCurl reports whether the command is rendered as a cURL command.
+func (c *Command) rendersCurl() bool {
+	_, ok := c.renderer.(curlRenderer)
+	return c.renderer == nil || ok
+}
@@ -0,0 +1,152 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_String_renderer(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	custom := RendererFunc(func(c *Command) string {
+		r := c.Request()
+		return "http " + r.Method + " " + r.URL.String()
+	})
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "default renderer",
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "curl renderer",
+			opts: []Option{WithRenderer(Curl)},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "wget renderer",
+			opts: []Option{WithRenderer(Wget)},
+			want: "wget -O - --method 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "powershell renderer",
+			opts: []Option{WithRenderer(PowerShell)},
+			want: "Invoke-WebRequest -Method 'GET' -Uri 'https://localhost/test'",
+		},
+		{
+			name: "custom renderer",
+			opts: []Option{WithRenderer(custom)},
+			want: "http GET https://localhost/test",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(&http.Request{URL: testUrl}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_Request(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL:    testUrl,
+		Header: http.Header{"X-Key": {"1"}},
+		Body:   readCloser("key=value"),
+	}
+
+	c, err := NewFromRequest(r)
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	want := Request{
+		Method:  http.MethodPost,
+		URL:     testUrl,
+		Proto:   "HTTP/1.1",
+		Header:  http.Header{"X-Key": {"1"}},
+		Body:    []byte("key=value"),
+		HasBody: true,
+	}
+
+	got := c.Request()
+	if !cmp.Equal(got, want) {
+		t.Errorf("Request() diff = %v", cmp.Diff(got, want))
+	}
+
+	got.URL.Path = "changed"
+	got.Header.Set("X-Key", "2")
+	got.Body[0] = 'K'
+
+	if !cmp.Equal(c.Request(), want) {
+		t.Errorf("Request() is not a copy, diff = %v", cmp.Diff(c.Request(), want))
+	}
+}
+
+func TestCommand_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		opts    []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "curl",
+			dialect: "curl",
+			opts:    []Option{WithRenderer(Wget)},
+			want:    "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:    "wget",
+			dialect: "wget",
+			want:    "wget -O - --method 'GET' 'https://localhost/test'",
+		},
+		{
+			name:    "powershell",
+			dialect: "powershell",
+			want:    "Invoke-WebRequest -Method 'GET' -Uri 'https://localhost/test'",
+		},
+		{
+			name:    "unknown dialect",
+			dialect: "httpie",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			got, err := c.Render(tt.dialect)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Render() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
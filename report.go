@@ -0,0 +1,77 @@
+package curling
+
+// A Report aggregates the findings returned by [Command.Validate] across
+// a [CommandSet], so that batch conversion jobs can check the fidelity of
+// the commands. It is meant to be encoded as JSON.
+type Report struct {
+	// Commands is the number of commands.
+	Commands int `json:"commands"`
+
+	// Faithful is the number of commands without findings.
+	Faithful int `json:"faithful"`
+
+	// Fidelity is the ratio of faithful commands, 1 when there are no commands.
+	Fidelity float64 `json:"fidelity"`
+
+	// Codes counts the findings by code.
+	Codes map[WarningCode]int `json:"codes"`
+
+	// Findings lists the commands with findings, in order.
+	Findings []CommandFindings `json:"findings"`
+}
+
+// CommandFindings are the findings about a command of a [Report].
+type CommandFindings struct {
+	// Index is the position of the command in the set.
+	Index int `json:"index"`
+
+	// Method is the request method.
+	Method string `json:"method"`
+
+	// URL is the request URL, with the password redacted.
+	URL string `json:"url"`
+
+	// Warnings are the findings returned by [Command.Validate].
+	Warnings []Warning `json:"warnings"`
+}
+
+// Report returns the findings of the commands, aggregated.
+func (s CommandSet) Report() Report {
+	r := Report{
+		Commands: len(s),
+		Fidelity: 1,
+		Codes:    map[WarningCode]int{},
+		Findings: []CommandFindings{},
+	}
+
+	for i, c := range s {
+		warnings := c.Validate()
+		if len(warnings) == 0 {
+			r.Faithful++
+			continue
+		}
+
+		for _, w := range warnings {
+			r.Codes[w.Code]++
+		}
+
+		r.Findings = append(r.Findings, CommandFindings{
+			Index:    i,
+			Method:   c.request.method,
+			URL:      c.request.url.Redacted(),
+			Warnings: warnings,
+		})
+	}
+
+	if r.Commands > 0 {
+		r.Fidelity = float64(r.Faithful) / float64(r.Commands)
+	}
+
+	return r
+}
+
+// Passes reports whether the fidelity of the commands reaches threshold,
+// a ratio between 0 and 1.
+func (r Report) Passes(threshold float64) bool {
+	return r.Fidelity >= threshold
+}
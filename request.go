@@ -0,0 +1,348 @@
+package curling
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// formContentType is the standard content type of url-encoded forms.
+const formContentType = "application/x-www-form-urlencoded"
+
+// parsedRequest is the target-independent model of an HTTP request.
+// It is produced once from the [http.Request] and shared by the cURL
+// builders and by every alternative output format.
+type parsedRequest struct {
+	// method is the request method, GET when the request does not set one.
+	method string
+
+	// url is a copy of the request URL.
+	url *url.URL
+
+	// proto is the protocol version, HTTP/1.1 when the request does not set one.
+	proto string
+
+	// header holds the request headers.
+	header http.Header
+
+	// trailer holds the trailers declared by the request.
+	trailer http.Header
+
+	// body holds the bytes read from the request body.
+	body []byte
+
+	// deadline is the deadline of the request context, zero when it has none.
+	deadline time.Time
+
+	// bodySize is the size of the original request body, which may be larger than body.
+	bodySize int
+
+	// readErr is the error met reading the request body, of which body holds the bytes read before it.
+	readErr error
+
+	// hasBody reports whether the request carries a body, even an empty one.
+	hasBody bool
+
+	// formDropped reports whether form values were left out of the body
+	// because the declared content type is not url-encoded.
+	formDropped bool
+}
+
+// clone returns a copy of p whose URL and headers can be changed independently.
+// The body is shared, since it is replaced rather than changed in place.
+func (p parsedRequest) clone() parsedRequest {
+	u := *p.url
+	p.url = &u
+	p.header = p.header.Clone()
+
+	return p
+}
+
+// headerField is a single request header with its values joined by comma.
+type headerField struct {
+	key   string
+	value string
+}
+
+// headerSeparator separates the key from the value in the wire form.
+const headerSeparator = ": "
+
+// String returns the header in the "Key: value" wire form.
+func (h headerField) String() string {
+	return h.key + headerSeparator + h.value
+}
+
+// compare compares the wire forms of h and other, without building them.
+func (h headerField) compare(other headerField) int {
+	n := min(h.len(), other.len())
+	for i := 0; i < n; i++ {
+		if a, b := h.byteAt(i), other.byteAt(i); a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return h.len() - other.len()
+}
+
+// len returns the length of the wire form of h.
+func (h headerField) len() int {
+	return len(h.key) + len(headerSeparator) + len(h.value)
+}
+
+// byteAt returns the byte at index i of the wire form of h.
+func (h headerField) byteAt(i int) byte {
+	if i < len(h.key) {
+		return h.key[i]
+	}
+
+	i -= len(h.key)
+	if i < len(headerSeparator) {
+		return headerSeparator[i]
+	}
+
+	return h.value[i-len(headerSeparator)]
+}
+
+// parseRequest reads r into a parsedRequest.
+// If the request URL is nil, parseRequest returns an error.
+// If parseRequest can't read the request body, it returns an error along with
+// the request holding the bytes read before it, and r's body keeps those bytes
+// in front of the unread rest.
+// The request body is restored so that r can be sent afterward.
+func parseRequest(r *http.Request) (parsedRequest, error) {
+	if r.URL == nil {
+		return parsedRequest{}, fmt.Errorf("request url is nil")
+	}
+
+	u := *r.URL
+
+	p := parsedRequest{
+		method: r.Method,
+		url:    &u,
+		proto:  r.Proto,
+		header: r.Header.Clone(),
+	}
+
+	if deadline, ok := r.Context().Deadline(); ok {
+		p.deadline = deadline
+	}
+
+	if len(r.Trailer) > 0 {
+		p.trailer = r.Trailer.Clone()
+	}
+
+	if p.method == "" {
+		p.method = http.MethodGet
+	}
+
+	if p.proto == "" {
+		p.proto = "HTTP/1.1"
+	}
+
+	if p.header == nil {
+		p.header = http.Header{}
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		p.parseForm(r.PostForm)
+		return p, nil
+	}
+
+	b := bodyBuffers.Get().(*bytes.Buffer)
+	defer putBodyBuffer(b)
+
+	_, err := b.ReadFrom(r.Body)
+
+	// The pooled buffer is reused, so the body gets its own copy of the bytes.
+	p.body = append([]byte{}, b.Bytes()...)
+
+	p.bodySize = len(p.body)
+	p.hasBody = true
+
+	if err != nil {
+		// The bytes read are put back in front of the rest of the body,
+		// which keeps its closer.
+		body := r.Body
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(p.body), body), body}
+
+		p.readErr = fmt.Errorf("reading bytes from request body: %w", err)
+		return p, p.readErr
+	}
+
+	// Reset request body for potential re-reads
+	r.Body = io.NopCloser(bytes.NewReader(p.body))
+
+	return p, nil
+}
+
+// maxPooledBufferSize is the capacity above which body buffers are not pooled,
+// so that a single large body isn't kept in memory.
+const maxPooledBufferSize = 64 << 10
+
+// bodyBuffers pools the buffers the request bodies are read into.
+var bodyBuffers = sync.Pool{
+	New: func() any {
+		return new(bytes.Buffer)
+	},
+}
+
+// putBodyBuffer resets b and returns it to the pool, unless it grew too large.
+func putBodyBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	b.Reset()
+	bodyBuffers.Put(b)
+}
+
+// parseForm reconstructs the body of a request that carries its data only in form.
+// The declared Content-Type is kept, so vendor types such as
+// application/vnd.api+x-www-form-urlencoded are preserved; when the request
+// declares none, the standard form content type is added.
+// Forms declared with a content type that is not url-encoded are left out.
+func (p *parsedRequest) parseForm(form url.Values) {
+	if len(form) == 0 {
+		return
+	}
+
+	contentType := p.header.Get("Content-Type")
+	if contentType == "" {
+		p.header.Set("Content-Type", formContentType)
+	} else if !isFormContentType(contentType) {
+		p.formDropped = true
+		return
+	}
+
+	p.body = []byte(form.Encode())
+	p.bodySize = len(p.body)
+	p.hasBody = true
+}
+
+// truncateBody cuts the body to the first size bytes, keeping bodySize unchanged.
+// Non-positive sizes leave the body as is.
+func (p *parsedRequest) truncateBody(size int) {
+	if size <= 0 || len(p.body) <= size {
+		return
+	}
+
+	p.body = p.body[:size]
+}
+
+// formFields returns the fields of a url-encoded body as --data-urlencode
+// arguments: the name as it appears in the body and the decoded value,
+// or the decoded value preceded by = for fields without name.
+// It reports false when the body is not declared url-encoded or can't be
+// split into fields that cURL joins back into an equivalent body.
+func (p *parsedRequest) formFields() ([]string, bool) {
+	if !isFormContentType(p.header.Get("Content-Type")) || len(p.body) == 0 || len(p.body) < p.bodySize {
+		return nil, false
+	}
+
+	pairs := strings.Split(string(p.body), "&")
+	fields := make([]string, 0, len(pairs))
+
+	for _, pair := range pairs {
+		if pair == "" {
+			return nil, false
+		}
+
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			name, value = "", pair
+		}
+
+		decoded, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, false
+		}
+
+		fields = append(fields, name+"="+decoded)
+	}
+
+	return fields, true
+}
+
+// headerFields returns the request headers with canonical keys,
+// sorted by their "Key: value" form.
+func (p *parsedRequest) headerFields() []headerField {
+	fields := make([]headerField, 0, len(p.header))
+	for key, values := range p.header {
+		fields = append(fields, headerField{
+			key:   http.CanonicalHeaderKey(key),
+			value: strings.Join(values, ", "),
+		})
+	}
+
+	slices.SortFunc(fields, headerField.compare)
+
+	return fields
+}
+
+// rawHeaderFields returns one field for each request header value, with the keys
+// as they are stored in the request, sorted by key. The values of a key keep their order.
+func (p *parsedRequest) rawHeaderFields() []headerField {
+	var fields []headerField
+	for key, values := range p.header {
+		for _, value := range values {
+			fields = append(fields, headerField{key: key, value: value})
+		}
+	}
+
+	slices.SortStableFunc(fields, func(a, b headerField) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	return fields
+}
+
+// isFormContentType reports whether contentType declares a url-encoded body,
+// either the standard type or a structured syntax suffix of it.
+func isFormContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == formContentType || strings.HasSuffix(mediaType, "+x-www-form-urlencoded")
+}
+
+// withoutUserinfo returns copies of the request URL and headers in which
+// the credentials embedded in the URL are moved to a basic Authorization header,
+// for clients that don't accept them as part of the URL.
+// An Authorization header already set by the request takes precedence.
+func (p *parsedRequest) withoutUserinfo() (*url.URL, http.Header) {
+	u := *p.url
+	header := p.header.Clone()
+
+	if u.User == nil {
+		return &u, header
+	}
+
+	if header.Get("Authorization") == "" {
+		password, _ := u.User.Password()
+		header.Set("Authorization", basicAuthHeader(u.User.Username(), password))
+	}
+	u.User = nil
+
+	return &u, header
+}
+
+// basicAuthHeader returns the basic Authorization header value of the credentials.
+func basicAuthHeader(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
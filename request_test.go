@@ -0,0 +1,286 @@
+package curling
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func Test_parseRequest(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	tests := []struct {
+		name    string
+		r       *http.Request
+		want    parsedRequest
+		wantErr bool
+	}{
+		{
+			name: "invalid url",
+			r: &http.Request{
+				URL: nil,
+			},
+			wantErr: true,
+		},
+		{
+			name: "error reading body",
+			r: &http.Request{
+				URL:  testUrl,
+				Body: readerWithError{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "default method",
+			r: &http.Request{
+				URL: testUrl,
+			},
+			want: parsedRequest{
+				method: http.MethodGet,
+				url:    testUrl,
+				proto:  "HTTP/1.1",
+				header: http.Header{},
+			},
+		},
+		{
+			name: "empty body",
+			r: &http.Request{
+				Method: http.MethodPost,
+				URL:    testUrl,
+				Body:   readCloser(""),
+			},
+			want: parsedRequest{
+				method:  http.MethodPost,
+				url:     testUrl,
+				proto:   "HTTP/1.1",
+				header:  http.Header{},
+				body:    []byte{},
+				hasBody: true,
+			},
+		},
+		{
+			name: "body",
+			r: &http.Request{
+				Method: http.MethodPost,
+				URL:    testUrl,
+				Header: http.Header{"X-Key": {"1"}},
+				Body:   readCloser("key=value"),
+			},
+			want: parsedRequest{
+				method:   http.MethodPost,
+				url:      testUrl,
+				proto:    "HTTP/1.1",
+				header:   http.Header{"X-Key": {"1"}},
+				body:     []byte("key=value"),
+				bodySize: 9,
+				hasBody:  true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRequest(tt.r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			optUnexported := cmp.AllowUnexported(parsedRequest{})
+			if !cmp.Equal(got, tt.want, optUnexported) {
+				t.Errorf("parseRequest() diff = %v", cmp.Diff(got, tt.want, optUnexported))
+			}
+
+			if tt.r.Body != nil {
+				b, err := io.ReadAll(tt.r.Body)
+				if err != nil {
+					t.Fatalf("reading restored body: %v", err)
+				}
+				if string(b) != string(tt.want.body) {
+					t.Errorf("restored body = %q, want %q", b, tt.want.body)
+				}
+			}
+		})
+	}
+}
+
+// A failingBody returns err once its reader is drained, and records its closing.
+type failingBody struct {
+	io.Reader
+	err    error
+	closed bool
+}
+
+func (b *failingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		err = b.err
+	}
+	return n, err
+}
+
+func (b *failingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func Test_parseRequest_readError(t *testing.T) {
+	errRead := errors.New("connection reset")
+	body := &failingBody{Reader: strings.NewReader("partial"), err: errRead}
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Scheme: "https", Host: "localhost"},
+		Body:   body,
+	}
+
+	got, err := parseRequest(r)
+	if !errors.Is(err, errRead) {
+		t.Fatalf("parseRequest() error = %v, want %v", err, errRead)
+	}
+	if string(got.body) != "partial" {
+		t.Errorf("parseRequest() body = %q, want %q", got.body, "partial")
+	}
+
+	b, err := io.ReadAll(r.Body)
+	if string(b) != "partial" {
+		t.Errorf("restored body = %q, want %q", b, "partial")
+	}
+	if !errors.Is(err, errRead) {
+		t.Errorf("reading restored body error = %v, want %v", err, errRead)
+	}
+
+	if err := r.Body.Close(); err != nil {
+		t.Fatalf("closing restored body: %v", err)
+	}
+	if !body.closed {
+		t.Error("restored body didn't close the original body")
+	}
+}
+
+func Test_parsedRequest_headerFields(t *testing.T) {
+	p := parsedRequest{
+		header: http.Header{
+			"x-key-z": {"foo", "bar"},
+			"X-Key-A": {"baz"},
+		},
+	}
+
+	want := []headerField{
+		{key: "X-Key-A", value: "baz"},
+		{key: "X-Key-Z", value: "foo, bar"},
+	}
+
+	got := p.headerFields()
+	if !cmp.Equal(got, want, cmp.AllowUnexported(headerField{})) {
+		t.Errorf("headerFields() diff = %v", cmp.Diff(got, want, cmp.AllowUnexported(headerField{})))
+	}
+}
+
+func Test_parseRequest_form(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	form := url.Values{"b": {"2"}, "a": {"1 & 2"}}
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		want parsedRequest
+	}{
+		{
+			name: "without content type",
+			r: &http.Request{
+				Method:   http.MethodPost,
+				URL:      testUrl,
+				PostForm: form,
+			},
+			want: parsedRequest{
+				method:   http.MethodPost,
+				url:      testUrl,
+				proto:    "HTTP/1.1",
+				header:   http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+				body:     []byte("a=1+%26+2&b=2"),
+				bodySize: 13,
+				hasBody:  true,
+			},
+		},
+		{
+			name: "vendor content type",
+			r: &http.Request{
+				Method:   http.MethodPost,
+				URL:      testUrl,
+				Header:   http.Header{"Content-Type": {"application/vnd.api+x-www-form-urlencoded; charset=utf-8"}},
+				PostForm: form,
+			},
+			want: parsedRequest{
+				method:   http.MethodPost,
+				url:      testUrl,
+				proto:    "HTTP/1.1",
+				header:   http.Header{"Content-Type": {"application/vnd.api+x-www-form-urlencoded; charset=utf-8"}},
+				body:     []byte("a=1+%26+2&b=2"),
+				bodySize: 13,
+				hasBody:  true,
+			},
+		},
+		{
+			name: "not url-encoded content type",
+			r: &http.Request{
+				Method:   http.MethodPost,
+				URL:      testUrl,
+				Header:   http.Header{"Content-Type": {"multipart/form-data; boundary=x"}},
+				PostForm: form,
+			},
+			want: parsedRequest{
+				method:      http.MethodPost,
+				url:         testUrl,
+				proto:       "HTTP/1.1",
+				header:      http.Header{"Content-Type": {"multipart/form-data; boundary=x"}},
+				formDropped: true,
+			},
+		},
+		{
+			name: "body takes precedence",
+			r: &http.Request{
+				Method:   http.MethodPost,
+				URL:      testUrl,
+				Body:     readCloser("c=3"),
+				PostForm: form,
+			},
+			want: parsedRequest{
+				method:   http.MethodPost,
+				url:      testUrl,
+				proto:    "HTTP/1.1",
+				header:   http.Header{},
+				body:     []byte("c=3"),
+				bodySize: 3,
+				hasBody:  true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRequest(tt.r)
+			if err != nil {
+				t.Fatalf("parseRequest() error = %v", err)
+			}
+
+			optUnexported := cmp.AllowUnexported(parsedRequest{})
+			if !cmp.Equal(got, tt.want, optUnexported) {
+				t.Errorf("parseRequest() diff = %v", cmp.Diff(got, tt.want, optUnexported))
+			}
+		})
+	}
+}
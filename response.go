@@ -0,0 +1,131 @@
+package curling
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// NewFromResponse returns a new [Command] for each request of the redirect chain
+// that led to resp, from the first request to the one that produced resp.
+// Request bodies are read again through [http.Request.GetBody] when available,
+// since the client already consumed them.
+// If resp has no request, NewFromResponse returns an error.
+// If a command can't be built, NewFromResponse returns an error.
+func NewFromResponse(resp *http.Response, opts ...Option) ([]*Command, error) {
+	if resp == nil || resp.Request == nil {
+		return nil, fmt.Errorf("response request is nil")
+	}
+
+	requests := redirectChain(resp)
+
+	commands := make([]*Command, 0, len(requests))
+	for i, r := range requests {
+		r, err := replayableRequest(r)
+		if err != nil {
+			return nil, fmt.Errorf("redirect hop %d: %w", i, err)
+		}
+
+		c, err := NewFromRequest(r, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("redirect hop %d: %w", i, err)
+		}
+
+		commands = append(commands, c)
+	}
+
+	return commands, nil
+}
+
+// NewFromRedirects returns a single [Command] replaying the redirect chain that
+// led to resp: the command sends the first request with -L, --location and lists
+// each hop, with the status it got, as comment lines above the command.
+// It is handier than [NewFromResponse], which returns a command for each hop,
+// but cURL may not follow the chain as the client did, such as when a redirect
+// switches POST to GET: the mismatches are reported by [Command.Validate].
+// If resp has no request, NewFromRedirects returns an error.
+// If the command can't be built, NewFromRedirects returns an error.
+func NewFromRedirects(resp *http.Response, opts ...Option) (*Command, error) {
+	if resp == nil || resp.Request == nil {
+		return nil, fmt.Errorf("response request is nil")
+	}
+
+	requests := redirectChain(resp)
+
+	r, err := replayableRequest(requests[0])
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Command{}
+	if err := c.build(r, append(slices.Clip(opts), WithFollowRedirects())...); err != nil {
+		return nil, err
+	}
+
+	for i, hop := range requests {
+		status := resp.Status
+		if i+1 < len(requests) {
+			status = requests[i+1].Response.Status
+		}
+
+		u := *hop.URL
+		c.scrubURL(&u)
+		c.redirects = append(c.redirects, fmt.Sprintf("Hop %d: %s %s -> %s", i+1, hop.Method, u.String(), status))
+	}
+
+	for _, hop := range requests[1:] {
+		if hop.Method != c.request.method {
+			c.warn(WarningRedirectMethod, "the method changes to %s along the redirect chain, while cURL keeps %s", hop.Method, c.request.method)
+			break
+		}
+	}
+
+	return c, nil
+}
+
+// redirectChain returns the requests of the redirect chain that led to resp,
+// from the first request to the one that produced resp.
+func redirectChain(resp *http.Response) []*http.Request {
+	var requests []*http.Request
+	for r := resp.Request; r != nil; {
+		requests = append(requests, r)
+
+		if r.Response == nil {
+			break
+		}
+		r = r.Response.Request
+	}
+
+	slices.Reverse(requests)
+
+	return requests
+}
+
+// redirectBlock returns the hops of the redirect chain as comment lines.
+func (c *Command) redirectBlock() string {
+	var b strings.Builder
+	for _, hop := range c.redirects {
+		fmt.Fprintf(&b, "%s %s\n", c.commentPrefix(), hop)
+	}
+
+	return b.String()
+}
+
+// replayableRequest returns a shallow copy of r with a fresh body obtained
+// from GetBody, or r itself when the body can't be obtained again.
+func replayableRequest(r *http.Request) (*http.Request, error) {
+	if r.GetBody == nil || r.Body == nil || r.Body == http.NoBody {
+		return r, nil
+	}
+
+	body, err := r.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("getting request body: %w", err)
+	}
+
+	c := *r
+	c.Body = body
+
+	return &c, nil
+}
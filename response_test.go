@@ -0,0 +1,172 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_NewFromResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusTemporaryRedirect)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/a", strings.NewReader("key=value"))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	got, err := NewFromResponse(resp)
+	if err != nil {
+		t.Fatalf("NewFromResponse() error = %v", err)
+	}
+
+	commands := make([]string, 0, len(got))
+	for _, c := range got {
+		commands = append(commands, c.String())
+	}
+
+	// The 307 redirect keeps method and body, the 302 one switches to GET.
+	want := []string{
+		"curl -X 'POST' '" + server.URL + "/a' -d 'key=value'",
+		"curl -X 'POST' '" + server.URL + "/b' -H 'Referer: " + server.URL + "/a' -d 'key=value'",
+		"curl -X 'GET' '" + server.URL + "/c' -H 'Referer: " + server.URL + "/b'",
+	}
+
+	if !cmp.Equal(commands, want) {
+		t.Errorf("NewFromResponse() diff = %v", cmp.Diff(commands, want))
+	}
+}
+
+func Test_NewFromResponse_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		resp *http.Response
+	}{
+		{
+			name: "nil response",
+			resp: nil,
+		},
+		{
+			name: "nil request",
+			resp: &http.Response{},
+		},
+		{
+			name: "invalid request",
+			resp: &http.Response{Request: &http.Request{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFromResponse(tt.resp); err == nil {
+				t.Errorf("NewFromResponse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func Test_NewFromRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusTemporaryRedirect)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		want         string
+		wantWarnings []Warning
+	}{
+		{
+			name:   "method kept",
+			method: http.MethodGet,
+			path:   "/a",
+			want: "# Hop 1: GET " + server.URL + "/a -> 307 Temporary Redirect\n" +
+				"# Hop 2: GET " + server.URL + "/b -> 302 Found\n" +
+				"# Hop 3: GET " + server.URL + "/c -> 200 OK\n" +
+				"curl -L -X 'GET' '" + server.URL + "/a'",
+		},
+		{
+			name:   "method switched",
+			method: http.MethodPost,
+			path:   "/b",
+			want: "# Hop 1: POST " + server.URL + "/b -> 302 Found\n" +
+				"# Hop 2: GET " + server.URL + "/c -> 200 OK\n" +
+				"curl -L -X 'POST' '" + server.URL + "/b' -d 'key=value'",
+			wantWarnings: []Warning{
+				{Code: WarningRedirectMethod, Message: "the method changes to GET along the redirect chain, while cURL keeps POST"},
+			},
+		},
+		{
+			name:   "no redirect",
+			method: http.MethodGet,
+			path:   "/c",
+			want: "# Hop 1: GET " + server.URL + "/c -> 200 OK\n" +
+				"curl -L -X 'GET' '" + server.URL + "/c'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.method == http.MethodPost {
+				body = strings.NewReader("key=value")
+			}
+
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, body)
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatalf("do request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			c, err := NewFromRedirects(resp)
+			if err != nil {
+				t.Fatalf("NewFromRedirects() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+			if got := c.Validate(); !cmp.Equal(got, tt.wantWarnings) {
+				t.Errorf("Validate() diff = %v", cmp.Diff(got, tt.wantWarnings))
+			}
+		})
+	}
+}
+
+func Test_NewFromRedirects_errors(t *testing.T) {
+	for _, resp := range []*http.Response{nil, {}, {Request: &http.Request{}}} {
+		if _, err := NewFromRedirects(resp); err == nil {
+			t.Errorf("NewFromRedirects() error = nil, want error")
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package curling
+
+import (
+	"strconv"
+	"time"
+)
+
+// WithRetry sets the flag --retry, so that cURL retries the request up to count
+// times on transient errors, such as timeouts and 5xx responses.
+// Non-positive counts will be silently ignored.
+func WithRetry(count int) Option {
+	return func(curling *Command) {
+		if count <= 0 {
+			return
+		}
+
+		curling.retry = count
+	}
+}
+
+// WithRetryDelay sets the flag --retry-delay, the time cURL waits between
+// retries, rounded up to the second, instead of its exponential backoff.
+// It takes effect with [WithRetry]. Non-positive delays will be silently ignored.
+func WithRetryDelay(d time.Duration) Option {
+	return func(curling *Command) {
+		if d <= 0 {
+			return
+		}
+
+		curling.retryDelay = d
+	}
+}
+
+// WithRetryMaxTime sets the flag --retry-max-time, the time after which cURL
+// stops retrying, rounded up to the second.
+// It takes effect with [WithRetry]. Non-positive times will be silently ignored.
+func WithRetryMaxTime(d time.Duration) Option {
+	return func(curling *Command) {
+		if d <= 0 {
+			return
+		}
+
+		curling.retryMaxTime = d
+	}
+}
+
+// WithRetryAllErrors sets the flag --retry-all-errors, so that cURL retries
+// on every error, including 4xx responses with -f, --fail.
+// It takes effect with [WithRetry].
+func WithRetryAllErrors() Option {
+	return func(curling *Command) {
+		curling.retryAllErrors = true
+	}
+}
+
+// retryOptions returns the options driving the retries, none without retries.
+func (c *Command) retryOptions() []word {
+	if c.retry == 0 {
+		return nil
+	}
+
+	s := []word{literal(c.option(flagRetry)), literal(strconv.Itoa(c.retry))}
+
+	if c.retryDelay > 0 {
+		s = append(s, literal(c.option(flagRetryDelay)), literal(wholeSeconds(c.retryDelay)))
+	}
+
+	if c.retryMaxTime > 0 {
+		s = append(s, literal(c.option(flagRetryMaxTime)), literal(wholeSeconds(c.retryMaxTime)))
+	}
+
+	if c.retryAllErrors {
+		s = append(s, literal(c.option(flagRetryAllErrors)))
+	}
+
+	return s
+}
@@ -0,0 +1,48 @@
+package curling
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "retry",
+			opts: []Option{WithRetry(3)},
+			want: "curl --retry 3 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "retry family",
+			opts: []Option{
+				WithRequestTimeout(10), WithRetry(5), WithRetryDelay(1500 * time.Millisecond),
+				WithRetryMaxTime(time.Minute), WithRetryAllErrors(),
+			},
+			want: "curl -m 10 --retry 5 --retry-delay 2 --retry-max-time 60 --retry-all-errors -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "without retry",
+			opts: []Option{WithRetryDelay(time.Second), WithRetryMaxTime(time.Minute), WithRetryAllErrors()},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "non-positive values",
+			opts: []Option{WithRetry(2), WithRetry(-1), WithRetryDelay(-time.Second), WithRetryMaxTime(0)},
+			want: "curl --retry 2 -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// A route applies its options to the requests it matches.
+type route struct {
+	matches func(r *http.Request) bool
+	opts    []Option
+}
+
+// OnHost makes [Transport] and [Middleware] apply opts, after the other options,
+// to the requests whose host matches pattern, so that different endpoints get
+// different redaction or verbosity from a single wrapper. For example:
+//
+//	curling.OnHost("*.internal", curling.WithMinimal())
+//
+// The pattern uses the [path.Match] syntax and is matched against the host
+// without the port. Malformed patterns never match.
+// [NewFromRequest] and the other constructors ignore it.
+func OnHost(pattern string, opts ...Option) Option {
+	return onRoute(func(r *http.Request) bool {
+		ok, _ := path.Match(pattern, requestHost(r))
+		return ok
+	}, opts)
+}
+
+// OnPath makes [Transport] and [Middleware] apply opts, after the other options,
+// to the requests whose URL path matches pattern. For example:
+//
+//	curling.OnPath("/admin/*", curling.WithRedactedHeaders("Cookie"))
+//
+// The pattern uses the [path.Match] syntax, except that a trailing /* also
+// matches the nested paths. Malformed patterns never match.
+// [NewFromRequest] and the other constructors ignore it.
+func OnPath(pattern string, opts ...Option) Option {
+	nested := strings.HasSuffix(pattern, "/*")
+
+	return onRoute(func(r *http.Request) bool {
+		p := r.URL.Path
+		ok, _ := path.Match(pattern, p)
+
+		// A trailing /* matches the nested paths through their ancestors.
+		for nested && !ok && p != "/" && p != "." && p != "" {
+			p = path.Dir(p)
+			ok, _ = path.Match(pattern, p)
+		}
+
+		return ok
+	}, opts)
+}
+
+// onRoute returns an option adding a route made of matches and opts.
+func onRoute(matches func(r *http.Request) bool, opts []Option) Option {
+	return func(curling *Command) {
+		curling.routes = append(curling.routes, route{matches: matches, opts: opts})
+	}
+}
+
+// requestHost returns the host of r without the port, taken from the URL
+// or, for server requests, from the Host header.
+func requestHost(r *http.Request) string {
+	host := r.URL.Host
+	if host == "" {
+		host = r.Host
+	}
+
+	u := url.URL{Host: host}
+	return u.Hostname()
+}
+
+// requestOptions returns opts followed by the options of the routes matching r,
+// and whether r passes the resulting predicate set with [WithPredicate], if any.
+func requestOptions(r *http.Request, opts []Option) ([]Option, bool) {
+	var c Command
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	for _, rt := range c.routes {
+		if !rt.matches(r) {
+			continue
+		}
+
+		opts = append(slices.Clip(opts), rt.opts...)
+		for _, opt := range rt.opts {
+			opt(&c)
+		}
+	}
+
+	return opts, c.predicate == nil || c.predicate(r)
+}
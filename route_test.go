@@ -0,0 +1,141 @@
+package curling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOnHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		url     string
+		host    string
+		want    bool
+	}{
+		{name: "exact", pattern: "api.example.com", url: "https://api.example.com/test", want: true},
+		{name: "wildcard", pattern: "*.internal", url: "http://billing.internal:8080/test", want: true},
+		{name: "nested subdomain", pattern: "*.internal", url: "http://eu.billing.internal/test", want: true},
+		{name: "other host", pattern: "*.internal", url: "https://example.com/test", want: false},
+		{name: "server request", pattern: "*.internal", url: "/test", host: "billing.internal:8080", want: true},
+		{name: "ipv6", pattern: "::1", url: "http://[::1]:8080/test", want: true},
+		{name: "malformed pattern", pattern: "[", url: "https://example.com/test", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+
+			var c Command
+			OnHost(tt.pattern)(&c)
+
+			if got := c.routes[0].matches(r); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{name: "exact", pattern: "/health", path: "/health", want: true},
+		{name: "wildcard", pattern: "/admin/*", path: "/admin/users", want: true},
+		{name: "nested path", pattern: "/admin/*", path: "/admin/users/42", want: true},
+		{name: "parent path", pattern: "/admin/*", path: "/admin", want: false},
+		{name: "other path", pattern: "/admin/*", path: "/api/users", want: false},
+		{name: "inner wildcard", pattern: "/api/*/items", path: "/api/v1/items", want: true},
+		{name: "inner wildcard nested path", pattern: "/api/*/items", path: "/api/v1/items/42", want: false},
+		{name: "malformed pattern", pattern: "/[", path: "/[", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+
+			var c Command
+			OnPath(tt.pattern)(&c)
+
+			if got := c.routes[0].matches(r); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware_routes(t *testing.T) {
+	opts := []Option{
+		WithLongForm(),
+		OnHost("*.internal", WithMinimal()),
+		OnPath("/admin/*", WithRedactedHeaders("Authorization")),
+		OnPath("/health", WithPredicate(func(r *http.Request) bool { return false })),
+	}
+
+	tests := []struct {
+		name   string
+		host   string
+		path   string
+		want   string
+		wantOk bool
+	}{
+		{
+			name:   "no route",
+			host:   "example.com",
+			path:   "/test",
+			want:   "curl --request 'GET' 'http://example.com/test' --header 'Authorization: Bearer s3cr3t'",
+			wantOk: true,
+		},
+		{
+			name:   "host route",
+			host:   "billing.internal",
+			path:   "/test",
+			want:   "export AUTHORIZATION='********'\ncurl 'http://billing.internal/test' --header 'Authorization: '\"${AUTHORIZATION}\"",
+			wantOk: true,
+		},
+		{
+			name:   "path route",
+			host:   "example.com",
+			path:   "/admin/users/42",
+			want:   "export AUTHORIZATION='********'\ncurl --request 'GET' 'http://example.com/admin/users/42' --header 'Authorization: '\"${AUTHORIZATION}\"",
+			wantOk: true,
+		},
+		{
+			name:   "route predicate",
+			host:   "example.com",
+			path:   "/health",
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.Host = tt.host
+			r.Header.Set("Authorization", "Bearer s3cr3t")
+
+			var c *Command
+			var ok bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c, ok = FromContext(r.Context())
+			})
+
+			Middleware(next, opts...).ServeHTTP(httptest.NewRecorder(), r)
+
+			if ok != tt.wantOk {
+				t.Fatalf("FromContext() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
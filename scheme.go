@@ -0,0 +1,58 @@
+package curling
+
+import "net"
+
+// WithForceHTTPS rewrites http URLs to https, for requests captured behind a load
+// balancer terminating TLS that must be replayed through the TLS edge.
+// The default port 80 is dropped, other ports are kept. It replaces [WithForceHTTP].
+func WithForceHTTPS() Option {
+	return func(curling *Command) {
+		curling.forceScheme = "https"
+	}
+}
+
+// WithForceHTTP rewrites https URLs to http, for replaying requests against
+// a local server without TLS. The default port 443 is dropped, other ports
+// are kept. It replaces [WithForceHTTPS].
+func WithForceHTTP() Option {
+	return func(curling *Command) {
+		curling.forceScheme = "http"
+	}
+}
+
+// WithInsecureOnRewrite enables the option -k, --insecure only when
+// [WithForceHTTPS] rewrites the URL, since the TLS edge reached this way
+// may not present a certificate valid for the host.
+func WithInsecureOnRewrite() Option {
+	return func(curling *Command) {
+		curling.insecureOnRewrite = true
+	}
+}
+
+// defaultPorts holds the default port of each scheme.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// rewriteScheme rewrites the request URL to the scheme set with WithForceHTTPS
+// or WithForceHTTP, dropping the default port of the original scheme.
+func (c *Command) rewriteScheme() {
+	u := c.request.url
+	if c.forceScheme == "" || u.Scheme == c.forceScheme || defaultPorts[u.Scheme] == "" {
+		return
+	}
+
+	if u.Port() == defaultPorts[u.Scheme] {
+		u.Host = u.Hostname()
+		if ip := net.ParseIP(u.Host); ip != nil && ip.To4() == nil {
+			u.Host = "[" + u.Host + "]"
+		}
+	}
+	u.Scheme = c.forceScheme
+
+	if c.forceScheme == "https" && c.insecureOnRewrite && !c.insecure {
+		c.insecure = true
+		c.insecureFromRewrite = true
+	}
+}
@@ -0,0 +1,88 @@
+package curling
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithForceHTTPS(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawurl string
+		opts   []Option
+		want   string
+	}{
+		{
+			name:   "http",
+			rawurl: "http://localhost/test",
+			opts:   []Option{WithForceHTTPS()},
+			want:   "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:   "default port dropped",
+			rawurl: "http://localhost:80/test",
+			opts:   []Option{WithForceHTTPS()},
+			want:   "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:   "ipv6 default port dropped",
+			rawurl: "http://[::1]:80/test",
+			opts:   []Option{WithForceHTTPS()},
+			want:   "curl -X 'GET' 'https://[::1]/test'",
+		},
+		{
+			name:   "other port kept",
+			rawurl: "http://localhost:8080/test",
+			opts:   []Option{WithForceHTTPS()},
+			want:   "curl -X 'GET' 'https://localhost:8080/test'",
+		},
+		{
+			name:   "insecure on rewrite",
+			rawurl: "http://localhost/test",
+			opts:   []Option{WithForceHTTPS(), WithInsecureOnRewrite()},
+			want:   "curl -k -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:   "insecure without rewrite",
+			rawurl: "https://localhost/test",
+			opts:   []Option{WithForceHTTPS(), WithInsecureOnRewrite()},
+			want:   "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:   "https to http",
+			rawurl: "https://localhost:443/test",
+			opts:   []Option{WithForceHTTP(), WithInsecureOnRewrite()},
+			want:   "curl -X 'GET' 'http://localhost/test'",
+		},
+		{
+			name:   "last option wins",
+			rawurl: "http://localhost/test",
+			opts:   []Option{WithForceHTTPS(), WithForceHTTP()},
+			want:   "curl -X 'GET' 'http://localhost/test'",
+		},
+		{
+			name:   "other scheme",
+			rawurl: "ftp://localhost/test",
+			opts:   []Option{WithForceHTTPS()},
+			want:   "curl -X 'GET' 'ftp://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, tt.rawurl, tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithForceHTTPS_config(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "http://localhost/test", WithForceHTTPS(), WithInsecureOnRewrite())
+
+	cfg := c.Config()
+	if cfg.Insecure || cfg.ForceScheme != "https" || !cfg.InsecureOnRewrite {
+		t.Errorf("Config() = %+v, want the rewrite options only", cfg)
+	}
+}
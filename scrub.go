@@ -0,0 +1,91 @@
+package curling
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// A Scrubber masks the secrets found in a piece of the request, such as
+// a header value, a URL component or the body.
+type Scrubber interface {
+	Scrub(s string) string
+}
+
+// The ScrubberFunc type is an adapter to allow the use of ordinary functions as scrubbers.
+type ScrubberFunc func(s string) string
+
+// Scrub calls f(s).
+func (f ScrubberFunc) Scrub(s string) string {
+	return f(s)
+}
+
+// RegexpScrubber returns a [Scrubber] replacing every match of re with mask.
+// The mask can refer to submatches as in [regexp.Regexp.ReplaceAllString].
+func RegexpScrubber(re *regexp.Regexp, mask string) Scrubber {
+	return ScrubberFunc(func(s string) string {
+		return re.ReplaceAllString(s, mask)
+	})
+}
+
+// The built-in scrubbers.
+var (
+	// JWTScrubber masks the strings looking like JSON Web Tokens.
+	JWTScrubber = RegexpScrubber(regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), placeholderMask)
+
+	// AWSKeyScrubber masks the AWS access key IDs.
+	AWSKeyScrubber = RegexpScrubber(regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), placeholderMask)
+)
+
+// scrub runs the scrubbers over the headers, the URL and the body of the request model,
+// so that every output format is scrubbed.
+func (c *Command) scrub() {
+	if len(c.scrubbers) == 0 {
+		return
+	}
+
+	for _, values := range c.request.header {
+		for i, value := range values {
+			values[i] = c.scrubString(value)
+		}
+	}
+
+	c.scrubURL(c.request.url)
+
+	if len(c.request.body) == 0 {
+		return
+	}
+
+	truncated := len(c.request.body) < c.request.bodySize
+	c.request.body = []byte(c.scrubString(string(c.request.body)))
+	if !truncated {
+		c.request.bodySize = len(c.request.body)
+	}
+}
+
+// scrubURL runs the scrubbers over the components of u that may carry secrets.
+func (c *Command) scrubURL(u *url.URL) {
+	if u.User != nil {
+		username := c.scrubString(u.User.Username())
+		if password, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(username, c.scrubString(password))
+		} else {
+			u.User = url.User(username)
+		}
+	}
+
+	// The escaped path is scrubbed too, so that the masks keep their characters.
+	u.RawPath = c.scrubString(u.EscapedPath())
+	u.Path = c.scrubString(u.Path)
+	u.RawQuery = c.scrubString(u.RawQuery)
+	u.Fragment = c.scrubString(u.Fragment)
+	u.RawFragment = ""
+}
+
+// scrubString runs the scrubbers over s, in order.
+func (c *Command) scrubString(s string) string {
+	for _, scrubber := range c.scrubbers {
+		s = scrubber.Scrub(s)
+	}
+
+	return s
+}
@@ -0,0 +1,188 @@
+package curling
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// sinkFileName is the name of the file a [FileSink] appends the commands to.
+const sinkFileName = "curling.sh"
+
+// sinkTimeLayout is the timestamp layout of the files rotated by a [FileSink].
+const sinkTimeLayout = "20060102T150405.000000000"
+
+// A RotatePolicy configures when a [FileSink] starts a new file.
+type RotatePolicy struct {
+	// MaxSize is the size, in bytes, above which the current file is rotated.
+	// Zero means the file is never rotated.
+	MaxSize int64
+
+	// MaxFiles is the number of rotated files kept, the oldest are removed first.
+	// Zero means every rotated file is kept.
+	MaxFiles int
+
+	// PerCommand writes each command to its own file instead of appending it
+	// to the current file. MaxSize is ignored.
+	PerCommand bool
+}
+
+// A FileSink records commands to files in a directory, each preceded by a comment
+// with the time the command was built, so that the files are replayable shell scripts.
+// The commands are appended to curling.sh, which is renamed with a timestamp
+// suffix when it grows above the size set by the [RotatePolicy].
+// A FileSink is safe for concurrent use.
+type FileSink struct {
+	mu     sync.Mutex
+	dir    string
+	rotate RotatePolicy
+	file   *os.File
+	size   int64
+	seq    int
+}
+
+// NewFileSink returns a new [FileSink] writing to dir, which is created if needed.
+// If the directory or the current file can't be opened, NewFileSink returns an error.
+func NewFileSink(dir string, rotate RotatePolicy) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating sink directory: %w", err)
+	}
+
+	s := &FileSink{
+		dir:    dir,
+		rotate: rotate,
+	}
+
+	if rotate.PerCommand {
+		return s, nil
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Write records c.
+// If Write can't write or rotate the files, it returns an error.
+func (s *FileSink) Write(c *Command) error {
+	entry := fmt.Sprintf("# %s\n%s\n", c.capturedAt.Format(time.RFC3339Nano), c)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rotate.PerCommand {
+		if err := os.WriteFile(s.nextName(c.capturedAt), []byte(entry), 0o600); err != nil {
+			return fmt.Errorf("writing command file: %w", err)
+		}
+		return s.prune()
+	}
+
+	if s.file == nil {
+		return fmt.Errorf("sink is closed")
+	}
+
+	if s.rotate.MaxSize > 0 && s.size > 0 && s.size+int64(len(entry)) > s.rotate.MaxSize {
+		if err := s.rotateFile(c.capturedAt); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.WriteString(entry)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("writing command: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the current file. Commands written after Close return an error.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+// open opens the current file for appending.
+func (s *FileSink) open() error {
+	f, err := os.OpenFile(filepath.Join(s.dir, sinkFileName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening sink file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("opening sink file: %w", err)
+	}
+
+	s.file = f
+	s.size = info.Size()
+
+	return nil
+}
+
+// rotateFile renames the current file with the timestamp at as suffix and opens a new one.
+func (s *FileSink) rotateFile(at time.Time) error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("rotating sink file: %w", err)
+	}
+	s.file = nil
+
+	if err := os.Rename(filepath.Join(s.dir, sinkFileName), s.nextName(at)); err != nil {
+		return fmt.Errorf("rotating sink file: %w", err)
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+
+	return s.prune()
+}
+
+// nextName returns the path of the next timestamped file, named after the time
+// at which the command being written was built, as set with [WithClock].
+// The sequence number tells apart files created within the clock resolution.
+func (s *FileSink) nextName(at time.Time) string {
+	s.seq++
+	name := fmt.Sprintf("curling-%s-%06d.sh", at.UTC().Format(sinkTimeLayout), s.seq)
+
+	return filepath.Join(s.dir, name)
+}
+
+// prune removes the oldest timestamped files above the number set by the policy.
+func (s *FileSink) prune() error {
+	if s.rotate.MaxFiles <= 0 {
+		return nil
+	}
+
+	names, err := filepath.Glob(filepath.Join(s.dir, "curling-*.sh"))
+	if err != nil {
+		return fmt.Errorf("listing sink files: %w", err)
+	}
+
+	// Timestamp suffixes sort chronologically.
+	slices.Sort(names)
+
+	for len(names) > s.rotate.MaxFiles {
+		if err := os.Remove(names[0]); err != nil {
+			return fmt.Errorf("removing sink file: %w", err)
+		}
+		names = names[1:]
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testSinkCommand(t *testing.T, path string) *Command {
+	t.Helper()
+
+	c := mustNewFromRequest(t, "GET", "https://localhost"+path)
+	c.capturedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return c
+}
+
+func readSinkFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("reading sink directory: %v", err)
+	}
+
+	var contents []string
+	for _, entry := range entries {
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("reading sink file: %v", err)
+		}
+		contents = append(contents, string(b))
+	}
+
+	return contents
+}
+
+func TestFileSink_Write(t *testing.T) {
+	entry := func(path string) string {
+		return "# 2024-01-02T03:04:05Z\ncurl -X 'GET' 'https://localhost" + path + "'\n"
+	}
+
+	tests := []struct {
+		name   string
+		rotate RotatePolicy
+		want   []string
+	}{
+		{
+			name: "single file",
+			want: []string{entry("/a") + entry("/b") + entry("/c")},
+		},
+		{
+			name:   "size rotation",
+			rotate: RotatePolicy{MaxSize: int64(len(entry("/a")) + 1)},
+			want:   []string{entry("/a"), entry("/b"), entry("/c")},
+		},
+		{
+			name:   "size rotation with max files",
+			rotate: RotatePolicy{MaxSize: int64(len(entry("/a")) * 2), MaxFiles: 1},
+			want:   []string{entry("/a") + entry("/b"), entry("/c")},
+		},
+		{
+			name:   "per command",
+			rotate: RotatePolicy{PerCommand: true, MaxFiles: 2},
+			want:   []string{entry("/b"), entry("/c")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "commands")
+
+			s, err := NewFileSink(dir, tt.rotate)
+			if err != nil {
+				t.Fatalf("NewFileSink() error = %v", err)
+			}
+
+			for _, path := range []string{"/a", "/b", "/c"} {
+				if err := s.Write(testSinkCommand(t, path)); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			// The current file sorts after the timestamped ones.
+			got := readSinkFiles(t, dir)
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("files diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestFileSink_Write_names(t *testing.T) {
+	tests := []struct {
+		name   string
+		rotate RotatePolicy
+		want   []string
+	}{
+		{
+			name:   "size rotation",
+			rotate: RotatePolicy{MaxSize: 1},
+			want: []string{
+				"curling-20240102T030405.000000000-000001.sh",
+				"curling-20240102T030405.000000000-000002.sh",
+				"curling.sh",
+			},
+		},
+		{
+			name:   "per command",
+			rotate: RotatePolicy{PerCommand: true},
+			want: []string{
+				"curling-20240102T030405.000000000-000001.sh",
+				"curling-20240102T030405.000000000-000002.sh",
+				"curling-20240102T030405.000000000-000003.sh",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			s, err := NewFileSink(dir, tt.rotate)
+			if err != nil {
+				t.Fatalf("NewFileSink() error = %v", err)
+			}
+
+			for _, path := range []string{"/a", "/b", "/c"} {
+				if err := s.Write(testSinkCommand(t, path)); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			if err := s.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("reading sink directory: %v", err)
+			}
+
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Name())
+			}
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("file names diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestFileSink_Write_closed(t *testing.T) {
+	s, err := NewFileSink(t.TempDir(), RotatePolicy{})
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if err := s.Write(testSinkCommand(t, "/a")); err == nil {
+		t.Errorf("Write() error = nil, want error")
+	}
+}
+
+func TestNewFileSink_append(t *testing.T) {
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		s, err := NewFileSink(dir, RotatePolicy{})
+		if err != nil {
+			t.Fatalf("NewFileSink() error = %v", err)
+		}
+
+		if err := s.Write(testSinkCommand(t, "/a")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	entry := "# 2024-01-02T03:04:05Z\ncurl -X 'GET' 'https://localhost/a'\n"
+	want := []string{entry + entry}
+	if got := readSinkFiles(t, dir); !cmp.Equal(got, want) {
+		t.Errorf("files diff = %v", cmp.Diff(got, want))
+	}
+}
@@ -0,0 +1,21 @@
+package curling
+
+import "log/slog"
+
+// LogValue implements the [slog.LogValuer] interface, so a command can be
+// passed to log/slog as is: it is rendered only when the record is handled,
+// not when the log level is disabled.
+func (c *Command) LogValue() slog.Value {
+	return slog.GroupValue(c.LogAttrs()...)
+}
+
+// LogAttrs returns the structured fields describing the command:
+// the request method and URL, the rendered command and whether the body was truncated.
+func (c *Command) LogAttrs() []slog.Attr {
+	return []slog.Attr{
+		slog.String("method", c.request.method),
+		slog.String("url", c.request.url.String()),
+		slog.String("curl", c.String()),
+		slog.Bool("truncated", len(c.request.body) < c.request.bodySize),
+	}
+}
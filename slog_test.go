@@ -0,0 +1,78 @@
+package curling
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCommand_LogValue(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		body string
+		want string
+	}{
+		{
+			name: "without body",
+			want: `level=INFO msg=request cmd.method=GET cmd.url=https://localhost/test cmd.curl="curl -X 'GET' 'https://localhost/test'" cmd.truncated=false` + "\n",
+		},
+		{
+			name: "truncated body",
+			opts: []Option{WithMaxBodySize(3)},
+			body: "key=value",
+			want: `level=INFO msg=request cmd.method=POST cmd.url=https://localhost/test cmd.curl="curl -X 'POST' 'https://localhost/test' -d 'key'" cmd.truncated=true` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := http.MethodGet
+			var body io.Reader
+			if tt.body != "" {
+				method = http.MethodPost
+				body = strings.NewReader(tt.body)
+			}
+
+			c, err := NewFromParts(method, "https://localhost/test", nil, body, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromParts() error = %v", err)
+			}
+
+			var b bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+
+			logger.Info("request", "cmd", c)
+
+			if got := b.String(); got != tt.want {
+				t.Errorf("log = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_LogValue_disabled(t *testing.T) {
+	c := mustNewFromRequest(t, "GET", "https://localhost/test")
+
+	var rendered bool
+	c.renderer = RendererFunc(func(c *Command) string {
+		rendered = true
+		return ""
+	})
+
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	logger.Info("request", "cmd", c)
+
+	if rendered {
+		t.Errorf("command rendered with disabled log level")
+	}
+}
@@ -0,0 +1,80 @@
+package curling
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// A SmokeTest pairs a command with the status code its response is expected to have.
+type SmokeTest struct {
+	// Command is the request to replay.
+	Command *Command
+
+	// Status is the expected response status code.
+	Status int
+}
+
+// NewSmokeTest returns a [SmokeTest] expecting the status code of resp.
+// If resp is nil, NewSmokeTest returns an error.
+func NewSmokeTest(c *Command, resp *http.Response) (SmokeTest, error) {
+	if resp == nil {
+		return SmokeTest{}, fmt.Errorf("response is nil")
+	}
+
+	return SmokeTest{
+		Command: c,
+		Status:  resp.StatusCode,
+	}, nil
+}
+
+// SmokeTestScript returns a POSIX shell script that replays each test with cURL,
+// captures the response status code with --write-out and checks it against the
+// expected one. The script prints a TAP-like line for each test and exits
+// with a non-zero status when any check fails.
+func SmokeTestScript(tests []SmokeTest) string {
+	var b strings.Builder
+
+	b.WriteString("#!/bin/sh\n\n")
+	b.WriteString("failed=0\n")
+
+	for i, test := range tests {
+		c := test.Command
+		description := fmt.Sprintf("%d - %s %s", i+1, c.request.method, c.request.url.Redacted())
+
+		b.WriteString("\n")
+		b.WriteString(c.decorate(""))
+		fmt.Fprintf(&b, "code=$(%s)\n", c.join(c.statusCodeTokens()))
+		fmt.Fprintf(&b, "if [ \"$code\" = \"%d\" ]; then\n", test.Status)
+		fmt.Fprintf(&b, "  echo %s\n", c.escape("ok "+description))
+		b.WriteString("else\n")
+		fmt.Fprintf(&b, "  echo %s\"$code\"\n", c.escape(fmt.Sprintf("not ok %s: want %d, got ", description, test.Status)))
+		b.WriteString("  failed=1\n")
+		b.WriteString("fi\n")
+	}
+
+	b.WriteString("\nexit $failed\n")
+
+	return b.String()
+}
+
+// statusCodeTokens returns the command tokens changed so that cURL prints
+// only the response status code.
+func (c *Command) statusCodeTokens() []string {
+	s := []string{"curl"}
+
+	if !c.silent {
+		s = append(s, c.option(flagSilent))
+	}
+
+	s = append(s,
+		c.option(flagOutput), "/dev/null",
+		c.option(flagWriteOut), c.escape("%{http_code}"),
+	)
+
+	c.ensureConstructed()
+	tokens := append([]string{}, c.tokens...)
+	tokens[0] = strings.Join(s, " ") + strings.TrimPrefix(tokens[0], "curl")
+
+	return tokens
+}
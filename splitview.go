@@ -0,0 +1,29 @@
+package curling
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ToSplitView returns a two-part rendering of the request: a human-readable
+// summary block with the method, host, content type, body size and capture time,
+// followed by a blank line and the command rendered by [Command.String].
+// It suits chat and ticketing tools where the context matters as much as the command.
+func (c *Command) ToSplitView() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Method:       %s\n", c.request.method)
+	fmt.Fprintf(&b, "Host:         %s\n", c.request.url.Host)
+
+	if contentType := c.request.header.Get("Content-Type"); contentType != "" {
+		fmt.Fprintf(&b, "Content-Type: %s\n", contentType)
+	}
+
+	fmt.Fprintf(&b, "Body size:    %d bytes\n", len(c.request.body))
+	fmt.Fprintf(&b, "Captured at:  %s\n", c.capturedAt.Format(time.RFC3339))
+	b.WriteString("\n")
+	b.WriteString(c.String())
+
+	return b.String()
+}
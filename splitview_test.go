@@ -0,0 +1,66 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCommand_ToSplitView(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	capturedAt := time.Date(2024, time.March, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		want string
+	}{
+		{
+			name: "without body",
+			r: &http.Request{
+				URL: testUrl,
+			},
+			want: "Method:       GET\n" +
+				"Host:         localhost\n" +
+				"Body size:    0 bytes\n" +
+				"Captured at:  2024-03-01T10:30:00Z\n" +
+				"\n" +
+				"curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "with body",
+			r: &http.Request{
+				Method: http.MethodPost,
+				URL:    testUrl,
+				Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+				Body:   readCloser("key=value"),
+			},
+			want: "Method:       POST\n" +
+				"Host:         localhost\n" +
+				"Content-Type: application/x-www-form-urlencoded\n" +
+				"Body size:    9 bytes\n" +
+				"Captured at:  2024-03-01T10:30:00Z\n" +
+				"\n" +
+				"curl -X 'POST' 'https://localhost/test' -H 'Content-Type: application/x-www-form-urlencoded' -d 'key=value'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.r)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+			c.capturedAt = capturedAt
+
+			if got := c.ToSplitView(); got != tt.want {
+				t.Errorf("ToSplitView() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
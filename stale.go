@@ -0,0 +1,111 @@
+package curling
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The metadata keys read by [WithStalenessCheck], set with [Command.SetMeta].
+const (
+	// MetaCapturedAt holds the [time.Time] the request was captured at,
+	// which takes precedence over the time the command was built.
+	MetaCapturedAt = "curling.captured_at"
+
+	// MetaCookies holds the []*[http.Cookie] set by the responses of the session,
+	// whose expiration is checked against the cookies the request sends.
+	MetaCookies = "curling.cookies"
+)
+
+// dateSkewTolerance is the age above which a Date header is reported as stale,
+// the clock skew signed requests commonly tolerate.
+const dateSkewTolerance = 15 * time.Minute
+
+// WithStalenessCheck annotates the script with comments reporting the age of each
+// request and the parts of it that no longer replay successfully at replayAt:
+// expired bearer tokens and cookies holding JWTs, Date headers older than 15
+// minutes, and cookies expired according to [MetaCookies].
+// Bearer tokens replaced with [WithTokenRefresh] are not reported.
+// A zero replayAt will be silently ignored.
+func WithStalenessCheck(replayAt time.Time) ScriptOption {
+	return func(config *scriptConfig) {
+		config.staleAt = replayAt
+	}
+}
+
+// capturedTime returns the time the request was captured at,
+// from [MetaCapturedAt] when set, the time the command was built otherwise.
+func (c *Command) capturedTime() time.Time {
+	if t, ok := c.Meta(MetaCapturedAt).(time.Time); ok {
+		return t
+	}
+
+	return c.capturedAt
+}
+
+// staleComments returns the comment lines reporting the age of the request of c
+// and its stale parts, none when the staleness check is disabled.
+func (config scriptConfig) staleComments(c *Command) []string {
+	at := config.staleAt
+	if at.IsZero() {
+		return nil
+	}
+
+	var comments []string
+
+	captured := c.capturedTime()
+	if !captured.IsZero() {
+		comments = append(comments, fmt.Sprintf("# Captured at %s, %s before the replay",
+			captured.UTC().Format(time.RFC3339), at.Sub(captured).Round(time.Second)))
+	}
+
+	if t, ok := c.expiringToken(); ok && config.tokenRefresh == "" && !t.expiresAt.After(at) {
+		comments = append(comments, fmt.Sprintf("# Bearer token expired at %s", t.expiresAt.Format(time.RFC3339)))
+	}
+
+	if date, err := http.ParseTime(c.request.header.Get("Date")); err == nil && at.Sub(date) > dateSkewTolerance {
+		comments = append(comments, fmt.Sprintf("# Date header is %s old, servers checking it may reject the request",
+			at.Sub(date).Round(time.Second)))
+	}
+
+	r := http.Request{Header: http.Header{"Cookie": c.request.header.Values("Cookie")}}
+	for _, cookie := range r.Cookies() {
+		if exp, ok := jwtExpiration(cookie.Value); ok && !exp.After(at) {
+			comments = append(comments, fmt.Sprintf("# Cookie %s holds a token expired at %s", cookie.Name, exp.Format(time.RFC3339)))
+			continue
+		}
+
+		if exp, ok := c.cookieExpiration(cookie.Name, captured); ok && !exp.After(at) {
+			comments = append(comments, fmt.Sprintf("# Cookie %s expired at %s", cookie.Name, exp.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	return comments
+}
+
+// cookieExpiration returns the expiration time of the cookie name set by the
+// responses listed in [MetaCookies], reporting false when it is unknown or the
+// cookie doesn't expire. Max-Age counts from captured, the time the cookie was seen.
+func (c *Command) cookieExpiration(name string, captured time.Time) (time.Time, bool) {
+	cookies, _ := c.Meta(MetaCookies).([]*http.Cookie)
+
+	for i := len(cookies) - 1; i >= 0; i-- {
+		cookie := cookies[i]
+		if cookie == nil || cookie.Name != name {
+			continue
+		}
+
+		switch {
+		case cookie.MaxAge < 0:
+			return captured, !captured.IsZero()
+		case cookie.MaxAge > 0 && !captured.IsZero():
+			return captured.Add(time.Duration(cookie.MaxAge) * time.Second), true
+		case !cookie.Expires.IsZero():
+			return cookie.Expires, true
+		}
+
+		return time.Time{}, false
+	}
+
+	return time.Time{}, false
+}
@@ -0,0 +1,114 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCommandSet_Script_stalenessCheck(t *testing.T) {
+	captured := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	replay := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	clock := WithClock(func() time.Time { return captured })
+
+	newCommand := func(t *testing.T, header http.Header, meta map[string]any) *Command {
+		t.Helper()
+
+		c, err := NewFromRequest(&http.Request{
+			Method: http.MethodGet,
+			URL:    &url.URL{Scheme: "https", Host: "localhost", Path: "/a"},
+			Header: header,
+		}, clock)
+		if err != nil {
+			t.Fatalf("NewFromRequest() error = %v", err)
+		}
+
+		for key, v := range meta {
+			c.SetMeta(key, v)
+		}
+
+		return c
+	}
+
+	expired := jwt(`{"sub":"42","exp":1767225600}`)
+	lasting := jwt(`{"sub":"42","exp":1798761600}`)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		meta   map[string]any
+		opts   []ScriptOption
+		want   string
+	}{
+		{
+			name: "without check",
+			want: "#!/bin/sh\n\ncurl -X 'GET' 'https://localhost/a'\n",
+		},
+		{
+			name: "age",
+			opts: []ScriptOption{WithStalenessCheck(replay)},
+			want: "#!/bin/sh\n\n# Captured at 2025-12-31T00:00:00Z, 48h0m0s before the replay\ncurl -X 'GET' 'https://localhost/a'\n",
+		},
+		{
+			name: "captured at metadata",
+			meta: map[string]any{MetaCapturedAt: replay.Add(-time.Hour)},
+			opts: []ScriptOption{WithStalenessCheck(replay)},
+			want: "#!/bin/sh\n\n# Captured at 2026-01-01T23:00:00Z, 1h0m0s before the replay\ncurl -X 'GET' 'https://localhost/a'\n",
+		},
+		{
+			name:   "expired bearer token",
+			header: http.Header{"Authorization": {"Bearer " + expired}},
+			opts:   []ScriptOption{WithStalenessCheck(replay)},
+			want: "#!/bin/sh\n\n# Captured at 2025-12-31T00:00:00Z, 48h0m0s before the replay\n" +
+				"# Bearer token expired at 2026-01-01T00:00:00Z\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Authorization: Bearer " + expired + "'\n",
+		},
+		{
+			name:   "lasting bearer token",
+			header: http.Header{"Authorization": {"Bearer " + lasting}},
+			opts:   []ScriptOption{WithStalenessCheck(replay)},
+			want: "#!/bin/sh\n\n# Captured at 2025-12-31T00:00:00Z, 48h0m0s before the replay\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Authorization: Bearer " + lasting + "'\n",
+		},
+		{
+			name:   "refreshed bearer token",
+			header: http.Header{"Authorization": {"Bearer " + expired}},
+			opts:   []ScriptOption{WithStalenessCheck(replay), WithTokenRefresh("$(get-token)")},
+			want: "#!/bin/sh\n\n# Captured at 2025-12-31T00:00:00Z, 48h0m0s before the replay\n" +
+				"# Bearer token expiring at 2026-01-01T00:00:00Z replaced with $(get-token)\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Authorization: Bearer '\"$(get-token)\"''\n",
+		},
+		{
+			name:   "past date header",
+			header: http.Header{"Date": {"Wed, 31 Dec 2025 00:00:00 GMT"}},
+			opts:   []ScriptOption{WithStalenessCheck(replay)},
+			want: "#!/bin/sh\n\n# Captured at 2025-12-31T00:00:00Z, 48h0m0s before the replay\n" +
+				"# Date header is 48h0m0s old, servers checking it may reject the request\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Date: Wed, 31 Dec 2025 00:00:00 GMT'\n",
+		},
+		{
+			name:   "expired cookies",
+			header: http.Header{"Cookie": {"jwt=" + expired + "; session=1; theme=dark; id=2"}},
+			meta: map[string]any{MetaCookies: []*http.Cookie{
+				{Name: "session", MaxAge: 3600},
+				{Name: "theme"},
+				{Name: "id", Expires: replay.Add(time.Hour)},
+			}},
+			opts: []ScriptOption{WithStalenessCheck(replay)},
+			want: "#!/bin/sh\n\n# Captured at 2025-12-31T00:00:00Z, 48h0m0s before the replay\n" +
+				"# Cookie jwt holds a token expired at 2026-01-01T00:00:00Z\n" +
+				"# Cookie session expired at 2025-12-31T01:00:00Z\n" +
+				"curl -X 'GET' 'https://localhost/a' -H 'Cookie: jwt=" + expired + "; session=1; theme=dark; id=2'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := CommandSet{newCommand(t, tt.header, tt.meta)}
+
+			if got := s.Script(tt.opts...); got != tt.want {
+				t.Errorf("Script() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package curling
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Stats reports the size of a request, in bytes.
+type Stats struct {
+	// HeaderBytes is the size of the headers, as "Key: value\r\n" lines.
+	HeaderBytes int
+
+	// BodyBytes is the size of the original request body.
+	BodyBytes int
+
+	// CapturedBodyBytes is the size of the body rendered in the command.
+	CapturedBodyBytes int
+
+	// WireBytes is an estimate of the request size on the wire, including the request line,
+	// the Host and Content-Length headers when the request doesn't set them,
+	// the headers and the original body. It doesn't account for headers added by the client.
+	WireBytes int
+}
+
+// Stats returns the size accounting of the request the command is built from,
+// as it was before the options, such as redaction, changed it.
+func (c *Command) Stats() Stats {
+	var s Stats
+	r := c.source
+
+	for key, values := range r.header {
+		for _, value := range values {
+			s.HeaderBytes += len(http.CanonicalHeaderKey(key)) + len(": ") + len(value) + len("\r\n")
+		}
+	}
+
+	s.BodyBytes = r.bodySize
+	s.CapturedBodyBytes = len(c.request.body)
+
+	requestLine := r.method + " " + r.url.RequestURI() + " " + r.proto + "\r\n"
+	s.WireBytes = len(requestLine) + s.HeaderBytes
+
+	if r.header.Get("Host") == "" {
+		s.WireBytes += len("Host: " + r.url.Host + "\r\n")
+	}
+
+	if r.hasBody && r.header.Get("Content-Length") == "" {
+		s.WireBytes += len("Content-Length: " + strconv.Itoa(s.BodyBytes) + "\r\n")
+	}
+
+	s.WireBytes += len("\r\n") + s.BodyBytes
+
+	return s
+}
@@ -0,0 +1,91 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_Stats(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme:   "https",
+		Host:     "localhost",
+		Path:     "/test",
+		RawQuery: "a=1",
+	}
+
+	tests := []struct {
+		name string
+		r    *http.Request
+		opts []Option
+		want Stats
+	}{
+		{
+			name: "without headers and body",
+			r: &http.Request{
+				URL: testUrl,
+			},
+			want: Stats{
+				// "GET /test?a=1 HTTP/1.1\r\n" + "Host: localhost\r\n" + "\r\n"
+				WireBytes: 24 + 17 + 2,
+			},
+		},
+		{
+			name: "with headers and body",
+			r: &http.Request{
+				Method: http.MethodPost,
+				URL:    testUrl,
+				Header: http.Header{"X-Key": {"1", "22"}},
+				Body:   readCloser("key=value"),
+			},
+			want: Stats{
+				// "X-Key: 1\r\n" + "X-Key: 22\r\n"
+				HeaderBytes:       10 + 11,
+				BodyBytes:         9,
+				CapturedBodyBytes: 9,
+				// "POST /test?a=1 HTTP/1.1\r\n" + headers + "Host: localhost\r\n" + "Content-Length: 9\r\n" + "\r\n" + body
+				WireBytes: 25 + 21 + 17 + 19 + 2 + 9,
+			},
+		},
+		{
+			name: "with host header",
+			r: &http.Request{
+				URL:    testUrl,
+				Header: http.Header{"Host": {"example.com"}},
+			},
+			want: Stats{
+				// "Host: example.com\r\n"
+				HeaderBytes: 19,
+				// "GET /test?a=1 HTTP/1.1\r\n" + headers + "\r\n"
+				WireBytes: 24 + 19 + 2,
+			},
+		},
+		{
+			name: "with redacted headers",
+			r: &http.Request{
+				URL:    testUrl,
+				Header: http.Header{"Authorization": {"Bearer abc"}},
+			},
+			opts: []Option{WithRedactedHeaders("Authorization"), WithForceHTTP()},
+			want: Stats{
+				// "Authorization: Bearer abc\r\n"
+				HeaderBytes: 27,
+				// "GET /test?a=1 HTTP/1.1\r\n" + headers + "Host: localhost\r\n" + "\r\n"
+				WireBytes: 24 + 27 + 17 + 2,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.Stats(); !cmp.Equal(got, tt.want) {
+				t.Errorf("Stats() diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
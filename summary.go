@@ -0,0 +1,117 @@
+package curling
+
+import "time"
+
+// The shells a command can be rendered for.
+const (
+	ShellPOSIX      = "posix"
+	ShellWindows    = "cmd"
+	ShellPowerShell = "powershell"
+)
+
+// An OptionsSummary reports the options a [Command] was built with,
+// so that logging systems can record how a command was produced.
+type OptionsSummary struct {
+	// LongForm reports whether cURL options use their long form.
+	LongForm bool
+
+	// MultiLine reports whether the command is split across multiple lines.
+	MultiLine bool
+
+	// Shell is the shell targeted by the line continuation:
+	// ShellPOSIX, ShellWindows or ShellPowerShell.
+	Shell string
+
+	// DoubleQuotes reports whether values are escaped using double quotes.
+	DoubleQuotes bool
+
+	// FlagGrouping reports whether related tokens share lines.
+	FlagGrouping bool
+
+	// FollowRedirects reports whether the option -L, --location is set.
+	FollowRedirects bool
+
+	// Compressed reports whether the option --compressed is set.
+	Compressed bool
+
+	// Insecure reports whether the option -k, --insecure is set.
+	Insecure bool
+
+	// Silent reports whether the option -s, --silent is set.
+	Silent bool
+
+	// RequestTimeout is the value of the option -m, --max-time, zero when not set.
+	RequestTimeout time.Duration
+
+	// ConnectTimeout is the value of the option --connect-timeout, zero when not set.
+	ConnectTimeout time.Duration
+
+	// InlineWarnings reports whether warnings are rendered as comments.
+	InlineWarnings bool
+
+	// BodyKindComment reports whether the body kind is rendered as a comment.
+	BodyKindComment bool
+
+	// Placeholders lists the names of the variables replacing literal values.
+	Placeholders []string
+
+	// RedactedHeaders lists the headers whose values are replaced by variables.
+	RedactedHeaders []string
+
+	// HeaderRedactor reports whether a header redactor is set.
+	HeaderRedactor bool
+
+	// MaxBodySize is the size above which the body is truncated, zero when not set.
+	MaxBodySize int
+
+	// MaxOutputSize is the size the rendered command is shrunk to, zero when not set.
+	MaxOutputSize int
+
+	// OutputVersion is the output version the command is rendered with.
+	OutputVersion int
+}
+
+// Options returns a summary of the options the command was built with.
+func (c *Command) Options() OptionsSummary {
+	s := OptionsSummary{
+		LongForm:        c.useLongForm,
+		MultiLine:       c.useMultiLine,
+		Shell:           c.shell(),
+		DoubleQuotes:    c.useDoubleQuotes,
+		FlagGrouping:    c.groupFlags,
+		FollowRedirects: c.location,
+		Compressed:      c.compressed,
+		Insecure:        c.insecure,
+		Silent:          c.silent,
+		RequestTimeout:  c.requestTimeout,
+		ConnectTimeout:  c.connectTimeout,
+		InlineWarnings:  c.inlineWarnings,
+		BodyKindComment: c.bodyKindComment,
+		HeaderRedactor:  c.headerRedactor != nil,
+		MaxBodySize:     c.maxBodySize,
+		MaxOutputSize:   c.maxOutputSize,
+		OutputVersion:   c.version(),
+	}
+
+	for _, p := range c.placeholders {
+		s.Placeholders = append(s.Placeholders, p.name)
+	}
+
+	if len(c.redactedHeaders) > 0 {
+		s.RedactedHeaders = append([]string{}, c.redactedHeaders...)
+	}
+
+	return s
+}
+
+// shell returns the shell targeted by the line continuation.
+func (c *Command) shell() string {
+	switch c.lineContinuation {
+	case lineContinuationWindows:
+		return ShellWindows
+	case lineContinuationPowerShell:
+		return ShellPowerShell
+	default:
+		return ShellPOSIX
+	}
+}
@@ -0,0 +1,95 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCommand_Options(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want OptionsSummary
+	}{
+		{
+			name: "default options",
+			want: OptionsSummary{
+				Shell:         ShellPOSIX,
+				OutputVersion: DefaultOutputVersion,
+			},
+		},
+		{
+			name: "windows shell",
+			opts: []Option{WithWindowsMultiLine(), WithDoubleQuotes()},
+			want: OptionsSummary{
+				MultiLine:     true,
+				Shell:         ShellWindows,
+				DoubleQuotes:  true,
+				OutputVersion: DefaultOutputVersion,
+			},
+		},
+		{
+			name: "sub-second timeout",
+			opts: []Option{WithRequestTimeoutDuration(500 * time.Millisecond), WithConnectTimeout(250 * time.Millisecond)},
+			want: OptionsSummary{
+				Shell:          ShellPOSIX,
+				RequestTimeout: 500 * time.Millisecond,
+				ConnectTimeout: 250 * time.Millisecond,
+				OutputVersion:  DefaultOutputVersion,
+			},
+		},
+		{
+			name: "all options",
+			opts: []Option{
+				WithLongForm(),
+				WithPowerShellMultiLine(),
+				WithFlagGrouping(),
+				WithFollowRedirects(),
+				WithCompression(),
+				WithInsecure(),
+				WithSilent(),
+				WithRequestTimeout(5),
+				WithInlineWarnings(),
+				WithBodyKindComment(),
+				WithPlaceholders(map[string]string{"HOST": "localhost"}),
+				WithOutputVersion(OutputVersion1),
+			},
+			want: OptionsSummary{
+				LongForm:        true,
+				MultiLine:       true,
+				Shell:           ShellPowerShell,
+				FlagGrouping:    true,
+				FollowRedirects: true,
+				Compressed:      true,
+				Insecure:        true,
+				Silent:          true,
+				RequestTimeout:  5 * time.Second,
+				InlineWarnings:  true,
+				BodyKindComment: true,
+				Placeholders:    []string{"HOST"},
+				OutputVersion:   OutputVersion1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(&http.Request{URL: testUrl}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.Options(); !cmp.Equal(got, tt.want) {
+				t.Errorf("Options() diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
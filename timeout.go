@@ -0,0 +1,67 @@
+package curling
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// WithConnectTimeout sets the flag --connect-timeout, the time the connection
+// to the server may take, with sub-second precision, such as 2.5 for 2.5s.
+// Unlike -m, --max-time, it doesn't bound the transfer.
+// Non-positive durations will be silently ignored.
+func WithConnectTimeout(d time.Duration) Option {
+	return func(curling *Command) {
+		if d <= 0 {
+			return
+		}
+
+		curling.connectTimeout = d
+	}
+}
+
+// WithRequestTimeoutDuration enables the option -m, --max-time, like [WithRequestTimeout],
+// with sub-second precision, such as 2.5 for 2.5s.
+// Non-positive durations will be silently ignored.
+func WithRequestTimeoutDuration(d time.Duration) Option {
+	return func(curling *Command) {
+		if d <= 0 {
+			return
+		}
+
+		curling.requestTimeout = d
+	}
+}
+
+// WithContextTimeout derives the option -m, --max-time from the deadline of the
+// request context, as the time left when the command is built, in milliseconds.
+// A timeout set with [WithRequestTimeout] or [WithRequestTimeoutDuration] takes precedence.
+// Requests without deadline, or whose deadline has passed, are left without timeout.
+func WithContextTimeout() Option {
+	return func(curling *Command) {
+		curling.contextTimeout = true
+	}
+}
+
+// applyContextTimeout sets the request timeout to the time left before the
+// deadline of the request context, when enabled with WithContextTimeout.
+func (c *Command) applyContextTimeout() {
+	if !c.contextTimeout || c.requestTimeout > 0 || c.request.deadline.IsZero() {
+		return
+	}
+
+	if left := c.request.deadline.Sub(c.capturedAt).Round(time.Millisecond); left > 0 {
+		c.requestTimeout = left
+		c.timeoutFromContext = true
+	}
+}
+
+// formatSeconds returns d in seconds, with the fractional digits it needs, such as 2.5.
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+// wholeSeconds returns d in seconds, rounded up.
+func wholeSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,173 @@
+package curling
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithConnectTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "fractional seconds",
+			opts: []Option{WithConnectTimeout(2500 * time.Millisecond)},
+			want: "curl --connect-timeout 2.5 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "with max time",
+			opts: []Option{WithRequestTimeout(10), WithConnectTimeout(3 * time.Second)},
+			want: "curl -m 10 --connect-timeout 3 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "long form",
+			opts: []Option{WithLongForm(), WithRequestTimeout(10), WithConnectTimeout(250 * time.Millisecond)},
+			want: "curl --max-time 10 --connect-timeout 0.25 --request 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "non-positive values",
+			opts: []Option{WithConnectTimeout(time.Second), WithConnectTimeout(0), WithConnectTimeout(-time.Second)},
+			want: "curl --connect-timeout 1 -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithRequestTimeoutDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "fractional seconds",
+			opts: []Option{WithRequestTimeoutDuration(1500 * time.Millisecond)},
+			want: "curl -m 1.5 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "long form",
+			opts: []Option{WithLongForm(), WithRequestTimeoutDuration(2 * time.Second)},
+			want: "curl --max-time 2 --request 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "replaces seconds",
+			opts: []Option{WithRequestTimeout(5), WithRequestTimeoutDuration(250 * time.Millisecond)},
+			want: "curl -m 0.25 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "non-positive values",
+			opts: []Option{WithRequestTimeoutDuration(time.Second), WithRequestTimeoutDuration(0), WithRequestTimeoutDuration(-time.Second)},
+			want: "curl -m 1 -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithContextTimeout(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	tests := []struct {
+		name     string
+		deadline time.Time
+		opts     []Option
+		want     string
+	}{
+		{
+			name:     "deadline",
+			deadline: now.Add(2500 * time.Millisecond),
+			opts:     []Option{WithContextTimeout()},
+			want:     "curl -m 2.5 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:     "rounded to milliseconds",
+			deadline: now.Add(1500*time.Millisecond + 400*time.Microsecond),
+			opts:     []Option{WithContextTimeout()},
+			want:     "curl -m 1.5 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:     "explicit timeout takes precedence",
+			deadline: now.Add(2500 * time.Millisecond),
+			opts:     []Option{WithContextTimeout(), WithRequestTimeout(10)},
+			want:     "curl -m 10 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:     "deadline passed",
+			deadline: now.Add(-time.Second),
+			opts:     []Option{WithContextTimeout()},
+			want:     "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "no deadline",
+			opts: []Option{WithContextTimeout()},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:     "not enabled",
+			deadline: now.Add(2500 * time.Millisecond),
+			want:     "curl -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if !tt.deadline.IsZero() {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithDeadline(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			r, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequestWithContext() error = %v", err)
+			}
+
+			c, err := NewFromRequest(r, append([]Option{WithClock(clock)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_formatSeconds(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 5 * time.Second, want: "5"},
+		{d: 2500 * time.Millisecond, want: "2.5"},
+		{d: 10 * time.Millisecond, want: "0.01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatSeconds(tt.d); got != tt.want {
+				t.Errorf("formatSeconds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
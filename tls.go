@@ -0,0 +1,71 @@
+package curling
+
+import "strings"
+
+// certPasswordVariable is the shell variable replacing the certificate password.
+const certPasswordVariable = "CERT_PASSWORD"
+
+// WithClientCert sets the flags -E, --cert and --key, so that the command
+// authenticates with the client certificate at certPath and its private key
+// at keyPath, as mTLS APIs require. An empty keyPath means the key is in
+// the certificate file.
+// An empty certPath will be silently ignored.
+func WithClientCert(certPath, keyPath string) Option {
+	return func(curling *Command) {
+		if certPath == "" {
+			return
+		}
+
+		curling.certFile = certPath
+		curling.keyFile = keyPath
+		curling.certInMemory = false
+	}
+}
+
+// WithCertPassword sets the password of the private key of the client
+// certificate set with [WithClientCert], passed to cURL after the certificate
+// path. The password is rendered as the CERT_PASSWORD placeholder (see [WithPlaceholders]).
+// An empty password will be silently ignored.
+func WithCertPassword(password string) Option {
+	return func(curling *Command) {
+		if password == "" {
+			return
+		}
+
+		curling.certPassword = password
+	}
+}
+
+// WithCACert sets the flag --cacert, so that the command verifies the server
+// with the CA certificates at path.
+// An empty path will be silently ignored.
+func WithCACert(path string) Option {
+	return func(curling *Command) {
+		if path == "" {
+			return
+		}
+
+		curling.caCertFile = path
+		curling.caCertInMemory = false
+	}
+}
+
+// certArgument returns the argument of --cert: the certificate path, with its
+// backslashes and colons escaped as cURL requires, followed by the password.
+func (c *Command) certArgument() string {
+	s := strings.NewReplacer(`\`, `\\`, `:`, `\:`).Replace(c.certFile)
+	if c.certPassword != "" {
+		s += ":" + c.certPassword
+	}
+
+	return s
+}
+
+// redactCertPassword replaces the certificate password with the CERT_PASSWORD placeholder.
+func (c *Command) redactCertPassword() {
+	if c.certFile == "" || c.certPassword == "" {
+		return
+	}
+
+	c.redactSecret(certPasswordVariable, &c.certPassword)
+}
@@ -0,0 +1,125 @@
+package curling
+
+import (
+	"crypto/tls"
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"testing"
+)
+
+func TestWithClientCert(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "certificate and key",
+			opts: []Option{WithClientCert("client.pem", "client.key")},
+			want: "curl -E 'client.pem' --key 'client.key' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "certificate with key",
+			opts: []Option{WithClientCert("bundle.pem", ""), WithLongForm()},
+			want: "curl --cert 'bundle.pem' --request 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "escaped path",
+			opts: []Option{WithClientCert(`C:\certs\client.pem`, "")},
+			want: `curl -E 'C\:\\certs\\client.pem' -X 'GET' 'https://localhost/test'`,
+		},
+		{
+			name: "password",
+			opts: []Option{WithClientCert("client.pem", "client.key"), WithCertPassword("s3cr3t")},
+			want: "export CERT_PASSWORD='********'\n" +
+				"curl -E 'client.pem:'\"${CERT_PASSWORD}\" --key 'client.key' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "password without certificate",
+			opts: []Option{WithCertPassword("s3cr3t")},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "ca certificate",
+			opts: []Option{WithCACert("ca.pem")},
+			want: "curl --cacert 'ca.pem' -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "empty paths",
+			opts: []Option{WithClientCert("", "client.key"), WithCACert("")},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", tt.opts...)
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_Args_certPassword(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test",
+		WithClientCert("client.pem", ""), WithCertPassword("s3cr3t"))
+
+	want := []string{"curl", "-E", "client.pem:s3cr3t", "-X", "GET", "https://localhost/test"}
+	if diff := cmp.Diff(want, c.Args()); diff != "" {
+		t.Errorf("Args() diff = %s", diff)
+	}
+}
+
+func TestWithCertPassword_quoter(t *testing.T) {
+	tests := []struct {
+		name   string
+		quoter Quoter
+		want   string
+	}{
+		{
+			name:   "powershell",
+			quoter: PowerShellQuoter,
+			want:   "$env:CERT_PASSWORD = '********'\ncurl -E \"client.pem:${env:CERT_PASSWORD}\" -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:   "custom",
+			quoter: QuoterFunc(func(s string) string { return "<" + s + ">" }),
+			want:   "export CERT_PASSWORD=<********>\ncurl -E <client.pem:${CERT_PASSWORD}> -X <GET> <https://localhost/test>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test",
+				WithClientCert("client.pem", ""), WithCertPassword("s3cr3t"), WithQuoter(tt.quoter))
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromRequestWithClient_clientCertOverride(t *testing.T) {
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{Certificates: []tls.Certificate{{}}}},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	r, _ := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+
+	c, err := NewFromRequestWithClient(r, client, WithClientCert("client.pem", "client.key"))
+	if err != nil {
+		t.Fatalf("NewFromRequestWithClient() error = %v", err)
+	}
+
+	want := "curl -E 'client.pem' --key 'client.key' -X 'GET' 'https://localhost/test'"
+	if got := c.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if got := c.Validate(); got != nil {
+		t.Errorf("Validate() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,96 @@
+package curling
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+)
+
+// A Trace collects the connection details of a request sent by an HTTP client,
+// to be rendered as comments above the command with [WithTrace].
+// A Trace is safe for concurrent use.
+type Trace struct {
+	mu         sync.Mutex
+	remoteAddr string
+	reused     bool
+	connected  bool
+	tlsVersion uint16
+	protocol   string
+}
+
+// NewTrace returns a copy of r whose context carries an [httptrace.ClientTrace]
+// that records its connection details in the returned [Trace].
+// The Trace is filled in while the client sends the returned request.
+func NewTrace(r *http.Request) (*http.Request, *Trace) {
+	t := &Trace{}
+
+	clientTrace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+
+			t.connected = true
+			t.reused = info.Reused
+			if info.Conn == nil {
+				return
+			}
+
+			t.remoteAddr = info.Conn.RemoteAddr().String()
+			if conn, ok := info.Conn.(*tls.Conn); ok {
+				state := conn.ConnectionState()
+				t.tlsVersion = state.Version
+				t.protocol = state.NegotiatedProtocol
+			}
+		},
+	}
+
+	return r.WithContext(httptrace.WithClientTrace(r.Context(), clientTrace)), t
+}
+
+// comments returns the lines describing the connection, without comment prefix.
+// It returns nil when no connection was obtained.
+func (t *Trace) comments() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.connected {
+		return nil
+	}
+
+	connection := "new"
+	if t.reused {
+		connection = "reused"
+	}
+
+	lines := []string{
+		"Remote address: " + t.remoteAddr,
+		"Connection: " + connection,
+	}
+
+	if t.tlsVersion != 0 {
+		tlsLine := "TLS: " + tls.VersionName(t.tlsVersion)
+		if t.protocol != "" {
+			tlsLine += fmt.Sprintf(" (ALPN %s)", t.protocol)
+		}
+		lines = append(lines, tlsLine)
+	}
+
+	return lines
+}
+
+// traceBlock returns the trace comments of the command, one per line.
+func (c *Command) traceBlock() string {
+	if c.trace == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, line := range c.trace.comments() {
+		fmt.Fprintf(&b, "%s %s\n", c.commentPrefix(), line)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package curling
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewTrace(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := server.Client()
+	remoteAddr := strings.TrimPrefix(server.URL, "https://")
+
+	tests := []struct {
+		name       string
+		connection string
+	}{
+		{
+			name:       "new connection",
+			connection: "new",
+		},
+		{
+			name:       "reused connection",
+			connection: "reused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodGet, server.URL+"/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+
+			r, trace := NewTrace(r)
+
+			c, err := NewFromRequest(r, WithTrace(trace))
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			want := "curl -X 'GET' '" + server.URL + "/test'"
+			if got := c.String(); got != want {
+				t.Errorf("String() before sending = %v, want %v", got, want)
+			}
+
+			resp, err := client.Do(r)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+
+			want = "# Remote address: " + remoteAddr + "\n" +
+				"# Connection: " + tt.connection + "\n" +
+				"# TLS: TLS 1.3\n" +
+				"curl -X 'GET' '" + server.URL + "/test'"
+
+			if got := c.String(); got != want {
+				t.Errorf("String() = %v, want %v", got, want)
+			}
+		})
+	}
+}
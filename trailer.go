@@ -0,0 +1,64 @@
+package curling
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// stripTrailers leaves out the headers cURL manages on its own or can't honor:
+// the TE header, whose transfer codings are requested with --tr-encoding
+// instead, the Trailer header and the request trailers, which cURL can't send.
+// Each decision is reported by Validate. OutputVersion1 renders them as they are.
+func (c *Command) stripTrailers() {
+	if c.version() < OutputVersion2 {
+		return
+	}
+
+	if te := c.request.header.Values("TE"); len(te) > 0 {
+		c.request.header.Del("TE")
+
+		if transferCodings(te) {
+			c.trEncoding = true
+			c.warn(WarningTEHeader, "TE header was left out, transfer codings are requested with --tr-encoding")
+		} else {
+			c.warn(WarningTEHeader, "TE header was left out, cURL accepts trailers without it")
+		}
+	}
+
+	declared := c.request.header.Values("Trailer")
+	c.request.header.Del("Trailer")
+
+	names := make([]string, 0, len(c.request.trailer))
+	for key := range c.request.trailer {
+		names = append(names, http.CanonicalHeaderKey(key))
+	}
+	for _, value := range declared {
+		for _, name := range strings.Split(value, ",") {
+			if name = http.CanonicalHeaderKey(strings.TrimSpace(name)); name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	if len(names) > 0 {
+		slices.Sort(names)
+		c.warn(WarningTrailers, "trailers %s were left out, cURL can't send request trailers", strings.Join(names, ", "))
+	}
+}
+
+// transferCodings reports whether the TE values request a transfer coding,
+// besides the acceptance of trailers.
+func transferCodings(values []string) bool {
+	for _, value := range values {
+		for _, coding := range strings.Split(value, ",") {
+			coding, _, _ = strings.Cut(coding, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding != "" && coding != "trailers" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,89 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCommand_stripTrailers(t *testing.T) {
+	tests := []struct {
+		name         string
+		header       http.Header
+		trailer      http.Header
+		want         string
+		wantWarnings []Warning
+	}{
+		{
+			name:   "no te",
+			header: http.Header{"Accept": {"*/*"}},
+			want:   "curl -X 'POST' 'https://localhost/test' -H 'Accept: */*' -d 'hello'",
+		},
+		{
+			name:   "te trailers",
+			header: http.Header{"Te": {"trailers"}},
+			want:   "curl -X 'POST' 'https://localhost/test' -d 'hello'",
+			wantWarnings: []Warning{
+				{Code: WarningTEHeader, Message: "TE header was left out, cURL accepts trailers without it"},
+			},
+		},
+		{
+			name:   "te transfer codings",
+			header: http.Header{"Te": {"gzip;q=1.0, trailers"}},
+			want:   "curl --tr-encoding -X 'POST' 'https://localhost/test' -d 'hello'",
+			wantWarnings: []Warning{
+				{Code: WarningTEHeader, Message: "TE header was left out, transfer codings are requested with --tr-encoding"},
+			},
+		},
+		{
+			name:    "trailers",
+			header:  http.Header{"Trailer": {"X-Checksum, x-signature"}},
+			trailer: http.Header{"X-Checksum": {"abc"}, "X-Timing": nil},
+			want:    "curl -X 'POST' 'https://localhost/test' -d 'hello'",
+			wantWarnings: []Warning{
+				{Code: WarningTrailers, Message: "trailers X-Checksum, X-Signature, X-Timing were left out, cURL can't send request trailers"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodPost, "https://localhost/test", strings.NewReader("hello"))
+			r.Header = tt.header
+			r.Trailer = tt.trailer
+
+			c, err := NewFromRequest(r, WithOutputVersion(OutputVersion2))
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			if diff := cmp.Diff(tt.wantWarnings, c.Validate()); diff != "" {
+				t.Errorf("Validate() diff = %s", diff)
+			}
+		})
+	}
+}
+
+func TestCommand_stripTrailers_firstVersion(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodPost, "https://localhost/test", strings.NewReader("hello"))
+	r.Header = http.Header{"Te": {"gzip"}, "Trailer": {"X-Checksum"}}
+	r.Trailer = http.Header{"X-Checksum": {"abc"}}
+
+	c, err := NewFromRequest(r, WithOutputVersion(OutputVersion1))
+	if err != nil {
+		t.Fatalf("NewFromRequest() error = %v", err)
+	}
+
+	want := "curl -X 'POST' 'https://localhost/test' -H 'Te: gzip' -H 'Trailer: X-Checksum' -d 'hello'"
+	if got := c.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if warnings := c.Validate(); warnings != nil {
+		t.Errorf("Validate() = %v, want nil", warnings)
+	}
+}
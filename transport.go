@@ -0,0 +1,155 @@
+package curling
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Transport is an [http.RoundTripper] that builds the cURL command of every
+// outgoing request and hands it to callbacks before and after the request is sent,
+// so that HTTP clients can log their traffic as replayable commands.
+//
+// The request sent by Transport always carries the whole body: options such
+// as [WithMaxBodySize] only affect the command.
+// The options of the routes set with [OnHost] and [OnPath] are applied to
+// the requests they match.
+// Requests rejected by the predicate set with [WithPredicate] and requests
+// whose command can't be built are sent without calling the callbacks.
+type Transport struct {
+	// Base is the RoundTripper used to send the requests.
+	// If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Options are the options used to build the commands.
+	Options []Option
+
+	// Before, if not nil, is called with the command before the request is sent.
+	Before func(c *Command)
+
+	// After, if not nil, is called with the command and the outcome of the request.
+	After func(c *Command, resp *http.Response, err error)
+
+	// captureStatus and captureErr select the outcomes whose commands are emitted,
+	// every outcome when both are nil.
+	captureStatus func(code int) bool
+	captureErr    func(err error) bool
+}
+
+// NewTransport returns a [Transport] that sends the requests with base and
+// calls fn with the command of every request before it is sent.
+func NewTransport(base http.RoundTripper, fn func(c *Command), opts ...Option) *Transport {
+	return &Transport{
+		Base:    base,
+		Options: opts,
+		Before:  fn,
+	}
+}
+
+// CaptureOn makes t emit the command of a request only when its outcome is
+// interesting: when status reports true for the response status code, or when
+// err reports true for the error returned by the base RoundTripper.
+// A nil predicate never reports true. The command is held until the outcome
+// is known, then both Before and After are called.
+// CaptureOn returns t, so it can be chained with [NewTransport].
+func (t *Transport) CaptureOn(status func(code int) bool, err func(err error) bool) *Transport {
+	t.captureStatus = status
+	t.captureErr = err
+
+	return t
+}
+
+// RoundTrip implements the [http.RoundTripper] interface.
+// The request is not modified: when it has a body, a copy carrying
+// the bytes read from it is sent instead.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	opts, ok := requestOptions(r, t.Options)
+	if !ok {
+		return t.base().RoundTrip(r)
+	}
+
+	out, snapshot, err := splitRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewFromRequest(snapshot, opts...)
+	if err != nil {
+		return t.base().RoundTrip(out)
+	}
+
+	if !t.capturing() {
+		if t.Before != nil {
+			t.Before(c)
+		}
+
+		resp, err := t.base().RoundTrip(out)
+
+		if t.After != nil {
+			t.After(c, resp, err)
+		}
+
+		return resp, err
+	}
+
+	resp, err := t.base().RoundTrip(out)
+
+	if t.captures(resp, err) {
+		if t.Before != nil {
+			t.Before(c)
+		}
+
+		if t.After != nil {
+			t.After(c, resp, err)
+		}
+	}
+
+	return resp, err
+}
+
+// capturing reports whether the commands are emitted depending on the outcome.
+func (t *Transport) capturing() bool {
+	return t.captureStatus != nil || t.captureErr != nil
+}
+
+// captures reports whether the outcome of a request matches the CaptureOn predicates.
+func (t *Transport) captures(resp *http.Response, err error) bool {
+	if err != nil {
+		return t.captureErr != nil && t.captureErr(err)
+	}
+
+	return t.captureStatus != nil && t.captureStatus(resp.StatusCode)
+}
+
+// base returns the RoundTripper used to send the requests.
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+// splitRequest returns two shallow copies of r, one to send and one to build
+// the command from, each with its own reader over the body of r.
+// If splitRequest can't read the body, it returns an error.
+func splitRequest(r *http.Request) (out, snapshot *http.Request, err error) {
+	out = r.Clone(r.Context())
+	snapshot = r.Clone(r.Context())
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return out, snapshot, nil
+	}
+
+	b, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading bytes from request body: %w", err)
+	}
+
+	out.Body = io.NopCloser(bytes.NewReader(b))
+	snapshot.Body = io.NopCloser(bytes.NewReader(b))
+
+	return out, snapshot, nil
+}
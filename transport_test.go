@@ -0,0 +1,225 @@
+package curling
+
+import (
+	"errors"
+	"github.com/google/go-cmp/cmp"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var events []string
+	transport := &Transport{
+		Options: []Option{WithMaxBodySize(3)},
+		Before: func(c *Command) {
+			events = append(events, "before: "+c.String())
+		},
+		After: func(c *Command, resp *http.Response, err error) {
+			events = append(events, "after: "+resp.Status)
+		},
+	}
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post(server.URL+"/test", "text/plain", strings.NewReader("key=value"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if received != "key=value" {
+		t.Errorf("server received body = %q, want %q", received, "key=value")
+	}
+
+	want := []string{
+		"before: curl -X 'POST' '" + server.URL + "/test' -H 'Content-Type: text/plain' -d 'key'",
+		"after: 201 Created",
+	}
+	if !cmp.Equal(events, want) {
+		t.Errorf("events diff = %v", cmp.Diff(events, want))
+	}
+}
+
+func TestTransport_RoundTrip_error(t *testing.T) {
+	wantErr := errors.New("connection refused")
+
+	var gotErr error
+	var commands []string
+	transport := &Transport{
+		Base: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, wantErr
+		}),
+		After: func(c *Command, resp *http.Response, err error) {
+			commands = append(commands, c.String())
+			gotErr = err
+		},
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if _, err := transport.RoundTrip(r); !errors.Is(err, wantErr) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, wantErr)
+	}
+
+	if !errors.Is(gotErr, wantErr) {
+		t.Errorf("After() error = %v, want %v", gotErr, wantErr)
+	}
+
+	want := []string{"curl -X 'GET' 'https://localhost/test'"}
+	if !cmp.Equal(commands, want) {
+		t.Errorf("commands diff = %v", cmp.Diff(commands, want))
+	}
+}
+
+func TestTransport_RoundTrip_predicate(t *testing.T) {
+	var got []string
+	transport := &Transport{
+		Base: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		Options: []Option{
+			WithPredicate(func(r *http.Request) bool {
+				return r.URL.Path != "/health"
+			}),
+		},
+		Before: func(c *Command) {
+			got = append(got, c.String())
+		},
+	}
+
+	for _, path := range []string{"/health", "/test"} {
+		r, err := http.NewRequest(http.MethodGet, "https://localhost"+path, nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+
+		if _, err := transport.RoundTrip(r); err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+	}
+
+	want := []string{"curl -X 'GET' 'https://localhost/test'"}
+	if !cmp.Equal(got, want) {
+		t.Errorf("commands diff = %v", cmp.Diff(got, want))
+	}
+}
+
+func TestTransport_CaptureOn(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name    string
+		status  func(code int) bool
+		err     func(err error) bool
+		outcome error
+		code    int
+		want    []string
+	}{
+		{
+			name:   "failed status",
+			status: func(code int) bool { return code >= 500 },
+			code:   http.StatusBadGateway,
+			want:   []string{"before", "after"},
+		},
+		{
+			name:   "successful status",
+			status: func(code int) bool { return code >= 500 },
+			code:   http.StatusOK,
+			want:   nil,
+		},
+		{
+			name:    "matching error",
+			status:  func(code int) bool { return code >= 500 },
+			err:     func(err error) bool { return errors.Is(err, errFailed) },
+			outcome: errFailed,
+			want:    []string{"before", "after"},
+		},
+		{
+			name:    "error without predicate",
+			status:  func(code int) bool { return code >= 500 },
+			outcome: errFailed,
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			transport := (&Transport{
+				Base: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+					if tt.outcome != nil {
+						return nil, tt.outcome
+					}
+					return &http.Response{StatusCode: tt.code, Body: http.NoBody}, nil
+				}),
+				Before: func(c *Command) {
+					got = append(got, "before")
+				},
+				After: func(c *Command, resp *http.Response, err error) {
+					got = append(got, "after")
+				},
+			}).CaptureOn(tt.status, tt.err)
+
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+
+			_, _ = transport.RoundTrip(r)
+
+			if !cmp.Equal(got, tt.want) {
+				t.Errorf("callbacks diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	var got []string
+	transport := NewTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		if string(b) != "{}" {
+			t.Errorf("sent body = %q, want %q", b, "{}")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	}), func(c *Command) {
+		got = append(got, c.String())
+	}, WithLongForm())
+
+	r, err := http.NewRequest(http.MethodPut, "https://localhost/test", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if _, err := transport.RoundTrip(r); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	want := []string{"curl --request 'PUT' 'https://localhost/test' --data '{}'"}
+	if !cmp.Equal(got, want) {
+		t.Errorf("commands diff = %v", cmp.Diff(got, want))
+	}
+}
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface.
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,34 @@
+package curling
+
+// The output versions. Each version freezes the rendering rules (ordering,
+// quoting, spacing and the flags chosen) of the cURL command, so that golden
+// tests and log parsers keep working when the formatting improves: new rendering
+// behavior lands under a new version number, which commands opt into.
+const (
+	// OutputVersion1 renders the command as the first releases of the library do.
+	OutputVersion1 = 1
+
+	// OutputVersion2 renders HEAD requests with -I, binary bodies with
+	// --data-binary @body.bin, GET and HEAD bodies with --data-raw, leaves out
+	// the TE header and the trailers, renders fractional timeouts derived from
+	// an [http.Client] and marks truncated header values with their length.
+	// With [WithIdiomaticFlags], it also renders the User-Agent, Referer and
+	// byte Range headers with -A, -e and -r.
+	OutputVersion2 = 2
+
+	// DefaultOutputVersion is the version used when no output version is set.
+	DefaultOutputVersion = OutputVersion1
+
+	// LatestOutputVersion is the newest output version.
+	LatestOutputVersion = OutputVersion2
+)
+
+// version returns the output version the command is rendered with,
+// the default one when the version set isn't supported.
+func (c *Command) version() int {
+	if c.outputVersion <= 0 || c.outputVersion > LatestOutputVersion {
+		return DefaultOutputVersion
+	}
+
+	return c.outputVersion
+}
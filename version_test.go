@@ -0,0 +1,183 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCommand_version(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	tests := []struct {
+		name         string
+		opts         []Option
+		want         int
+		wantWarnings []Warning
+	}{
+		{
+			name: "default version",
+			want: DefaultOutputVersion,
+		},
+		{
+			name: "first version",
+			opts: []Option{WithOutputVersion(OutputVersion1)},
+			want: OutputVersion1,
+		},
+		{
+			name: "second version",
+			opts: []Option{WithOutputVersion(OutputVersion2)},
+			want: OutputVersion2,
+		},
+		{
+			name: "unsupported version",
+			opts: []Option{WithOutputVersion(LatestOutputVersion + 1)},
+			want: DefaultOutputVersion,
+			wantWarnings: []Warning{
+				{
+					Code:    WarningUnsupportedOutputVersion,
+					Message: "output version 3 is not supported, using version 1",
+				},
+			},
+		},
+		{
+			name: "negative version",
+			opts: []Option{WithOutputVersion(-1)},
+			want: DefaultOutputVersion,
+			wantWarnings: []Warning{
+				{
+					Code:    WarningUnsupportedOutputVersion,
+					Message: "output version -1 is not supported, using version 1",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(&http.Request{URL: testUrl}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.version(); got != tt.want {
+				t.Errorf("version() = %v, want %v", got, tt.want)
+			}
+
+			if got := c.Validate(); !cmp.Equal(got, tt.wantWarnings) {
+				t.Errorf("Validate() diff = %v", cmp.Diff(got, tt.wantWarnings))
+			}
+
+			if got := c.String(); got != "curl -X 'GET' 'https://localhost/test'" {
+				t.Errorf("String() = %v", got)
+			}
+		})
+	}
+}
+
+func TestWithOutputVersion_rendering(t *testing.T) {
+	get := func(header http.Header, body string) func(opts ...Option) (*Command, error) {
+		return func(opts ...Option) (*Command, error) {
+			r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+			if err != nil {
+				return nil, err
+			}
+			r.Header = header
+			if body != "" {
+				r.Body = readCloser(body)
+			}
+
+			return NewFromRequest(r, opts...)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		build  func(opts ...Option) (*Command, error)
+		opts   []Option
+		wantV1 string
+		wantV2 string
+	}{
+		{
+			name: "head request",
+			build: func(opts ...Option) (*Command, error) {
+				return NewFromRequest(&http.Request{Method: http.MethodHead, URL: &url.URL{Scheme: "https", Host: "localhost", Path: "test"}}, opts...)
+			},
+			wantV1: "curl -X 'HEAD' 'https://localhost/test'",
+			wantV2: "curl -I 'https://localhost/test'",
+		},
+		{
+			name: "binary body",
+			build: func(opts ...Option) (*Command, error) {
+				return NewFromParts(http.MethodPost, "https://localhost/test", nil, readCloser("\x00\x01"), opts...)
+			},
+			wantV1: "curl -X 'POST' 'https://localhost/test' -d '\x00\x01'",
+			wantV2: "curl -X 'POST' 'https://localhost/test' --data-binary '@body.bin'",
+		},
+		{
+			name:   "get body",
+			build:  get(http.Header{}, "q=1"),
+			wantV1: "curl -X 'GET' 'https://localhost/test' -d 'q=1'",
+			wantV2: "curl -X 'GET' 'https://localhost/test' --data-raw 'q=1'",
+		},
+		{
+			name:   "te header",
+			build:  get(http.Header{"Te": {"gzip"}}, ""),
+			wantV1: "curl -X 'GET' 'https://localhost/test' -H 'Te: gzip'",
+			wantV2: "curl --tr-encoding -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "client timeout",
+			build: func(opts ...Option) (*Command, error) {
+				r, err := http.NewRequest(http.MethodGet, "https://localhost/test", nil)
+				if err != nil {
+					return nil, err
+				}
+
+				return NewFromRequestWithClient(r, &http.Client{Transport: &http.Transport{}, Timeout: 1500 * time.Millisecond}, opts...)
+			},
+			wantV1: "curl -m 2 -L --max-redirs 10 -X 'GET' 'https://localhost/test'",
+			wantV2: "curl -m 1.5 -L --max-redirs 10 -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name:   "idiomatic headers",
+			build:  get(http.Header{"User-Agent": {"test/1.0"}, "Referer": {"https://localhost/"}, "Range": {"bytes=0-9"}}, ""),
+			opts:   []Option{WithIdiomaticFlags()},
+			wantV1: "curl -X 'GET' 'https://localhost/test' -H 'Range: bytes=0-9' -H 'Referer: https://localhost/' -H 'User-Agent: test/1.0'",
+			wantV2: "curl -X 'GET' 'https://localhost/test' -r '0-9' -e 'https://localhost/' -A 'test/1.0'",
+		},
+		{
+			name:   "header truncation",
+			build:  get(http.Header{"X-Long": {strings.Repeat("a", 100)}}, ""),
+			opts:   []Option{WithMaxOutputSize(90)},
+			wantV1: "curl -X 'GET' 'https://localhost/test' -H 'X-Long: aaaaaaaaaaaaaaaaaaaaaaaaaaa[truncated]'",
+			wantV2: "curl -X 'GET' 'https://localhost/test' -H 'X-Long: aaaaaaaaa[truncated, 9 of 100 bytes]'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range []struct {
+				version int
+				want    string
+			}{
+				{OutputVersion1, tt.wantV1},
+				{OutputVersion2, tt.wantV2},
+			} {
+				c, err := tt.build(append(tt.opts, WithOutputVersion(v.version))...)
+				if err != nil {
+					t.Fatalf("build() error = %v", err)
+				}
+
+				if got := c.String(); got != v.want {
+					t.Errorf("version %d: String() = %q, want %q", v.version, got, v.want)
+				}
+			}
+		})
+	}
+}
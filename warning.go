@@ -0,0 +1,184 @@
+package curling
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A WarningCode identifies the kind of a [Warning].
+type WarningCode string
+
+const (
+	// WarningBinaryBody reports a binary body referenced as a file that must be provided.
+	WarningBinaryBody WarningCode = "binary_body"
+
+	// WarningFormDropped reports form values that can't be encoded with the declared content type.
+	WarningFormDropped WarningCode = "form_dropped"
+
+	// WarningUnsupportedOutputVersion reports an output version that the library doesn't provide.
+	WarningUnsupportedOutputVersion WarningCode = "unsupported_output_version"
+
+	// WarningSingleQuotesOnWindows reports single quote escaping used in a Windows shell snippet.
+	WarningSingleQuotesOnWindows WarningCode = "single_quotes_on_windows"
+
+	// WarningUndecodableBody reports a body that can't be decoded with [WithDecodedBody].
+	WarningUndecodableBody WarningCode = "undecodable_body"
+
+	// WarningNonASCIIHeader reports a header value containing non-ASCII characters.
+	WarningNonASCIIHeader WarningCode = "non_ascii_header"
+
+	// WarningCompressedSuppressed reports --compressed left out by [WithIdiomaticFlags].
+	WarningCompressedSuppressed WarningCode = "compressed_suppressed"
+
+	// WarningRedirectMethod reports a redirect chain that cURL follows with another method.
+	WarningRedirectMethod WarningCode = "redirect_method"
+
+	// WarningBodyTruncated reports a body cut to the size set with [WithMaxBodySize].
+	WarningBodyTruncated WarningCode = "body_truncated"
+
+	// WarningOutputTruncated reports a command shrunk to the size set with [WithMaxOutputSize].
+	WarningOutputTruncated WarningCode = "output_truncated"
+
+	// WarningHeaderTruncated reports a header value cut to fit the size set with [WithMaxOutputSize].
+	WarningHeaderTruncated WarningCode = "header_truncated"
+
+	// WarningTEHeader reports a TE header left out, since cURL manages it.
+	WarningTEHeader WarningCode = "te_header"
+
+	// WarningTrailers reports request trailers left out, since cURL can't send them.
+	WarningTrailers WarningCode = "trailers"
+
+	// WarningMethodBody reports a body sent with GET or HEAD.
+	WarningMethodBody WarningCode = "method_body"
+
+	// WarningUnreadableBody reports a body that couldn't be read entirely, of which
+	// the command carries the bytes read before the failure.
+	WarningUnreadableBody WarningCode = "unreadable_body"
+
+	// WarningInvalidCookie reports a cookie whose name is not a valid token.
+	WarningInvalidCookie WarningCode = "invalid_cookie"
+
+	// WarningConfigFile reports a command too long for a command line, whose
+	// options were written to a config file with [WithAutoConfigFile].
+	WarningConfigFile WarningCode = "config_file"
+
+	// WarningConfigFilePlaceholders reports a command too long for a command line,
+	// left as is by [WithAutoConfigFile] because a config file can't expand its placeholders.
+	WarningConfigFilePlaceholders WarningCode = "config_file_placeholders"
+
+	// WarningBodyDecoder reports a body that the decoder registered for its
+	// content type with [RegisterBodyDecoder] can't decode.
+	WarningBodyDecoder WarningCode = "body_decoder"
+
+	// WarningAuthScheme reports an Authorization header answering a server challenge,
+	// which must be replayed with [WithAuthScheme].
+	WarningAuthScheme WarningCode = "auth_scheme"
+
+	// WarningManagedHeaders reports the headers managed by cURL left out with [WithStripManagedHeaders].
+	WarningManagedHeaders WarningCode = "managed_headers"
+
+	// WarningTLSInMemory reports TLS material held in memory, referenced as files that must be provided.
+	WarningTLSInMemory WarningCode = "tls_in_memory"
+
+	// WarningOutputDirIgnored reports an output directory set with [WithOutputDir]
+	// left out, since the command doesn't save the response body to a file.
+	WarningOutputDirIgnored WarningCode = "output_dir_ignored"
+)
+
+// A Warning describes why a command may not be a faithful replay of the request.
+type Warning struct {
+	// Code identifies the kind of finding.
+	Code WarningCode `json:"code"`
+
+	// Message is a human-readable description of the finding.
+	Message string `json:"message"`
+}
+
+// String returns the warning message.
+func (w Warning) String() string {
+	return w.Message
+}
+
+// Validate returns the findings collected while building the command.
+// An empty result means the command is expected to replay the request faithfully.
+func (c *Command) Validate() []Warning {
+	if len(c.warnings) == 0 {
+		return nil
+	}
+
+	warnings := make([]Warning, len(c.warnings))
+	copy(warnings, c.warnings)
+
+	return warnings
+}
+
+// warn records a finding about the command.
+func (c *Command) warn(code WarningCode, format string, a ...any) {
+	c.warnings = append(c.warnings, Warning{
+		Code:    code,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+// validate collects the findings about the parsed request and the supplied options.
+func (c *Command) validate() {
+	if c.outputVersion < 0 || c.outputVersion > LatestOutputVersion {
+		c.warn(WarningUnsupportedOutputVersion, "output version %d is not supported, using version %d", c.outputVersion, DefaultOutputVersion)
+		c.outputVersion = DefaultOutputVersion
+	}
+
+	if c.request.hasBody && !isText(c.request.body) && !c.binaryBodyFile && !c.bodyToFile {
+		if c.version() >= OutputVersion2 {
+			c.warn(WarningBinaryBody, "body contains binary data, save it as %s to replay the command", binaryBodyFileName)
+		} else {
+			c.warn(WarningBinaryBody, "body contains binary data that may be altered by the shell")
+		}
+	}
+
+	if c.certInMemory {
+		c.warn(WarningTLSInMemory, "client certificate is held in memory, save it as %s and %s to replay the command", c.certFile, c.keyFile)
+	}
+
+	if c.caCertInMemory {
+		c.warn(WarningTLSInMemory, "root CAs are held in memory, save them as %s to replay the command", c.caCertFile)
+	}
+
+	if c.outputDir != "" && c.outputFile == "" && !c.remoteName {
+		c.warn(WarningOutputDirIgnored, "output directory %q was left out, set an output file or the remote name to use it", c.outputDir)
+	}
+
+	if c.request.readErr != nil {
+		c.warn(WarningUnreadableBody, "body was read partially, %d bytes: %v", len(c.request.body), c.request.readErr)
+		c.issue(c.request.readErr)
+	}
+
+	if len(c.request.body) < c.request.bodySize {
+		c.warn(WarningBodyTruncated, "body truncated to %d of %d bytes", len(c.request.body), c.request.bodySize)
+	}
+
+	c.validateMethodBody()
+
+	if c.request.formDropped {
+		c.warn(WarningFormDropped, "form values were left out, the declared content type is not url-encoded")
+	}
+
+	if c.useMultiLine && c.lineContinuation == lineContinuationWindows && !c.useDoubleQuotes && c.quoter == nil {
+		c.warn(WarningSingleQuotesOnWindows, "the Windows shell does not support single quotes, use double quotes")
+	}
+}
+
+// isText reports whether b is valid UTF-8 without control characters other than whitespace.
+func isText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+
+	for _, r := range string(b) {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,172 @@
+package curling
+
+import (
+	"github.com/google/go-cmp/cmp"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_Validate(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	type args struct {
+		r    *http.Request
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Warning
+	}{
+		{
+			name: "no findings",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Body:   readCloser("{\n\t\"key\": \"value\"\n}"),
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "binary body",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Body:   readCloser("\x00\x01\xff"),
+				},
+			},
+			want: []Warning{
+				{
+					Code:    WarningBinaryBody,
+					Message: "body contains binary data that may be altered by the shell",
+				},
+			},
+		},
+		{
+			name: "binary body with second version",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Body:   readCloser("\x00\x01\xff"),
+				},
+				opts: []Option{WithOutputVersion(OutputVersion2)},
+			},
+			want: []Warning{
+				{
+					Code:    WarningBinaryBody,
+					Message: "body contains binary data, save it as body.bin to replay the command",
+				},
+			},
+		},
+		{
+			name: "windows multiline with double quotes",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+				opts: []Option{WithWindowsMultiLine(), WithDoubleQuotes()},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.args.r, tt.args.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.Validate(); !cmp.Equal(got, tt.want) {
+				t.Errorf("Validate() diff = %v", cmp.Diff(got, tt.want))
+			}
+		})
+	}
+}
+
+func TestCommand_String_inlineWarnings(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "disabled",
+			opts: []Option{WithWindowsMultiLine()},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "windows shell",
+			opts: []Option{WithWindowsMultiLine(), WithInlineWarnings()},
+			want: "REM WARNING: the Windows shell does not support single quotes, use double quotes\n" +
+				"curl -X 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "no findings",
+			opts: []Option{WithInlineWarnings()},
+			want: "curl -X 'GET' 'https://localhost/test'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(&http.Request{URL: testUrl}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isText(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+		want bool
+	}{
+		{
+			name: "empty",
+			b:    nil,
+			want: true,
+		},
+		{
+			name: "whitespace",
+			b:    []byte("a\tb\r\n"),
+			want: true,
+		},
+		{
+			name: "control character",
+			b:    []byte("a\x1bb"),
+			want: false,
+		},
+		{
+			name: "invalid utf-8",
+			b:    []byte{0xff, 0xfe},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isText(tt.b); got != tt.want {
+				t.Errorf("isText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package curling
+
+import (
+	"strings"
+)
+
+// The wget flags the library can emit.
+var (
+	wgetFlagQuiet              = flag{short: "-q", long: "--quiet"}
+	wgetFlagTimeout            = flag{short: "-T", long: "--timeout"}
+	wgetFlagNoCheckCertificate = flag{long: "--no-check-certificate"}
+	wgetFlagCompression        = flag{long: "--compression"}
+	wgetFlagOutputDocument     = flag{short: "-O", long: "--output-document"}
+	wgetFlagMethod             = flag{long: "--method"}
+	wgetFlagHeader             = flag{long: "--header"}
+	wgetFlagBodyData           = flag{long: "--body-data"}
+)
+
+// ToWget returns a wget command equivalent to the cURL command.
+//
+// The command is rendered from the same parsed request and honors the form,
+// quoting, multiline, inline warnings and placeholders options.
+// The response is written to standard output, as cURL does.
+// Note that wget follows redirects by default.
+func (c *Command) ToWget() string {
+	s := []string{"wget"}
+
+	if c.silent {
+		s = append(s, c.option(wgetFlagQuiet))
+	}
+
+	if c.requestTimeout > 0 {
+		s = append(s, c.option(wgetFlagTimeout), formatSeconds(c.requestTimeout))
+	}
+
+	if c.insecure {
+		s = append(s, c.option(wgetFlagNoCheckCertificate))
+	}
+
+	if c.compressed {
+		s = append(s, c.option(wgetFlagCompression)+"=auto")
+	}
+
+	s = append(s,
+		c.option(wgetFlagOutputDocument), "-",
+		c.option(wgetFlagMethod), c.escape(c.request.method),
+		c.escape(c.request.url.String()),
+	)
+
+	tokens := []string{strings.Join(s, " ")}
+
+	for _, header := range c.request.headerFields() {
+		tokens = append(tokens, c.option(wgetFlagHeader)+" "+c.escape(header.String()))
+	}
+
+	if c.request.hasBody {
+		tokens = append(tokens, c.option(wgetFlagBodyData)+" "+c.escape(string(c.request.body)))
+	}
+
+	return c.decorate(c.join(tokens))
+}
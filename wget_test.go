@@ -0,0 +1,90 @@
+package curling
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCommand_ToWget(t *testing.T) {
+	testUrl := &url.URL{
+		Scheme: "https",
+		Host:   "localhost",
+		Path:   "test",
+	}
+
+	type args struct {
+		r    *http.Request
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "empty method",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+			},
+			want: "wget -O - --method 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "headers and body",
+			args: args{
+				r: &http.Request{
+					Method: http.MethodPost,
+					URL:    testUrl,
+					Header: http.Header{"X-Key": {"1"}},
+					Body:   readCloser("key=value"),
+				},
+			},
+			want: "wget -O - --method 'POST' 'https://localhost/test' --header 'X-Key: 1' --body-data 'key=value'",
+		},
+		{
+			name: "short options",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+				opts: []Option{WithSilent(), WithRequestTimeout(5), WithInsecure(), WithCompression()},
+			},
+			want: "wget -q -T 5 --no-check-certificate --compression=auto -O - --method 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "long options",
+			args: args{
+				r: &http.Request{
+					URL: testUrl,
+				},
+				opts: []Option{WithSilent(), WithRequestTimeout(5), WithLongForm()},
+			},
+			want: "wget --quiet --timeout 5 --output-document - --method 'GET' 'https://localhost/test'",
+		},
+		{
+			name: "multiline and double quotes",
+			args: args{
+				r: &http.Request{
+					URL:    testUrl,
+					Header: http.Header{"X-Key": {"1"}},
+				},
+				opts: []Option{WithMultiLine(), WithDoubleQuotes()},
+			},
+			want: "wget -O - --method \"GET\" \"https://localhost/test\" \\\n--header \"X-Key: 1\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(tt.args.r, tt.args.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			if got := c.ToWget(); got != tt.want {
+				t.Errorf("ToWget() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package curling
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteTo writes the command returned by String to w, implementing [io.WriterTo].
+// The cURL command is written token by token, without building the whole string,
+// so large commands can be streamed to a log or a file: with a multiline option,
+// each line is written on its own. Other renderers write their whole output.
+func (c *Command) WriteTo(w io.Writer) (int64, error) {
+	if !c.rendersCurl() {
+		n, err := io.WriteString(w, c.String())
+		return int64(n), err
+	}
+
+	var written int64
+	write := func(s string) error {
+		n, err := io.WriteString(w, s)
+		written += int64(n)
+		return err
+	}
+
+	if err := write(c.decorate("")); err != nil {
+		return written, err
+	}
+
+	c.ensureConstructed()
+
+	separator := c.tokenSeparator()
+	for i, token := range c.tokens {
+		if i == 0 {
+			token = strings.TrimLeftFunc(token, unicode.IsSpace)
+		} else if err := write(separator); err != nil {
+			return written, err
+		}
+
+		if i == len(c.tokens)-1 {
+			token = strings.TrimRightFunc(token, unicode.IsSpace)
+		}
+
+		if err := write(token); err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,104 @@
+package curling
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCommand_WriteTo(t *testing.T) {
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL: &url.URL{
+			Scheme: "https",
+			Host:   "localhost",
+			Path:   "test",
+		},
+		Header: http.Header{"X-Key": {"1"}, "X-Other": {"2"}},
+		Body:   readCloser("key=value"),
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "single line",
+		},
+		{
+			name: "multiline",
+			opts: []Option{WithMultiLine(), WithSilent()},
+		},
+		{
+			name: "flag grouping with comments",
+			opts: []Option{WithMultiLine(), WithFlagGrouping(), WithInsecure(), WithBodyKindComment()},
+		},
+		{
+			name: "line continuation",
+			opts: []Option{WithLineContinuation("")},
+		},
+		{
+			name: "placeholders",
+			opts: []Option{WithPlaceholders(map[string]string{"HOST": "localhost"})},
+		},
+		{
+			name: "other renderer",
+			opts: []Option{WithRenderer(Fetch)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromRequest(r, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewFromRequest() error = %v", err)
+			}
+
+			var b strings.Builder
+			n, err := c.WriteTo(&b)
+			if err != nil {
+				t.Fatalf("WriteTo() error = %v", err)
+			}
+
+			want := c.String()
+			if got := b.String(); got != want {
+				t.Errorf("WriteTo() wrote %q, want %q", got, want)
+			}
+			if n != int64(len(want)) {
+				t.Errorf("WriteTo() = %d, want %d", n, len(want))
+			}
+		})
+	}
+}
+
+// limitedWriter fails once more than n bytes are written.
+type limitedWriter struct {
+	n int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		written := w.n
+		w.n = 0
+		return written, errors.New("writer is full")
+	}
+
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestCommand_WriteTo_error(t *testing.T) {
+	c := mustNewFromRequest(t, http.MethodGet, "https://localhost/test", WithMultiLine())
+	c.tokens = append(c.tokens, "-H 'X-Key: 1'")
+
+	for _, limit := range []int{0, 10, len(c.tokens[0]) + 1, len(c.String()) - 1} {
+		n, err := c.WriteTo(&limitedWriter{n: limit})
+		if err == nil {
+			t.Errorf("WriteTo() with limit %d error = nil, want error", limit)
+		}
+		if n != int64(limit) {
+			t.Errorf("WriteTo() with limit %d = %d, want %d", limit, n, limit)
+		}
+	}
+}